@@ -0,0 +1,78 @@
+// Command export-users writes users as CSV or NDJSON to a file or standard output, one at a
+// time, with the same filters as GET /users.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/wansanjou/backend-exercise-user-api/config"
+	"github.com/wansanjou/backend-exercise-user-api/infrastructures"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/services"
+	"github.com/wansanjou/backend-exercise-user-api/internal/repositories"
+)
+
+func main() {
+	config.Init()
+	out := flag.String("out", "-", "file to write, - for standard output")
+	format := flag.String("format", domains.BulkCSV, "csv or ndjson")
+	fields := flag.String("fields", strings.Join(domains.DefaultUserExportFields, ","), "comma-separated fields to export")
+	var q domains.FindAllUsers
+	flag.StringVar(&q.Name, "name", "", "only users whose name starts with this")
+	flag.StringVar(&q.Email, "email", "", "only users whose email starts with this")
+	flag.StringVar(&q.Status, "status", "", "only users with this status: active, suspended, frozen, closed or deleted")
+	flag.BoolVar(&q.IncludeDeleted, "include-deleted", false, "include deleted users")
+	flag.Func("attr", "only users whose custom attribute path=value, e.g. loyalty.tier=gold; may be repeated", func(s string) error {
+		path, value, ok := strings.Cut(s, "=")
+		if !ok {
+			return errors.New("want path=value")
+		}
+		if q.Attr == nil {
+			q.Attr = map[string]string{}
+		}
+		q.Attr[path] = value
+		return nil
+	})
+	flag.Parse()
+
+	selected, err := domains.ParseUserExportFields(*fields)
+	if err != nil {
+		log.Fatalf("export users: %v", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "-" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatalf("export users: %v", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	buf := bufio.NewWriter(w)
+
+	ctx := context.Background()
+	pii := repositories.NewPIICipher(infrastructures.EncryptionKeys())
+	db := infrastructures.NewMongoDB(pii.Registry())
+	defer db.Disconnect(ctx)
+
+	ur := repositories.NewUserRepository(db, config.Get().Mongo.Database, pii)
+	fr := repositories.NewFXRepository(db, config.Get().Mongo.Database)
+	us := services.NewUserService(ur, fr)
+
+	n, err := us.ExportUsers(ctx, q, *format, selected, buf)
+	if err == nil {
+		err = buf.Flush()
+	}
+	if err != nil {
+		log.Fatalf("export users: %v", err)
+	}
+	log.Printf("exported %d users", n)
+}
@@ -0,0 +1,77 @@
+// Command import-users creates users from a CSV or NDJSON file, or standard input, and
+// prints a line for every row that was not created. Without -apply it is a dry run that
+// only checks the rows. It exits with status 1 if any row failed.
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/wansanjou/backend-exercise-user-api/config"
+	"github.com/wansanjou/backend-exercise-user-api/infrastructures"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/services"
+	"github.com/wansanjou/backend-exercise-user-api/internal/repositories"
+)
+
+func main() {
+	config.Init()
+	// config.Init keeps the server on one CPU; an import spends its time hashing passwords
+	// and should use them all.
+	runtime.GOMAXPROCS(runtime.NumCPU())
+	file := flag.String("file", "-", "file to import, - for standard input")
+	format := flag.String("format", "", "csv or ndjson; taken from the file extension if not given")
+	apply := flag.Bool("apply", false, "create the users instead of only checking them")
+	flag.Parse()
+
+	var in io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Fatalf("import users: %v", err)
+		}
+		defer f.Close()
+		in = f
+		if *format == "" {
+			*format = strings.TrimPrefix(filepath.Ext(*file), ".")
+		}
+	}
+	if *format == "" {
+		*format = domains.BulkCSV
+	}
+
+	ctx := context.Background()
+	pii := repositories.NewPIICipher(infrastructures.EncryptionKeys())
+	db := infrastructures.NewMongoDB(pii.Registry())
+	defer db.Disconnect(ctx)
+
+	ur := repositories.NewUserRepository(db, config.Get().Mongo.Database, pii)
+	fr := repositories.NewFXRepository(db, config.Get().Mongo.Database)
+	us := services.NewUserService(ur, fr)
+
+	report, err := us.ImportUsers(ctx, *format, in, !*apply)
+	if report != nil {
+		for _, row := range report.Errors {
+			for _, f := range row.Errors {
+				log.Printf("line %d: %s: %s (%s)", row.Line, f.Field, f.Message, f.Code)
+			}
+		}
+		verb := "would create"
+		if *apply {
+			verb = "created"
+		}
+		log.Printf("read %d rows, %s %d users, %d failed", report.Rows, verb, report.Created, report.Failed)
+	}
+	if err != nil {
+		log.Fatalf("import users: %v", err)
+	}
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
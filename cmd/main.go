@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -15,51 +16,149 @@ import (
 	"github.com/wansanjou/backend-exercise-user-api/infrastructures"
 	"github.com/wansanjou/backend-exercise-user-api/internal/core/services"
 	handlers "github.com/wansanjou/backend-exercise-user-api/internal/handlers/http"
+	"github.com/wansanjou/backend-exercise-user-api/internal/handlers/jobs"
 	"github.com/wansanjou/backend-exercise-user-api/internal/repositories"
+	"github.com/wansanjou/backend-exercise-user-api/logging"
+	"github.com/wansanjou/backend-exercise-user-api/middleware"
 )
 
 func main() {
 	config.Init()
-	db := infrastructures.NewMongoDB()
-	r := gin.Default()
+	logger, err := logging.New(os.Stderr, logging.Options(config.Get().Logging))
+	if err != nil {
+		log.Fatalf("logging: %v", err)
+	}
+	slog.SetDefault(logger)
+	pii := repositories.NewPIICipher(infrastructures.EncryptionKeys())
+	db := infrastructures.NewMongoDB(pii.Registry())
+	r := gin.New()
+	// Lets services reach the request's logger, and its ID, through the gin.Context.
+	r.ContextWithFallback = true
+	r.Use(gin.Recovery(), middleware.RequestID(), middleware.LoggingMiddleware(), middleware.ErrorHandler())
 
-	ur := repositories.NewUserRepository(db, config.Get().Mongo.Database)
-	us := services.NewUserService(ur)
-	uh := handlers.NewUserHandler(us)
+	if err := services.LoadAttributeSchemas(); err != nil {
+		log.Fatalf("users.attributeSchemas: %v", err)
+	}
+	if err := services.CheckFees(); err != nil {
+		log.Fatalf("fees: %v", err)
+	}
+	if err := services.CheckTransferTiers(); err != nil {
+		log.Fatalf("transfer: %v", err)
+	}
+
+	ur := repositories.NewUserRepository(db, config.Get().Mongo.Database, pii)
+	fr := repositories.NewFXRepository(db, config.Get().Mongo.Database)
+	us := services.NewUserService(ur, fr)
+	lookups := handlers.NewLookupLimiter(config.Get().Recipients.LookupsPerHour)
+	uh := handlers.NewUserHandler(us, lookups, config.Get().Users.AttributeWriters)
+
+	rcs := services.NewRecipientService(ur)
+	rch := handlers.NewRecipientHandler(rcs, lookups)
+
+	fs := services.NewFXService(fr, ur)
+	fh := handlers.NewFXHandler(fs)
+	if path := config.Get().FX.RatesFile; path != "" {
+		added, err := services.LoadRatesFile(context.Background(), fs, path)
+		if err != nil {
+			log.Fatalf("load fx rates from %s: %v", path, err)
+		}
+		slog.Info("loaded fx rates", "added", added, "path", path)
+	}
 
 	as := services.NewAuthService(ur)
 	ah := handlers.NewAuthHandler(as)
 
+	hr := repositories.NewHoldRepository(db, config.Get().Mongo.Database)
+	hs := services.NewHoldService(hr, ur)
+	hh := handlers.NewHoldHandler(hs)
+
+	tr := repositories.NewTransferRepository(db, config.Get().Mongo.Database)
+	ts := services.NewTransferService(tr, ur)
+	th := handlers.NewTransferHandler(ts)
+
+	sr := repositories.NewScheduledTransferRepository(db, config.Get().Mongo.Database)
+	ss := services.NewScheduledTransferService(sr, ur)
+	sh := handlers.NewScheduledTransferHandler(ss)
+
+	rr := repositories.NewReconciliationRepository(db, config.Get().Mongo.Database)
+	rs := services.NewReconciliationService(rr)
+
+	str := repositories.NewStatementRepository(db, config.Get().Mongo.Database)
+	sts := services.NewStatementService(str, ur)
+	sth := handlers.NewStatementHandler(sts)
+
+	pr := repositories.NewPrivacyRepository(db, config.Get().Mongo.Database)
+	ps := services.NewPrivacyService(pr, ur)
+	ph := handlers.NewPrivacyHandler(ps)
+
 	api := r.Group("/api/v1")
 
 	uh.UserRoutes(api)
 	ah.AuthRoutes(api)
+	hh.HoldRoutes(api)
+	th.TransferRoutes(api)
+	sh.ScheduledTransferRoutes(api)
+	sth.StatementRoutes(api)
+	fh.FXRoutes(api)
+	rch.RecipientRoutes(api)
+	ph.PrivacyRoutes(api)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	var wg sync.WaitGroup
 
-	// Background goroutine to count users
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		ticker := time.NewTicker(10 * time.Second)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ctx.Done():
-				log.Println("Stopping user count background job...")
-				return
-			case <-ticker.C:
-				count, err := us.CountUsers(context.Background())
-				if err != nil {
-					log.Printf("failed to count users: %v", err)
-					continue
-				}
-				log.Printf("Total users in DB: %d", count)
+	runner := jobs.NewRunner(
+		jobs.Job{Name: "user count", Interval: 10 * time.Second, Run: func(ctx context.Context) error {
+			count, err := us.CountUsers(ctx)
+			if err != nil {
+				return err
 			}
-		}
-	}()
+			logging.FromContext(ctx).Debug("counted users", "total", count)
+			return nil
+		}},
+		jobs.Job{Name: "deleted user emails", Interval: time.Hour, Run: func(ctx context.Context) error {
+			released, err := us.ReleaseDeletedEmails(ctx)
+			if released > 0 {
+				logging.FromContext(ctx).Info("released the emails of deleted users", "users", released)
+			}
+			return err
+		}},
+		jobs.Job{Name: "pii re-encryption", Interval: 10 * time.Minute, Run: func(ctx context.Context) error {
+			updated, err := us.ReencryptUsers(ctx)
+			if updated > 0 {
+				logging.FromContext(ctx).Info("re-encrypted personal data", "users", updated)
+			}
+			return err
+		}},
+		jobs.Job{Name: "hold sweeper", Interval: 30 * time.Second, Run: func(ctx context.Context) error {
+			released, err := hs.ReleaseExpiredHolds(ctx)
+			if released > 0 {
+				logging.FromContext(ctx).Info("released expired holds", "holds", released)
+			}
+			return err
+		}},
+		jobs.Job{Name: "scheduled transfers", Interval: 15 * time.Second, Run: func(ctx context.Context) error {
+			ran, err := ss.RunDueTransfers(ctx)
+			if ran > 0 {
+				logging.FromContext(ctx).Info("ran scheduled transfers", "transfers", ran)
+			}
+			return err
+		}},
+		jobs.Job{Name: "monthly statements", Interval: time.Hour, Run: func(ctx context.Context) error {
+			generated, err := sts.GenerateMonthlyStatements(ctx, time.Now())
+			if generated > 0 {
+				logging.FromContext(ctx).Info("generated monthly statements", "statements", generated)
+			}
+			return err
+		}},
+		jobs.Job{Name: "balance reconciliation", Interval: time.Hour, Run: func(ctx context.Context) error {
+			run, err := rs.Reconcile(ctx, config.Get().Reconciliation.FreezeOnDrift)
+			if err == nil && run.Mismatched > 0 {
+				logging.FromContext(ctx).Warn("reconciliation found drifted balances", "runId", run.ID.Hex(), "accounts", run.Mismatched)
+			}
+			return err
+		}},
+	)
+	runner.Start(ctx, &wg)
 
 	httpServer := &http.Server{
 		Addr:    ":8080",
@@ -68,7 +167,7 @@ func main() {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		log.Println("HTTP server listening on :8080")
+		slog.Info("HTTP server listening", "addr", httpServer.Addr)
 		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("HTTP serve failed: %v", err)
 		}
@@ -77,7 +176,7 @@ func main() {
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
 	<-stop
-	log.Println("Received shutdown signal...")
+	slog.Info("received shutdown signal")
 
 	// Start graceful shutdown
 	cancel()
@@ -89,5 +188,5 @@ func main() {
 	}
 
 	wg.Wait()
-	log.Println("Server gracefully stopped.")
+	slog.Info("server gracefully stopped")
 }
@@ -0,0 +1,55 @@
+// Command migrate-emails normalizes stored emails and switches users to a case-insensitive
+// unique email index. Without -apply it only reports what would change. It exits with
+// status 1 while any accounts still share an email that differs only by case.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/wansanjou/backend-exercise-user-api/config"
+	"github.com/wansanjou/backend-exercise-user-api/infrastructures"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/services"
+	"github.com/wansanjou/backend-exercise-user-api/internal/repositories"
+)
+
+func main() {
+	config.Init()
+	apply := flag.Bool("apply", false, "save normalized emails and build the case-insensitive index")
+	flag.Parse()
+
+	ctx := context.Background()
+	pii := repositories.NewPIICipher(infrastructures.EncryptionKeys())
+	db := infrastructures.NewMongoDB(pii.Registry())
+	defer db.Disconnect(ctx)
+
+	ur := repositories.NewUserRepository(db, config.Get().Mongo.Database, pii)
+	fr := repositories.NewFXRepository(db, config.Get().Mongo.Database)
+	us := services.NewUserService(ur, fr)
+
+	report, err := us.MigrateEmails(ctx, *apply)
+	if err != nil {
+		log.Fatalf("migrate emails: %v", err)
+	}
+
+	for _, group := range report.Collisions {
+		log.Printf("collision: %d accounts share an email:", len(group))
+		for _, r := range group {
+			log.Printf("  user %s: %s", r.UserID.Hex(), r.Email)
+		}
+	}
+	for _, r := range report.Invalid {
+		log.Printf("user %s: email %q cannot be normalized", r.UserID.Hex(), r.Email)
+	}
+	verb := "would normalize"
+	if *apply {
+		verb = "normalized"
+	}
+	log.Printf("checked %d users, %s %d emails, %d collisions, %d invalid, index created=%t",
+		report.Checked, verb, report.Normalized, len(report.Collisions), len(report.Invalid), report.IndexCreated)
+	if len(report.Collisions) > 0 {
+		os.Exit(1)
+	}
+}
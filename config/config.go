@@ -11,10 +11,20 @@ import (
 )
 
 type Config struct {
-	Server Server
-	Mongo  Mongo
-	Bcrypt Bcrypt
-	JWT    JWT
+	Server   Server
+	Mongo    Mongo
+	Bcrypt   Bcrypt
+	JWT      JWT
+	Transfer Transfer
+	Fees     Fees
+
+	Reconciliation Reconciliation
+	Statements     Statements
+	FX             FX
+	Recipients     Recipients
+	Users          Users
+	Encryption     Encryption
+	Logging        Logging
 }
 
 type Server struct {
@@ -36,6 +46,97 @@ type JWT struct {
 	ExpiresIn string `mapstructure:"expiresIn"`
 }
 
+type Transfer struct {
+	DefaultTier    string                    `mapstructure:"defaultTier"`
+	Tiers          map[string]TransferLimits `mapstructure:"tiers"`
+	ReversalPolicy string                    `mapstructure:"reversalPolicy"`
+}
+
+type TransferLimits struct {
+	MaxPerTransaction   float64 `mapstructure:"maxPerTransaction"`
+	DailyOutgoing       float64 `mapstructure:"dailyOutgoing"`
+	MonthlyOutgoing     float64 `mapstructure:"monthlyOutgoing"`
+	MaxTransfersPerHour int     `mapstructure:"maxTransfersPerHour"`
+}
+
+type Fees struct {
+	HouseAccountID string    `mapstructure:"houseAccountId" envconfig:"FEES_HOUSE_ACCOUNT_ID"`
+	Rules          []FeeRule `mapstructure:"rules"`
+}
+
+type FeeRule struct {
+	Tier     string       `mapstructure:"tier"`
+	Currency string       `mapstructure:"currency"`
+	Type     string       `mapstructure:"type"`
+	Amount   float64      `mapstructure:"amount"`
+	Percent  float64      `mapstructure:"percent"`
+	Min      float64      `mapstructure:"min"`
+	Max      float64      `mapstructure:"max"`
+	Brackets []FeeBracket `mapstructure:"brackets"`
+}
+
+type FeeBracket struct {
+	UpTo    float64 `mapstructure:"upTo"`
+	Flat    float64 `mapstructure:"flat"`
+	Percent float64 `mapstructure:"percent"`
+}
+
+type Reconciliation struct {
+	PageSize      int  `mapstructure:"pageSize"`
+	FreezeOnDrift bool `mapstructure:"freezeOnDrift"`
+}
+
+type Statements struct {
+	Formats []string `mapstructure:"formats"`
+}
+
+type Recipients struct {
+	LookupsPerHour int `mapstructure:"lookupsPerHour"`
+}
+
+type Users struct {
+	// DeletedEmailRetentionDays is how long a deleted user keeps their email; after that
+	// it is released so the address can sign up again.
+	DeletedEmailRetentionDays int `mapstructure:"deletedEmailRetentionDays"`
+	DefaultPageSize           int `mapstructure:"defaultPageSize"`
+	MaxPageSize               int `mapstructure:"maxPageSize"`
+	// TextSearch allows ?search= on GET /users, backed by a text index on names.
+	TextSearch bool `mapstructure:"textSearch"`
+	// ImportBatchSize is how many rows of a bulk import are inserted at a time.
+	ImportBatchSize int `mapstructure:"importBatchSize"`
+	// ImportWorkers is how many passwords a bulk import hashes at once; 0 means one per CPU
+	// the process may use.
+	ImportWorkers int `mapstructure:"importWorkers"`
+	// AttributeSchemas holds a JSON Schema for each namespace of custom user attributes.
+	// Attributes are checked against it on every write; a namespace not listed cannot be set.
+	AttributeSchemas map[string]string `mapstructure:"attributeSchemas"`
+	// AttributeWriters lists the roles besides admin that may write each namespace; one
+	// not listed is written by admins only.
+	AttributeWriters map[string][]string `mapstructure:"attributeWriters"`
+}
+
+type Encryption struct {
+	// KeyFile holds the keys personal data is encrypted with at rest; encryption is off
+	// while it is empty.
+	KeyFile string `mapstructure:"keyFile" envconfig:"ENCRYPTION_KEY_FILE"`
+	// ReencryptBatchSize is how many users the re-encryption job updates at a time.
+	ReencryptBatchSize int `mapstructure:"reencryptBatchSize"`
+}
+
+// Logging mirrors logging.Options.
+type Logging struct {
+	Level  string `mapstructure:"level" envconfig:"LOG_LEVEL"`
+	Format string `mapstructure:"format" envconfig:"LOG_FORMAT"`
+	// RedactKeys are the keys whose values never reach the logs; see logging.Redactor.
+	RedactKeys []string `mapstructure:"redactKeys"`
+}
+
+type FX struct {
+	Currencies      []string `mapstructure:"currencies"`
+	QuoteTTLSeconds int      `mapstructure:"quoteTtlSeconds"`
+	RatesFile       string   `mapstructure:"ratesFile" envconfig:"FX_RATES_FILE"`
+}
+
 var cfg Config
 
 func Init() {
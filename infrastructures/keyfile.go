@@ -0,0 +1,122 @@
+package infrastructures
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports"
+)
+
+// KeyFile is a ports.KeyProvider whose keys are read from a local JSON file:
+//
+//	{
+//	  "activeKey": "2026-10",
+//	  "keys": {"2026-10": "<base64, 32 bytes>", "2026-01": "<base64, 32 bytes>"},
+//	  "indexKey": "<base64, 32 bytes>"
+//	}
+//
+// To rotate, add a key, make it active and restart; older keys stay until the
+// re-encryption job has moved everything off them. The index key cannot be rotated this
+// way, since every blind index would change with it.
+type KeyFile struct {
+	active   string
+	keys     map[string]cipher.AEAD
+	indexKey []byte
+}
+
+var _ ports.KeyProvider = (*KeyFile)(nil)
+
+func LoadKeyFile(path string) (*KeyFile, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		ActiveKey string            `json:"activeKey"`
+		Keys      map[string]string `json:"keys"`
+		IndexKey  string            `json:"indexKey"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("key file %s: %w", path, err)
+	}
+
+	kf := &KeyFile{active: doc.ActiveKey, keys: make(map[string]cipher.AEAD, len(doc.Keys))}
+	for id, encoded := range doc.Keys {
+		key, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key file %s: key %s: %w", path, id, err)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		if kf.keys[id], err = cipher.NewGCM(block); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := kf.keys[kf.active]; !ok {
+		return nil, fmt.Errorf("key file %s: active key %q is not in keys", path, kf.active)
+	}
+	if kf.indexKey, err = decodeKey(doc.IndexKey); err != nil {
+		return nil, fmt.Errorf("key file %s: index key: %w", path, err)
+	}
+	return kf, nil
+}
+
+func decodeKey(s string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.New("not valid base64")
+	}
+	if len(key) != 32 {
+		return nil, errors.New("must be 32 bytes")
+	}
+	return key, nil
+}
+
+func (k *KeyFile) ActiveKeyID() string {
+	return k.active
+}
+
+// WrapKey seals dataKey with AES-GCM under keyID, returning the nonce followed by the
+// ciphertext. The key ID is authenticated with it.
+func (k *KeyFile) WrapKey(keyID string, dataKey []byte) ([]byte, error) {
+	aead, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("no key %q in key file", keyID)
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(dataKey)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, dataKey, []byte(keyID)), nil
+}
+
+func (k *KeyFile) UnwrapKey(keyID string, wrapped []byte) ([]byte, error) {
+	aead, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("no key %q in key file", keyID)
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, errors.New("wrapped key is too short")
+	}
+	n := aead.NonceSize()
+	return aead.Open(nil, wrapped[:n], wrapped[n:], []byte(keyID))
+}
+
+// BlindIndex is HMAC-SHA256 of kind and value under the index key.
+func (k *KeyFile) BlindIndex(kind, value string) string {
+	mac := hmac.New(sha256.New, k.indexKey)
+	mac.Write([]byte(kind))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return base64.RawStdEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -6,15 +6,22 @@ import (
 	"time"
 
 	"github.com/wansanjou/backend-exercise-user-api/config"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
-func NewMongoDB() *mongo.Client {
+// NewMongoDB connects to Mongo, with registry as the client's registry unless it is nil.
+func NewMongoDB(registry *bsoncodec.Registry) *mongo.Client {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(config.Get().Mongo.URI))
+	opts := options.Client().ApplyURI(config.Get().Mongo.URI)
+	if registry != nil {
+		opts.SetRegistry(registry)
+	}
+	client, err := mongo.Connect(ctx, opts)
 	if err != nil {
 		log.Fatalf("failed to connect mongo: %s\n", err.Error())
 	}
@@ -25,3 +32,17 @@ func NewMongoDB() *mongo.Client {
 
 	return client
 }
+
+// EncryptionKeys loads the key file set in encryption.keyFile, or returns nil when none is
+// set and personal data is stored in plaintext.
+func EncryptionKeys() ports.KeyProvider {
+	path := config.Get().Encryption.KeyFile
+	if path == "" {
+		return nil
+	}
+	keys, err := LoadKeyFile(path)
+	if err != nil {
+		log.Fatalf("failed to load encryption keys: %s\n", err.Error())
+	}
+	return keys
+}
@@ -0,0 +1,172 @@
+package domains
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// Attributes are custom fields kept on a user, grouped by namespace. Each namespace belongs
+// to one product team and holds one JSON object checked against that team's schema.
+type Attributes map[string]map[string]interface{}
+
+var attributeNamespace = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// schemaMessages renders validation failures in English.
+var schemaMessages = message.NewPrinter(language.English)
+
+// AttributeSchemas are the compiled JSON Schemas of the attribute namespaces. A namespace
+// without a schema cannot be written.
+type AttributeSchemas struct {
+	schemas map[string]*jsonschema.Schema
+}
+
+// CompileAttributeSchemas compiles one JSON Schema document per namespace. Every schema
+// must describe an object.
+func CompileAttributeSchemas(docs map[string]string) (*AttributeSchemas, error) {
+	c := jsonschema.NewCompiler()
+	c.AssertFormat()
+	out := &AttributeSchemas{schemas: make(map[string]*jsonschema.Schema, len(docs))}
+	for _, ns := range slices.Sorted(maps.Keys(docs)) {
+		if !attributeNamespace.MatchString(ns) {
+			return nil, fmt.Errorf("attribute namespace %q: must be lower-case letters, digits and _", ns)
+		}
+		doc, err := jsonschema.UnmarshalJSON(strings.NewReader(docs[ns]))
+		if err != nil {
+			return nil, fmt.Errorf("attribute namespace %s: schema is not valid JSON: %w", ns, err)
+		}
+		url := "urn:attributes:" + ns
+		if err := c.AddResource(url, doc); err != nil {
+			return nil, fmt.Errorf("attribute namespace %s: %w", ns, err)
+		}
+		sch, err := c.Compile(url)
+		if err != nil {
+			return nil, fmt.Errorf("attribute namespace %s: %w", ns, err)
+		}
+		if sch.Types == nil || !slices.Equal(sch.Types.ToStrings(), []string{"object"}) {
+			return nil, fmt.Errorf("attribute namespace %s: schema must have type object", ns)
+		}
+		out.schemas[ns] = sch
+	}
+	return out, nil
+}
+
+// Validate checks every namespace of attrs against its schema and reports each failure as a
+// field such as attributes.loyalty.tier. A nil namespace, which removes it, is not checked.
+func (s *AttributeSchemas) Validate(attrs Attributes) error {
+	var fields []FieldError
+	for _, ns := range slices.Sorted(maps.Keys(attrs)) {
+		if attrs[ns] == nil {
+			continue
+		}
+		sch, ok := s.schemas[ns]
+		if !ok {
+			fields = append(fields, FieldError{
+				Field:   "attributes." + ns,
+				Code:    "unknown_namespace",
+				Message: "no attribute schema is configured for " + ns,
+			})
+			continue
+		}
+		var verr *jsonschema.ValidationError
+		if err := sch.Validate(map[string]interface{}(attrs[ns])); errors.As(err, &verr) {
+			fields = appendSchemaErrors(fields, "attributes."+ns, verr)
+		} else if err != nil {
+			return err
+		}
+	}
+	if len(fields) > 0 {
+		return InvalidFields(fields)
+	}
+	return nil
+}
+
+// AttributeWriters lists, for each namespace, the roles that may write it besides admins,
+// who may write any. Namespaces belong to product teams, so account holders (RoleUser)
+// can set only those that list them.
+type AttributeWriters map[string][]string
+
+// CheckWrite reports whether role may write every namespace in attrs, removals included.
+func (w AttributeWriters) CheckWrite(role string, attrs Attributes) error {
+	if role == RoleAdmin {
+		return nil
+	}
+	for _, ns := range slices.Sorted(maps.Keys(attrs)) {
+		if !slices.Contains(w[ns], role) {
+			return Forbidden("not allowed to set attributes in " + ns)
+		}
+	}
+	return nil
+}
+
+// appendSchemaErrors adds the leaves of a schema validation error, the failures that
+// explain it, as field errors.
+func appendSchemaErrors(fields []FieldError, prefix string, err *jsonschema.ValidationError) []FieldError {
+	if len(err.Causes) > 0 {
+		for _, cause := range err.Causes {
+			fields = appendSchemaErrors(fields, prefix, cause)
+		}
+		return fields
+	}
+	field := strings.Join(append([]string{prefix}, err.InstanceLocation...), ".")
+	return append(fields, FieldError{
+		Field:   field,
+		Code:    "invalid_attribute",
+		Message: field + ": " + err.ErrorKind.LocalizedString(schemaMessages),
+	})
+}
+
+// FilterValue turns the text of a search filter on the attribute at path, such as
+// loyalty.tier, into a value of the type its schema gives it, so that ?attr[loyalty.points]=10
+// matches the number 10. Only properties the schema declares can be filtered on.
+func (s *AttributeSchemas) FilterValue(path, raw string) (interface{}, error) {
+	field := "attr[" + path + "]"
+	segs := strings.Split(path, ".")
+	sch, ok := s.schemas[segs[0]]
+	if !ok || len(segs) < 2 {
+		return nil, Invalid(field, path+" is not a known attribute")
+	}
+	for _, seg := range segs[1:] {
+		for sch.Ref != nil {
+			sch = sch.Ref
+		}
+		if sch = sch.Properties[seg]; sch == nil {
+			return nil, Invalid(field, path+" is not a known attribute")
+		}
+	}
+	for sch.Ref != nil {
+		sch = sch.Ref
+	}
+
+	var types []string
+	if sch.Types != nil {
+		types = sch.Types.ToStrings()
+	}
+	if len(types) == 0 || slices.Contains(types, "string") {
+		return raw, nil
+	}
+	if slices.Contains(types, "boolean") {
+		if b, err := strconv.ParseBool(raw); err == nil {
+			return b, nil
+		}
+	}
+	if slices.Contains(types, "integer") {
+		if n, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return n, nil
+		}
+	}
+	if slices.Contains(types, "number") {
+		if f, err := strconv.ParseFloat(raw, 64); err == nil {
+			return f, nil
+		}
+	}
+	return nil, Invalid(field, fmt.Sprintf("%s must be %s", path, strings.Join(types, " or ")))
+}
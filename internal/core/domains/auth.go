@@ -1,8 +1,8 @@
 package domains
 
 type LoginRequest struct {
-	Email    string `bson:"email"`
-	Password string `bson:"password"`
+	Email    string `json:"email" validate:"required"`
+	Password string `json:"password" validate:"required"`
 }
 
 type LoginResponse struct {
@@ -0,0 +1,83 @@
+package domains
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// BatchModeAtomic runs every line in one transaction: all succeed or none do.
+	BatchModeAtomic = "atomic"
+	// BatchModePartial runs each line on its own and keeps the ones that succeed.
+	BatchModePartial = "partial"
+
+	BatchProcessing         = "processing"
+	BatchCompleted          = "completed"
+	BatchPartiallyCompleted = "partially_completed"
+	BatchFailed             = "failed"
+
+	BatchLineSucceeded = "succeeded"
+	BatchLineFailed    = "failed"
+
+	MaxBatchLines = 1000
+)
+
+type BatchTransferRequest struct {
+	FromUserID string              `json:"fromUserId" validate:"required,objectid"`
+	Mode       string              `json:"mode" validate:"omitempty,oneof=atomic partial"`
+	Lines      []BatchTransferLine `json:"lines" validate:"required,min=1,dive"`
+}
+
+type BatchTransferLine struct {
+	ToUserID string  `json:"toUserId" validate:"required,objectid"`
+	Amount   float64 `json:"amount" validate:"required,money"`
+}
+
+type TransferBatch struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	FromUserID  primitive.ObjectID `bson:"from_user_id" json:"fromUserId"`
+	Mode        string             `bson:"mode" json:"mode"`
+	Status      string             `bson:"status" json:"status"`
+	Total       float64            `bson:"total" json:"total"`
+	TotalFees   float64            `bson:"total_fees,omitempty" json:"totalFees,omitempty"`
+	Lines       []BatchLineResult  `bson:"lines" json:"lines"`
+	CreatedAt   time.Time          `bson:"created_at" json:"createdAt"`
+	CompletedAt *time.Time         `bson:"completed_at,omitempty" json:"completedAt,omitempty"`
+
+	FeeAccountID *primitive.ObjectID `bson:"fee_account_id,omitempty" json:"-"`
+}
+
+type BatchLineResult struct {
+	Line       int                 `bson:"line" json:"line"`
+	ToUserID   primitive.ObjectID  `bson:"to_user_id" json:"toUserId"`
+	Amount     float64             `bson:"amount" json:"amount"`
+	Fee        float64             `bson:"fee,omitempty" json:"fee,omitempty"`
+	Status     string              `bson:"status,omitempty" json:"status,omitempty"`
+	TransferID *primitive.ObjectID `bson:"transfer_id,omitempty" json:"transferId,omitempty"`
+	Error      string              `bson:"error,omitempty" json:"error,omitempty"`
+}
+
+// BatchValidationError lists every line that failed up-front validation. Nothing was executed.
+type BatchValidationError struct {
+	Lines []BatchLineError `json:"lines"`
+}
+
+type BatchLineError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+func (e *BatchValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Lines))
+	for _, l := range e.Lines {
+		msgs = append(msgs, fmt.Sprintf("line %d: %s", l.Line, l.Error))
+	}
+	return "invalid batch: " + strings.Join(msgs, "; ")
+}
+
+func (e *BatchValidationError) Unwrap() error {
+	return ErrValidation
+}
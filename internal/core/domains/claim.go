@@ -5,5 +5,6 @@ import "github.com/golang-jwt/jwt/v4"
 type JWTClaims struct {
 	ID    string `json:"id"`
 	Email string `json:"email"`
+	Role  string `json:"role"`
 	jwt.RegisteredClaims
 }
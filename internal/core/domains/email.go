@@ -0,0 +1,49 @@
+package domains
+
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/net/idna"
+)
+
+// NormalizeEmail is the form an email is stored and looked up in: trimmed, with the domain
+// converted to lower-case ASCII (internationalized domains become punycode). The local
+// part keeps its case; the unique index and lookups ignore case instead.
+func NormalizeEmail(email string) (string, error) {
+	email = strings.TrimSpace(email)
+	at := strings.LastIndex(email, "@")
+	if at < 1 || at == len(email)-1 {
+		return "", Invalid("email", "email must be a valid email address")
+	}
+	domain, err := idna.Lookup.ToASCII(email[at+1:])
+	if err != nil {
+		return "", Invalid("email", "email domain is not valid")
+	}
+	return email[:at] + "@" + strings.ToLower(domain), nil
+}
+
+// EmailIdentity is the key two emails share if they belong to the same account.
+func EmailIdentity(email string) string {
+	if n, err := NormalizeEmail(email); err == nil {
+		email = n
+	}
+	return SearchKey(email)
+}
+
+// EmailMigrationReport is the outcome of normalizing stored emails. Collisions are groups
+// of accounts whose emails differ only by case or domain spelling; they are left alone
+// for someone to resolve by hand, and the case-insensitive index is only built once
+// there are none.
+type EmailMigrationReport struct {
+	Checked      int
+	Normalized   int
+	Invalid      []EmailRecord
+	Collisions   [][]EmailRecord
+	IndexCreated bool
+}
+
+type EmailRecord struct {
+	UserID primitive.ObjectID
+	Email  string
+}
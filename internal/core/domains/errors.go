@@ -0,0 +1,95 @@
+package domains
+
+import (
+	"errors"
+	"strings"
+)
+
+// Error kinds. Every error a client can act on wraps one of these, so callers can branch
+// with errors.Is instead of matching message text.
+var (
+	ErrNotFound          = errors.New("not found")
+	ErrValidation        = errors.New("validation failed")
+	ErrConflict          = errors.New("conflict")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrLimitExceeded     = errors.New("limit exceeded")
+	ErrUnauthorized      = errors.New("unauthorized")
+	ErrForbidden         = errors.New("forbidden")
+	ErrRateLimited       = errors.New("rate limited")
+	ErrStale             = errors.New("precondition failed")
+	ErrUnavailable       = errors.New("service unavailable")
+)
+
+// Error is a failure that is safe to show to clients. Code is stable and machine-readable;
+// Message may be reworded at any time.
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Fields  []FieldError
+}
+
+// FieldError points at the part of a request that failed validation. Code is stable so
+// clients can map it to their own wording.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+func NewError(kind error, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// NotFound reports that a thing does not exist, e.g. NotFound("hold") is "hold not found"
+// with code hold_not_found.
+func NotFound(thing string) *Error {
+	return NewError(ErrNotFound, strings.ReplaceAll(thing, " ", "_")+"_not_found", thing+" not found")
+}
+
+// Invalid reports a request field that failed validation.
+func Invalid(field, message string) *Error {
+	return InvalidFields([]FieldError{{Field: field, Code: "invalid", Message: message}})
+}
+
+// InvalidFields reports several failing fields at once. The message is the first field's.
+func InvalidFields(fields []FieldError) *Error {
+	e := NewError(ErrValidation, "validation_failed", fields[0].Message)
+	e.Fields = fields
+	return e
+}
+
+func Conflict(code, message string) *Error {
+	return NewError(ErrConflict, code, message)
+}
+
+func InsufficientFunds(message string) *Error {
+	return NewError(ErrInsufficientFunds, "insufficient_funds", message)
+}
+
+func Unauthorized(code, message string) *Error {
+	return NewError(ErrUnauthorized, code, message)
+}
+
+func Forbidden(message string) *Error {
+	return NewError(ErrForbidden, "forbidden", message)
+}
+
+// Unavailable reports something the server cannot do as configured, so retrying will not
+// help until an operator fixes it.
+func Unavailable(code, message string) *Error {
+	return NewError(ErrUnavailable, code, message)
+}
+
+// Stale reports a conditional write whose expected version is no longer current.
+func Stale(message string) *Error {
+	return NewError(ErrStale, "version_mismatch", message)
+}
@@ -0,0 +1,110 @@
+package domains
+
+import "math"
+
+const (
+	DefaultCurrency = "THB"
+
+	FeeFlat       = "flat"
+	FeePercentage = "percentage"
+	FeeTiered     = "tiered"
+)
+
+// FeeRule prices a transfer. Tier and Currency narrow which transfers the rule applies
+// to; an empty value matches anything. Min and Max cap the computed fee when non-zero.
+// Amounts are in Currency or, for a rule without one, in DefaultCurrency.
+type FeeRule struct {
+	Tier     string
+	Currency string
+	Type     string
+	Amount   float64
+	Percent  float64
+	Min      float64
+	Max      float64
+	Brackets []FeeBracket
+}
+
+// FeeBracket applies to amounts up to and including UpTo. A zero UpTo has no upper bound.
+type FeeBracket struct {
+	UpTo    float64
+	Flat    float64
+	Percent float64
+}
+
+type FeeQuote struct {
+	Currency          string  `json:"currency"`
+	Amount            float64 `json:"amount"`
+	Fee               float64 `json:"fee"`
+	Total             float64 `json:"total"`
+	BalanceBefore     float64 `json:"balanceBefore"`
+	BalanceAfter      float64 `json:"balanceAfter"`
+	RecipientReceives float64 `json:"recipientReceives"`
+}
+
+type QuoteTransferRequest struct {
+	FromUserID string  `json:"fromUserId" validate:"required,objectid"`
+	ToUserID   string  `json:"toUserId" validate:"required,objectid"`
+	Amount     float64 `json:"amount" validate:"required,money"`
+}
+
+// NoFeeAccount is the error for a transfer that carries a fee when no house account is
+// configured to collect it.
+func NoFeeAccount() *Error {
+	return Unavailable("fee_account_unconfigured", "transfer fees cannot be collected: no house account is configured")
+}
+
+// Fee returns the fee for amount under the first rule matching tier and currency, rounded to two decimals.
+func Fee(rules []FeeRule, tier, currency string, amount float64) float64 {
+	if r := matchFeeRule(rules, tier, currency); r != nil {
+		return r.fee(amount)
+	}
+	return 0
+}
+
+// TransferFee returns the fee for t, in its source currency. A rule without a currency is
+// priced in DefaultCurrency, so a transfer in another currency is priced by its
+// BaseAmount and the fee converted back at the same rate.
+func TransferFee(rules []FeeRule, tier string, t Transfer) float64 {
+	currency := t.SourceCurrency()
+	r := matchFeeRule(rules, tier, currency)
+	if r == nil {
+		return 0
+	}
+	if r.Currency != "" || currency == DefaultCurrency || t.BaseAmount == 0 {
+		return r.fee(t.Amount)
+	}
+	return math.Round(r.fee(t.BaseAmount)*t.Amount/t.BaseAmount*100) / 100
+}
+
+func matchFeeRule(rules []FeeRule, tier, currency string) *FeeRule {
+	for i, r := range rules {
+		if (r.Tier == "" || r.Tier == tier) && (r.Currency == "" || r.Currency == currency) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+func (r FeeRule) fee(amount float64) float64 {
+	var fee float64
+	switch r.Type {
+	case FeeFlat:
+		fee = r.Amount
+	case FeePercentage:
+		fee = amount * r.Percent / 100
+	case FeeTiered:
+		for _, b := range r.Brackets {
+			if b.UpTo == 0 || amount <= b.UpTo {
+				fee = b.Flat + amount*b.Percent/100
+				break
+			}
+		}
+	}
+	if r.Min > 0 && fee < r.Min {
+		fee = r.Min
+	}
+	if r.Max > 0 && fee > r.Max {
+		fee = r.Max
+	}
+	return math.Round(fee*100) / 100
+}
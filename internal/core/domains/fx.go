@@ -0,0 +1,106 @@
+package domains
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const DefaultQuoteTTL = time.Minute
+
+// FXRate converts Base to Quote: one unit of Base buys Rate units of Quote. A rate applies
+// from ValidFrom until ValidUntil, or until a newer rate for the pair takes over.
+type FXRate struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Base       string             `bson:"base" json:"base"`
+	Quote      string             `bson:"quote" json:"quote"`
+	Rate       float64            `bson:"rate" json:"rate"`
+	ValidFrom  time.Time          `bson:"valid_from" json:"validFrom"`
+	ValidUntil *time.Time         `bson:"valid_until,omitempty" json:"validUntil,omitempty"`
+	Source     string             `bson:"source,omitempty" json:"source,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at" json:"createdAt"`
+}
+
+// FXQuote locks a rate for one conversion until ExpiresAt. It can be used by a single transfer.
+type FXQuote struct {
+	ID           primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	UserID       primitive.ObjectID  `bson:"user_id" json:"userId"`
+	FromCurrency string              `bson:"from_currency" json:"fromCurrency"`
+	ToCurrency   string              `bson:"to_currency" json:"toCurrency"`
+	Rate         float64             `bson:"rate" json:"rate"`
+	Amount       float64             `bson:"amount" json:"amount"`
+	ToAmount     float64             `bson:"to_amount" json:"toAmount"`
+	ExpiresAt    time.Time           `bson:"expires_at" json:"expiresAt"`
+	UsedAt       *time.Time          `bson:"used_at,omitempty" json:"usedAt,omitempty"`
+	TransferID   *primitive.ObjectID `bson:"transfer_id,omitempty" json:"transferId,omitempty"`
+	CreatedAt    time.Time           `bson:"created_at" json:"createdAt"`
+}
+
+type CreateFXQuoteRequest struct {
+	UserID       string  `json:"userId" validate:"omitempty,objectid"`
+	FromCurrency string  `json:"fromCurrency" validate:"required,currency"`
+	ToCurrency   string  `json:"toCurrency" validate:"required,currency"`
+	Amount       float64 `json:"amount" validate:"required,money"`
+}
+
+// FXRateRequest is one rate in an admin rate upload.
+type FXRateRequest struct {
+	Base       string     `json:"base" validate:"required,currency"`
+	Quote      string     `json:"quote" validate:"required,currency"`
+	Rate       float64    `json:"rate" validate:"required,gt=0"`
+	ValidFrom  time.Time  `json:"validFrom"`
+	ValidUntil *time.Time `json:"validUntil"`
+	Source     string     `json:"source" validate:"omitempty,max=100"`
+}
+
+func (r FXRateRequest) FXRate() FXRate {
+	return FXRate{Base: r.Base, Quote: r.Quote, Rate: r.Rate, ValidFrom: r.ValidFrom, ValidUntil: r.ValidUntil, Source: r.Source}
+}
+
+// NormalizeCurrency upper-cases a currency code, defaulting to DefaultCurrency when empty.
+func NormalizeCurrency(c string) string {
+	c = strings.ToUpper(strings.TrimSpace(c))
+	if c == "" {
+		return DefaultCurrency
+	}
+	return c
+}
+
+// BalanceField is the user document field holding the available balance in currency.
+// The default currency keeps living in the original balance field.
+func BalanceField(currency string) string {
+	if currency == DefaultCurrency {
+		return "balance"
+	}
+	return "wallets." + currency
+}
+
+func (r FXRate) Validate() error {
+	if len(r.Base) != 3 || len(r.Quote) != 3 {
+		return Invalid("base", "currencies must be three-letter codes")
+	}
+	if r.Base == r.Quote {
+		return Invalid("quote", "base and quote currencies must differ")
+	}
+	if r.Rate <= 0 || math.IsInf(r.Rate, 0) || math.IsNaN(r.Rate) {
+		return Invalid("rate", "rate must be greater than zero")
+	}
+	if r.ValidUntil != nil && !r.ValidUntil.After(r.ValidFrom) {
+		return Invalid("validUntil", "validUntil must be after validFrom")
+	}
+	return nil
+}
+
+// Convert returns amount of Base in Quote, rounded to two decimals.
+func (r FXRate) Convert(amount float64) float64 {
+	return math.Round(amount*r.Rate*100) / 100
+}
+
+// Invert returns the same rate read in the other direction.
+func (r FXRate) Invert() FXRate {
+	inv := r
+	inv.Base, inv.Quote, inv.Rate = r.Quote, r.Base, 1/r.Rate
+	return inv
+}
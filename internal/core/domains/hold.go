@@ -0,0 +1,42 @@
+package domains
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	HoldActive   = "active"
+	HoldCaptured = "captured"
+	HoldVoided   = "voided"
+	HoldExpired  = "expired"
+
+	DefaultHoldExpiry = 7 * 24 * time.Hour
+	MaxHoldExpiry     = 30 * 24 * time.Hour
+)
+
+type Hold struct {
+	ID             primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	FromUserID     primitive.ObjectID  `bson:"from_user_id" json:"fromUserId"`
+	ToUserID       primitive.ObjectID  `bson:"to_user_id" json:"toUserId"`
+	Amount         float64             `bson:"amount" json:"amount"`
+	CapturedAmount float64             `bson:"captured_amount" json:"capturedAmount"`
+	Status         string              `bson:"status" json:"status"`
+	TransferID     *primitive.ObjectID `bson:"transfer_id,omitempty" json:"transferId,omitempty"`
+	ExpiresAt      time.Time           `bson:"expires_at" json:"expiresAt"`
+	CreatedAt      time.Time           `bson:"created_at" json:"createdAt"`
+	UpdatedAt      time.Time           `bson:"updated_at" json:"updatedAt"`
+}
+
+type CreateHoldRequest struct {
+	FromUserID       string  `json:"fromUserId" validate:"required,objectid"`
+	ToUserID         string  `json:"toUserId" validate:"required,objectid"`
+	Amount           float64 `json:"amount" validate:"required,money"`
+	ExpiresInSeconds int     `json:"expiresInSeconds" validate:"gte=0"`
+}
+
+// CaptureHoldRequest settles part or all of a hold. A zero Amount captures the full hold.
+type CaptureHoldRequest struct {
+	Amount float64 `json:"amount" validate:"omitempty,money"`
+}
@@ -0,0 +1,123 @@
+package domains
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultTier = "standard"
+
+	LimitMaxPerTransaction   = "max_per_transaction"
+	LimitDailyOutgoing       = "daily_outgoing"
+	LimitMonthlyOutgoing     = "monthly_outgoing"
+	LimitMaxTransfersPerHour = "max_transfers_per_hour"
+)
+
+// TransferLimits caps a user's outgoing transfers. A zero value disables that limit.
+type TransferLimits struct {
+	MaxPerTransaction   float64
+	DailyOutgoing       float64
+	MonthlyOutgoing     float64
+	MaxTransfersPerHour int
+}
+
+// LimitTiers are the transfer limits of each account tier, keyed by lower-case tier name.
+type LimitTiers map[string]TransferLimits
+
+// For returns the limits of tier, or of defaultTier when tier is not one of t, so a tier
+// misspelt or dropped from config never lifts a user's limits. It fails when neither is.
+func (t LimitTiers) For(tier, defaultTier string) (TransferLimits, error) {
+	if l, ok := t[strings.ToLower(tier)]; ok {
+		return l, nil
+	}
+	if l, ok := t[strings.ToLower(defaultTier)]; ok {
+		return l, nil
+	}
+	return TransferLimits{}, Unavailable("transfer_limits_unconfigured", "transfer limits are not configured for this account")
+}
+
+// LimitOverrides replaces individual tier limits for a single user. Nil fields inherit the tier value.
+type LimitOverrides struct {
+	MaxPerTransaction   *float64 `bson:"max_per_transaction,omitempty"`
+	DailyOutgoing       *float64 `bson:"daily_outgoing,omitempty"`
+	MonthlyOutgoing     *float64 `bson:"monthly_outgoing,omitempty"`
+	MaxTransfersPerHour *int     `bson:"max_transfers_per_hour,omitempty"`
+}
+
+// TransferUsage is what a user has already sent in the current hour, day and month.
+type TransferUsage struct {
+	HourlyCount  int
+	DailyTotal   float64
+	MonthlyTotal float64
+}
+
+type LimitExceededError struct {
+	Limit    string
+	Max      float64
+	ResetsAt time.Time
+}
+
+func (e *LimitExceededError) Error() string {
+	if e.ResetsAt.IsZero() {
+		return fmt.Sprintf("transfer limit exceeded: %s (max %.2f)", e.Limit, e.Max)
+	}
+	return fmt.Sprintf("transfer limit exceeded: %s (max %.2f), resets at %s",
+		e.Limit, e.Max, e.ResetsAt.Format(time.RFC3339))
+}
+
+func (e *LimitExceededError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+func (l TransferLimits) Apply(o *LimitOverrides) TransferLimits {
+	if o == nil {
+		return l
+	}
+	if o.MaxPerTransaction != nil {
+		l.MaxPerTransaction = *o.MaxPerTransaction
+	}
+	if o.DailyOutgoing != nil {
+		l.DailyOutgoing = *o.DailyOutgoing
+	}
+	if o.MonthlyOutgoing != nil {
+		l.MonthlyOutgoing = *o.MonthlyOutgoing
+	}
+	if o.MaxTransfersPerHour != nil {
+		l.MaxTransfersPerHour = *o.MaxTransfersPerHour
+	}
+	return l
+}
+
+// LimitWindows returns the start of the UTC hour, day and month that contain now.
+func LimitWindows(now time.Time) (hour, day, month time.Time) {
+	now = now.UTC()
+	hour = now.Truncate(time.Hour)
+	day = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	month = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return hour, day, month
+}
+
+// Check reports whether sending amount on top of usage stays within l.
+func (l TransferLimits) Check(usage TransferUsage, amount float64, now time.Time) error {
+	hour, day, month := LimitWindows(now)
+
+	if l.MaxPerTransaction > 0 && amount > l.MaxPerTransaction {
+		return &LimitExceededError{Limit: LimitMaxPerTransaction, Max: l.MaxPerTransaction}
+	}
+	if l.MaxTransfersPerHour > 0 && usage.HourlyCount+1 > l.MaxTransfersPerHour {
+		return &LimitExceededError{
+			Limit:    LimitMaxTransfersPerHour,
+			Max:      float64(l.MaxTransfersPerHour),
+			ResetsAt: hour.Add(time.Hour),
+		}
+	}
+	if l.DailyOutgoing > 0 && usage.DailyTotal+amount > l.DailyOutgoing {
+		return &LimitExceededError{Limit: LimitDailyOutgoing, Max: l.DailyOutgoing, ResetsAt: day.AddDate(0, 0, 1)}
+	}
+	if l.MonthlyOutgoing > 0 && usage.MonthlyTotal+amount > l.MonthlyOutgoing {
+		return &LimitExceededError{Limit: LimitMonthlyOutgoing, Max: l.MonthlyOutgoing, ResetsAt: month.AddDate(0, 1, 0)}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package domains
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	DefaultUserSort = "created_at"
+	// SortRelevance orders a full-text search best match first. It cannot be reversed.
+	SortRelevance = "relevance"
+)
+
+// userSortFields are the fields GET /users may be sorted by.
+var userSortFields = map[string]bool{
+	"created_at":  true,
+	"name":        true,
+	"email":       true,
+	SortRelevance: true,
+}
+
+// UserSort orders a user listing by Field, then by _id to break ties.
+type UserSort struct {
+	Field      string
+	Descending bool
+}
+
+// ParseUserSort reads "field" or "-field" for descending order. Empty means DefaultUserSort.
+func ParseUserSort(s string) (UserSort, error) {
+	if s == "" {
+		s = DefaultUserSort
+	}
+	out := UserSort{Field: strings.TrimPrefix(s, "-"), Descending: strings.HasPrefix(s, "-")}
+	if !userSortFields[out.Field] || out.Field == SortRelevance && out.Descending {
+		return UserSort{}, Invalid("sort", "sort must be relevance, or one of created_at, name or email optionally prefixed with -")
+	}
+	return out, nil
+}
+
+func (s UserSort) String() string {
+	if s.Descending {
+		return "-" + s.Field
+	}
+	return s.Field
+}
+
+// UserCursor marks the last user of a page: the value it was sorted on and its ID. It is
+// handed to clients as an opaque string.
+type UserCursor struct {
+	Sort  string             `json:"s"`
+	Value string             `json:"v"`
+	ID    primitive.ObjectID `json:"id"`
+}
+
+// CursorAfter returns the cursor for the page that follows u under sort.
+func CursorAfter(u User, sort UserSort) UserCursor {
+	c := UserCursor{Sort: sort.String(), ID: u.ID}
+	switch sort.Field {
+	case "name":
+		c.Value = u.Name
+	case "email":
+		c.Value = u.Email
+	default:
+		c.Value = u.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return c
+}
+
+// RelevanceCursor returns the cursor for the page of search results after the user with
+// id, which scored score.
+func RelevanceCursor(id primitive.ObjectID, score float64) UserCursor {
+	return UserCursor{Sort: SortRelevance, Value: strconv.FormatFloat(score, 'g', -1, 64), ID: id}
+}
+
+// SortValue is the cursor's value typed as the field it was sorted on.
+func (c UserCursor) SortValue() interface{} {
+	switch strings.TrimPrefix(c.Sort, "-") {
+	case "created_at":
+		t, _ := time.Parse(time.RFC3339Nano, c.Value)
+		return t
+	case SortRelevance:
+		f, _ := strconv.ParseFloat(c.Value, 64)
+		return f
+	}
+	return c.Value
+}
+
+func (c UserCursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeUserCursor reads a cursor produced by Encode for a listing sorted by sort.
+func DecodeUserCursor(s string, sort UserSort) (*UserCursor, error) {
+	invalid := Invalid("cursor", "cursor is invalid or has expired")
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, invalid
+	}
+	var c UserCursor
+	if err := json.Unmarshal(b, &c); err != nil || c.ID.IsZero() {
+		return nil, invalid
+	}
+	if c.Sort != sort.String() {
+		return nil, Invalid("cursor", "cursor was issued for a different sort order")
+	}
+	switch sort.Field {
+	case "created_at":
+		if _, err := time.Parse(time.RFC3339Nano, c.Value); err != nil {
+			return nil, invalid
+		}
+	case SortRelevance:
+		if _, err := strconv.ParseFloat(c.Value, 64); err != nil {
+			return nil, invalid
+		}
+	}
+	return &c, nil
+}
+
+// UserPage is one page of a user listing. NextCursor is empty on the last page; Total is
+// only counted when asked for.
+type UserPage struct {
+	Users      []User
+	NextCursor string
+	Total      *int64
+}
@@ -0,0 +1,39 @@
+package domains
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ErasurePending   = "pending"
+	ErasureCompleted = "completed"
+
+	// ErasedName replaces the name of an erased user, and ErasedText any free text that was
+	// written about them.
+	ErasedName = "Erased user"
+	ErasedText = "[erased]"
+)
+
+// ErasureRequest is a data subject's request to have their personal data erased. It is
+// kept after the erasure as the record that it was asked for and carried out.
+type ErasureRequest struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID      primitive.ObjectID `bson:"user_id" json:"userId"`
+	Status      string             `bson:"status" json:"status"`
+	RequestedBy string             `bson:"requested_by" json:"requestedBy"`
+	RequestedAt time.Time          `bson:"requested_at" json:"requestedAt"`
+	CompletedBy string             `bson:"completed_by,omitempty" json:"completedBy,omitempty"`
+	CompletedAt *time.Time         `bson:"completed_at,omitempty" json:"completedAt,omitempty"`
+}
+
+// ErasedEmail is the placeholder email of an erased user. It is unique per account so the
+// email index still holds, and can never receive mail.
+func ErasedEmail(id primitive.ObjectID) string {
+	return "erased-" + id.Hex() + "@erased.invalid"
+}
+
+func (u User) Erased() bool {
+	return u.ErasedAt != nil
+}
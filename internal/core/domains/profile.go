@@ -0,0 +1,90 @@
+package domains
+
+import (
+	"strings"
+	"time"
+)
+
+// Profile is the optional personal detail kept on an account. A phone number is an alias
+// and lives on User itself.
+type Profile struct {
+	Locale      string     `json:"locale,omitempty" bson:"locale,omitempty" validate:"omitempty,bcp47_language_tag"`
+	DateOfBirth string     `json:"dateOfBirth,omitempty" bson:"date_of_birth,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	Address     *Address   `json:"address,omitempty" bson:"address,omitempty"`
+	Marketing   *Marketing `json:"marketing,omitempty" bson:"marketing,omitempty"`
+}
+
+// Address is a postal address. Country is an ISO 3166-1 alpha-2 code.
+type Address struct {
+	Line1      string `json:"line1" bson:"line1" validate:"required,max=200"`
+	Line2      string `json:"line2,omitempty" bson:"line2,omitempty" validate:"omitempty,max=200"`
+	City       string `json:"city" bson:"city" validate:"required,max=100"`
+	Region     string `json:"region,omitempty" bson:"region,omitempty" validate:"omitempty,max=100"`
+	PostalCode string `json:"postalCode,omitempty" bson:"postal_code,omitempty" validate:"omitempty,max=20"`
+	Country    string `json:"country" bson:"country" validate:"required,iso3166_1_alpha2"`
+}
+
+// Marketing is what the user agreed to be sent. Nothing is sent on a channel left false.
+type Marketing struct {
+	Email bool `json:"email" bson:"email"`
+	SMS   bool `json:"sms" bson:"sms"`
+	Push  bool `json:"push" bson:"push"`
+}
+
+// ProfileUpdate changes parts of a profile. Only the fields given are changed; an empty
+// locale or date of birth removes it, and so does an address with no fields. An address is
+// replaced whole, marketing preferences one channel at a time.
+type ProfileUpdate struct {
+	Locale      *string          `json:"locale" validate:"omitempty,bcp47_language_tag"`
+	DateOfBirth *string          `json:"dateOfBirth" validate:"omitempty,datetime=2006-01-02"`
+	Address     *Address         `json:"address" validate:"omitzero"`
+	Marketing   *MarketingUpdate `json:"marketing"`
+}
+
+type MarketingUpdate struct {
+	Email *bool `json:"email"`
+	SMS   *bool `json:"sms"`
+	Push  *bool `json:"push"`
+}
+
+// Normalize trims the locale and upper-cases the address's country code, so "th" is
+// accepted as "TH".
+func (p *Profile) Normalize() {
+	if p == nil {
+		return
+	}
+	p.Locale = strings.TrimSpace(p.Locale)
+	p.Address.normalize()
+}
+
+func (p *ProfileUpdate) Normalize() {
+	if p == nil {
+		return
+	}
+	if p.Locale != nil {
+		locale := strings.TrimSpace(*p.Locale)
+		p.Locale = &locale
+	}
+	p.Address.normalize()
+}
+
+func (a *Address) normalize() {
+	if a != nil {
+		a.Country = strings.ToUpper(strings.TrimSpace(a.Country))
+	}
+}
+
+// CheckDateOfBirth rejects a date of birth after today. dob must already be YYYY-MM-DD.
+func CheckDateOfBirth(dob string, now time.Time) error {
+	if dob == "" {
+		return nil
+	}
+	d, err := time.Parse(time.DateOnly, dob)
+	if err != nil {
+		return Invalid("profile.dateOfBirth", "dateOfBirth must be a date in YYYY-MM-DD form")
+	}
+	if d.After(now) {
+		return Invalid("profile.dateOfBirth", "dateOfBirth cannot be in the future")
+	}
+	return nil
+}
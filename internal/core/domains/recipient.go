@@ -0,0 +1,145 @@
+package domains
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	AliasEmail  = "email"
+	AliasHandle = "handle"
+	AliasPhone  = "phone"
+)
+
+// Alias names a recipient without exposing their user ID.
+type Alias struct {
+	Kind  string
+	Value string
+}
+
+// RecipientPreview is what a sender sees before paying an alias. It carries only masked
+// data so lookups cannot be used to harvest names, emails or phone numbers.
+type RecipientPreview struct {
+	Alias       string `json:"alias"`
+	Kind        string `json:"kind"`
+	DisplayName string `json:"displayName"`
+	Contact     string `json:"contact,omitempty"`
+}
+
+type UpdateAliasesRequest struct {
+	Handle *string `json:"handle" validate:"omitempty,max=31"`
+	Phone  *string `json:"phone" validate:"omitempty,max=24"`
+}
+
+type VerifyAliasesRequest struct {
+	Email *bool `json:"email"`
+	Phone *bool `json:"phone"`
+}
+
+// ParseAlias reads "@handle", an email address or an international phone number.
+func ParseAlias(s string) (Alias, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case strings.HasPrefix(s, "@"):
+		h, err := NormalizeHandle(s)
+		return Alias{AliasHandle, h}, err
+	case strings.Contains(s, "@"):
+		if strings.Count(s, "@") != 1 || strings.HasSuffix(s, "@") || !strings.Contains(s[strings.Index(s, "@"):], ".") {
+			return Alias{}, Invalid("alias", "invalid email alias")
+		}
+		e, err := NormalizeEmail(s)
+		if err != nil {
+			return Alias{}, Invalid("alias", "invalid email alias")
+		}
+		return Alias{AliasEmail, e}, nil
+	case strings.HasPrefix(s, "+"):
+		p, err := NormalizePhone(s)
+		return Alias{AliasPhone, p}, err
+	default:
+		return Alias{}, Invalid("alias", "recipient must be an @handle, email or +phone number")
+	}
+}
+
+// NormalizeHandle lower-cases a handle and strips the leading @. Handles are 3 to 30
+// letters, digits, dots or underscores.
+func NormalizeHandle(h string) (string, error) {
+	h = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(h), "@"))
+	if len(h) < 3 || len(h) > 30 {
+		return "", Invalid("handle", "handle must be 3 to 30 characters")
+	}
+	for _, r := range h {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' || r == '.') {
+			return "", Invalid("handle", "handle may only contain letters, digits, dots and underscores")
+		}
+	}
+	return h, nil
+}
+
+// NormalizePhone reduces a phone number to +digits, dropping spaces, dashes and brackets.
+func NormalizePhone(p string) (string, error) {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(p) {
+		switch {
+		case r == '+' && i == 0, r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '(' || r == ')':
+		default:
+			return "", Invalid("phone", "invalid phone number")
+		}
+	}
+	out := b.String()
+	if !strings.HasPrefix(out, "+") || len(out) < 9 || len(out) > 16 {
+		return "", Invalid("phone", "phone number must be in international format, e.g. +66812345678")
+	}
+	return out, nil
+}
+
+// Preview masks u for display to someone paying them through a.
+func (u User) Preview(a Alias) RecipientPreview {
+	out := RecipientPreview{Kind: a.Kind, DisplayName: maskName(u.Name)}
+	switch a.Kind {
+	case AliasHandle:
+		out.Alias = "@" + a.Value
+	case AliasEmail:
+		out.Alias = a.Value
+		out.Contact = maskEmail(u.Email)
+	case AliasPhone:
+		out.Alias = a.Value
+		out.Contact = maskTail(u.Phone, 4)
+	}
+	return out
+}
+
+// maskName keeps the first letters of the first name and the initial of the last, e.g. "Som**** J.".
+func maskName(name string) string {
+	parts := strings.Fields(name)
+	if len(parts) == 0 {
+		return ""
+	}
+	first := []rune(parts[0])
+	keep := 3
+	if len(first) <= 3 {
+		keep = 1
+	}
+	out := string(first[:keep]) + strings.Repeat("*", len(first)-keep)
+	if len(parts) > 1 {
+		r, _ := utf8.DecodeRuneInString(parts[len(parts)-1])
+		out += " " + string(r) + "."
+	}
+	return out
+}
+
+func maskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 1 {
+		return ""
+	}
+	return email[:1] + strings.Repeat("*", at-1) + email[at:]
+}
+
+func maskTail(s string, visible int) string {
+	if len(s) <= visible {
+		return s
+	}
+	return strings.Repeat("*", len(s)-visible) + s[len(s)-visible:]
+}
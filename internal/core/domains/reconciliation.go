@@ -0,0 +1,126 @@
+package domains
+
+import (
+	"maps"
+	"math"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// OpeningBalance is what every account is credited with on sign-up, before any transfer.
+	OpeningBalance = 100.0
+
+	ReconciliationRunning   = "running"
+	ReconciliationCompleted = "completed"
+	ReconciliationFailed    = "failed"
+
+	DefaultReconciliationPageSize = 500
+
+	// DriftTolerance absorbs float rounding; smaller differences are not reported.
+	DriftTolerance = 0.005
+)
+
+// LedgerTotals is what the transfer history says about one account in one currency.
+type LedgerTotals struct {
+	Received      float64 `bson:"received" json:"received"`
+	Sent          float64 `bson:"sent" json:"sent"`
+	FeesPaid      float64 `bson:"fees_paid" json:"feesPaid"`
+	FeesCollected float64 `bson:"fees_collected" json:"feesCollected"`
+	Held          float64 `bson:"held" json:"held"`
+}
+
+// Net is how much the transfers moved the account's balance overall.
+func (l LedgerTotals) Net() float64 {
+	return l.Received + l.FeesCollected - l.Sent - l.FeesPaid
+}
+
+// AccountBalance pairs the balances stored on a user with their ledger, read from the same
+// snapshot. Balances, Opening and Ledger are keyed by currency.
+type AccountBalance struct {
+	UserID      primitive.ObjectID
+	Balances    map[string]float64
+	HeldBalance float64 // holds are always in DefaultCurrency
+	Frozen      bool
+	Opening     map[string]float64
+	Ledger      map[string]LedgerTotals
+}
+
+// Expected returns the available and held balances the ledger accounts for in currency.
+// Open holds move money from Balance to HeldBalance without a transfer, so they only shift
+// the split.
+func (a AccountBalance) Expected(currency string) (balance, held float64) {
+	l := a.Ledger[currency]
+	total := a.Opening[currency] + l.Net()
+	return total - l.Held, l.Held
+}
+
+// Mismatches returns the drift between the stored and the expected balances of every
+// currency the account has a balance, opening credit or transfer in, sorted by currency.
+func (a AccountBalance) Mismatches() []BalanceMismatch {
+	currencies := map[string]bool{}
+	for _, m := range []map[string]float64{a.Balances, a.Opening} {
+		for c := range m {
+			currencies[c] = true
+		}
+	}
+	for c := range a.Ledger {
+		currencies[c] = true
+	}
+
+	var out []BalanceMismatch
+	for _, c := range slices.Sorted(maps.Keys(currencies)) {
+		var stored float64
+		if c == DefaultCurrency {
+			stored = a.HeldBalance
+		}
+		balance, held := a.Expected(c)
+		drift := a.Balances[c] - balance
+		heldDrift := stored - held
+		if math.Abs(drift) <= DriftTolerance && math.Abs(heldDrift) <= DriftTolerance {
+			continue
+		}
+		out = append(out, BalanceMismatch{
+			UserID:              a.UserID,
+			Currency:            c,
+			Balance:             a.Balances[c],
+			HeldBalance:         stored,
+			ExpectedBalance:     balance,
+			ExpectedHeldBalance: held,
+			Drift:               math.Round(drift*100) / 100,
+			HeldDrift:           math.Round(heldDrift*100) / 100,
+			Ledger:              a.Ledger[c],
+		})
+	}
+	return out
+}
+
+type ReconciliationRun struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Status     string             `bson:"status" json:"status"`
+	Freeze     bool               `bson:"freeze" json:"freeze"`
+	Checked    int64              `bson:"checked" json:"checked"`
+	Mismatched int64              `bson:"mismatched" json:"mismatched"`
+	Frozen     int64              `bson:"frozen" json:"frozen"`
+	Error      string             `bson:"error,omitempty" json:"error,omitempty"`
+	StartedAt  time.Time          `bson:"started_at" json:"startedAt"`
+	FinishedAt *time.Time         `bson:"finished_at,omitempty" json:"finishedAt,omitempty"`
+}
+
+type BalanceMismatch struct {
+	ID                  primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	RunID               primitive.ObjectID `bson:"run_id" json:"runId"`
+	UserID              primitive.ObjectID `bson:"user_id" json:"userId"`
+	Currency            string             `bson:"currency" json:"currency"`
+	Balance             float64            `bson:"balance" json:"balance"`
+	HeldBalance         float64            `bson:"held_balance" json:"heldBalance"`
+	ExpectedBalance     float64            `bson:"expected_balance" json:"expectedBalance"`
+	ExpectedHeldBalance float64            `bson:"expected_held_balance" json:"expectedHeldBalance"`
+	Drift               float64            `bson:"drift" json:"drift"`
+	HeldDrift           float64            `bson:"held_drift" json:"heldDrift"`
+	Ledger              LedgerTotals       `bson:"ledger" json:"ledger"`
+	Frozen              bool               `bson:"frozen" json:"frozen"`
+	DetectedAt          time.Time          `bson:"detected_at" json:"detectedAt"`
+}
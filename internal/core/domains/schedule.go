@@ -0,0 +1,135 @@
+package domains
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ScheduleActive    = "active"
+	SchedulePaused    = "paused"
+	ScheduleCompleted = "completed"
+	ScheduleCancelled = "cancelled"
+
+	RunRunning   = "running"
+	RunSucceeded = "succeeded"
+	RunFailed    = "failed"
+
+	// MaxRunAttempts is how many times one occurrence is tried before it is skipped.
+	MaxRunAttempts = 3
+	RunRetryDelay  = 5 * time.Minute
+	MinInterval    = time.Minute
+)
+
+type ScheduledTransfer struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	FromUserID      primitive.ObjectID `bson:"from_user_id" json:"fromUserId"`
+	ToUserID        primitive.ObjectID `bson:"to_user_id" json:"toUserId"`
+	Amount          float64            `bson:"amount" json:"amount"`
+	Cron            string             `bson:"cron,omitempty" json:"cron,omitempty"`
+	IntervalSeconds int64              `bson:"interval_seconds,omitempty" json:"intervalSeconds,omitempty"`
+	StartAt         time.Time          `bson:"start_at" json:"startAt"`
+	EndAt           *time.Time         `bson:"end_at,omitempty" json:"endAt,omitempty"`
+	MaxRuns         int                `bson:"max_runs,omitempty" json:"maxRuns,omitempty"`
+	RunCount        int                `bson:"run_count" json:"runCount"`
+	Status          string             `bson:"status" json:"status"`
+	// Occurrence is the scheduled time currently being executed; NextRunAt is when the
+	// executor should next try it, which is later than Occurrence while retrying.
+	Occurrence *time.Time `bson:"occurrence,omitempty" json:"occurrence,omitempty"`
+	NextRunAt  *time.Time `bson:"next_run_at,omitempty" json:"nextRunAt,omitempty"`
+	Attempt    int        `bson:"attempt" json:"attempt"`
+	LastRunAt  *time.Time `bson:"last_run_at,omitempty" json:"lastRunAt,omitempty"`
+	LeaseOwner string     `bson:"lease_owner,omitempty" json:"-"`
+	LeaseUntil *time.Time `bson:"lease_until,omitempty" json:"-"`
+	CreatedAt  time.Time  `bson:"created_at" json:"createdAt"`
+	UpdatedAt  time.Time  `bson:"updated_at" json:"updatedAt"`
+}
+
+type ScheduledTransferRun struct {
+	ID           primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	ScheduleID   primitive.ObjectID  `bson:"schedule_id" json:"scheduleId"`
+	ScheduledFor time.Time           `bson:"scheduled_for" json:"scheduledFor"`
+	Attempt      int                 `bson:"attempt" json:"attempt"`
+	Status       string              `bson:"status" json:"status"`
+	TransferID   *primitive.ObjectID `bson:"transfer_id,omitempty" json:"transferId,omitempty"`
+	Error        string              `bson:"error,omitempty" json:"error,omitempty"`
+	StartedAt    time.Time           `bson:"started_at" json:"startedAt"`
+	FinishedAt   *time.Time          `bson:"finished_at,omitempty" json:"finishedAt,omitempty"`
+}
+
+type CreateScheduledTransferRequest struct {
+	FromUserID      string     `json:"fromUserId" validate:"required,objectid"`
+	ToUserID        string     `json:"toUserId" validate:"required,objectid"`
+	Amount          float64    `json:"amount" validate:"required,money"`
+	Cron            string     `json:"cron" validate:"omitempty,max=100"`
+	IntervalSeconds int64      `json:"intervalSeconds" validate:"omitempty,min=60"`
+	StartAt         *time.Time `json:"startAt"`
+	EndAt           *time.Time `json:"endAt"`
+	MaxRuns         int        `json:"maxRuns" validate:"gte=0"`
+}
+
+// After returns the first occurrence of the schedule strictly after t, ignoring end conditions.
+// A one-off schedule (no cron and no interval) only occurs at StartAt.
+func (s ScheduledTransfer) After(t time.Time) (time.Time, error) {
+	switch {
+	case s.Cron != "":
+		c, err := ParseCron(s.Cron)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if t.Before(s.StartAt) {
+			t = s.StartAt.Add(-time.Minute)
+		}
+		return c.Next(t), nil
+	case s.IntervalSeconds > 0:
+		if t.Before(s.StartAt) {
+			return s.StartAt, nil
+		}
+		every := time.Duration(s.IntervalSeconds) * time.Second
+		n := t.Sub(s.StartAt)/every + 1
+		return s.StartAt.Add(n * every), nil
+	default:
+		if t.Before(s.StartAt) {
+			return s.StartAt, nil
+		}
+		return time.Time{}, nil
+	}
+}
+
+// Upcoming returns the next occurrence after t that is still within the end conditions,
+// or nil when the schedule has finished.
+func (s ScheduledTransfer) Upcoming(t time.Time) (*time.Time, error) {
+	if s.MaxRuns > 0 && s.RunCount >= s.MaxRuns {
+		return nil, nil
+	}
+	next, err := s.After(t)
+	if err != nil {
+		return nil, err
+	}
+	if next.IsZero() || (s.EndAt != nil && next.After(*s.EndAt)) {
+		return nil, nil
+	}
+	return &next, nil
+}
+
+func (s ScheduledTransfer) Validate() error {
+	if s.Cron != "" && s.IntervalSeconds > 0 {
+		return Invalid("cron", "use either cron or intervalSeconds, not both")
+	}
+	if s.Cron != "" {
+		if _, err := ParseCron(s.Cron); err != nil {
+			return Invalid("cron", err.Error())
+		}
+	}
+	if s.IntervalSeconds < 0 || (s.IntervalSeconds > 0 && time.Duration(s.IntervalSeconds)*time.Second < MinInterval) {
+		return Invalid("intervalSeconds", "intervalSeconds must be at least 60")
+	}
+	if s.MaxRuns < 0 {
+		return Invalid("maxRuns", "maxRuns must not be negative")
+	}
+	if s.EndAt != nil && !s.EndAt.After(s.StartAt) {
+		return Invalid("endAt", "endAt must be after startAt")
+	}
+	return nil
+}
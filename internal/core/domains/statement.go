@@ -0,0 +1,109 @@
+package domains
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	StatementCSV    = "csv"
+	StatementNDJSON = "ndjson"
+	StatementOFX    = "ofx"
+
+	EntryDebit     = "debit"
+	EntryCredit    = "credit"
+	EntryFee       = "fee"
+	EntryFeeIncome = "fee_income"
+
+	StoredPending = "pending"
+	StoredReady   = "ready"
+
+	// PeriodLayout names a monthly statement period, e.g. 2026-09.
+	PeriodLayout = "2006-01"
+)
+
+// Statement describes a user's account over [From, To). Balances are ledger balances:
+// money reserved by an open hold still belongs to the account and is included.
+type Statement struct {
+	UserID         primitive.ObjectID `json:"userId"`
+	Currency       string             `json:"currency"`
+	From           time.Time          `json:"from"`
+	To             time.Time          `json:"to"`
+	OpeningBalance float64            `json:"openingBalance"`
+	ClosingBalance float64            `json:"closingBalance"`
+	Entries        int                `json:"entries"`
+}
+
+type StatementEntry struct {
+	ID          string             `json:"id"`
+	TransferID  primitive.ObjectID `json:"transferId"`
+	Date        time.Time          `json:"date"`
+	Type        string             `json:"type"`
+	Description string             `json:"description"`
+	Amount      float64            `json:"amount"`  // signed: negative when money left the account
+	Balance     float64            `json:"balance"` // running balance after this entry
+}
+
+// StoredStatement is a generated monthly statement kept for download.
+type StoredStatement struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID         primitive.ObjectID `bson:"user_id" json:"userId"`
+	Period         string             `bson:"period" json:"period"`
+	Format         string             `bson:"format" json:"format"`
+	Status         string             `bson:"status" json:"status"`
+	OpeningBalance float64            `bson:"opening_balance" json:"openingBalance"`
+	ClosingBalance float64            `bson:"closing_balance" json:"closingBalance"`
+	Entries        int                `bson:"entries" json:"entries"`
+	CreatedAt      time.Time          `bson:"created_at" json:"createdAt"`
+}
+
+func ValidStatementFormat(format string) bool {
+	return format == StatementCSV || format == StatementNDJSON || format == StatementOFX
+}
+
+// EntriesFor returns the lines a transfer puts on userID's statement in currency: the amount
+// leaving or arriving, the fee the sender paid and the fee the house account collected.
+func (t Transfer) EntriesFor(userID primitive.ObjectID, currency string) []StatementEntry {
+	var out []StatementEntry
+	entry := func(suffix, typ, desc string, amount float64) {
+		out = append(out, StatementEntry{
+			ID:          t.ID.Hex() + suffix,
+			TransferID:  t.ID,
+			Date:        t.CreatedAt,
+			Type:        typ,
+			Description: desc,
+			Amount:      amount,
+		})
+	}
+
+	src, dst := t.SourceCurrency() == currency, t.DestCurrency() == currency
+	if t.FromUserID == userID && src {
+		entry("", EntryDebit, t.describe("to", t.ToUserID), -t.Amount)
+		if t.Fee > 0 {
+			entry("-fee", EntryFee, "Transfer fee", -t.Fee)
+		}
+	}
+	if t.ToUserID == userID && dst {
+		entry("", EntryCredit, t.describe("from", t.FromUserID), t.Credited())
+	}
+	if t.FeeAccountID != nil && *t.FeeAccountID == userID && t.Fee > 0 && src {
+		entry("-fee-income", EntryFeeIncome, "Fee collected from "+t.FromUserID.Hex(), t.Fee)
+	}
+	return out
+}
+
+func (t Transfer) describe(direction string, other primitive.ObjectID) string {
+	switch {
+	case t.ReversalOf != nil:
+		return "Reversal of " + t.ReversalOf.Hex() + " " + direction + " " + other.Hex()
+	case t.HoldID != nil:
+		return "Captured hold " + direction + " " + other.Hex()
+	case t.BatchID != nil:
+		return "Batch transfer " + direction + " " + other.Hex()
+	case t.ScheduleID != nil:
+		return "Scheduled transfer " + direction + " " + other.Hex()
+	default:
+		return "Transfer " + direction + " " + other.Hex()
+	}
+}
@@ -0,0 +1,67 @@
+package domains
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Account statuses. Only active accounts can send money. Suspended and closed accounts
+// cannot log in, and closed accounts cannot receive money either.
+const (
+	StatusActive    = "active"
+	StatusSuspended = "suspended" // access withdrawn while a case is reviewed
+	StatusFrozen    = "frozen"    // can log in and receive, but no money can leave
+	StatusClosed    = "closed"    // final
+)
+
+// ReconciliationActor is recorded as the actor of status changes made by the reconciliation job.
+const ReconciliationActor = "reconciliation"
+
+// statusTransitions lists where each status may move to.
+var statusTransitions = map[string][]string{
+	StatusActive:    {StatusSuspended, StatusFrozen, StatusClosed},
+	StatusSuspended: {StatusActive, StatusFrozen, StatusClosed},
+	StatusFrozen:    {StatusActive, StatusSuspended, StatusClosed},
+	StatusClosed:    nil,
+}
+
+// CanTransition reports whether an account may move from one status to another.
+func CanTransition(from, to string) bool {
+	for _, s := range statusTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// AccountStatus is u's status. Accounts from before statuses existed are active unless the
+// reconciliation job froze them.
+func (u User) AccountStatus() string {
+	switch {
+	case u.Status != "":
+		return u.Status
+	case u.Frozen:
+		return StatusFrozen
+	default:
+		return StatusActive
+	}
+}
+
+type ChangeStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=active suspended frozen closed"`
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+// StatusChange is the audit record of one status transition. ActorID is the admin who made
+// it, or the name of the job that did.
+type StatusChange struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"userId"`
+	From      string             `bson:"from" json:"from"`
+	To        string             `bson:"to" json:"to"`
+	Reason    string             `bson:"reason" json:"reason"`
+	ActorID   string             `bson:"actor_id" json:"actorId"`
+	CreatedAt time.Time          `bson:"created_at" json:"createdAt"`
+}
@@ -0,0 +1,86 @@
+package domains
+
+import (
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ReversalPolicyFail            = "fail"
+	ReversalPolicyNegativeBalance = "negative_balance"
+)
+
+type Transfer struct {
+	ID             primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	FromUserID     primitive.ObjectID  `bson:"from_user_id" json:"fromUserId"`
+	ToUserID       primitive.ObjectID  `bson:"to_user_id" json:"toUserId"`
+	Amount         float64             `bson:"amount" json:"amount"`
+	Currency       string              `bson:"currency,omitempty" json:"currency,omitempty"`
+	ToCurrency     string              `bson:"to_currency,omitempty" json:"toCurrency,omitempty"`
+	ToAmount       float64             `bson:"to_amount,omitempty" json:"toAmount,omitempty"`
+	Rate           float64             `bson:"rate,omitempty" json:"rate,omitempty"`
+	QuoteID        *primitive.ObjectID `bson:"quote_id,omitempty" json:"quoteId,omitempty"`
+	BaseAmount     float64             `bson:"base_amount,omitempty" json:"-"` // Amount in DefaultCurrency, for limits
+	Fee            float64             `bson:"fee,omitempty" json:"fee,omitempty"`
+	FeeAccountID   *primitive.ObjectID `bson:"fee_account_id,omitempty" json:"-"`
+	HoldID         *primitive.ObjectID `bson:"hold_id,omitempty" json:"holdId,omitempty"`
+	BatchID        *primitive.ObjectID `bson:"batch_id,omitempty" json:"batchId,omitempty"`
+	ScheduleID     *primitive.ObjectID `bson:"schedule_id,omitempty" json:"scheduleId,omitempty"`
+	ReversedAmount float64             `bson:"reversed_amount,omitempty" json:"reversedAmount,omitempty"`
+	RefundedFee    float64             `bson:"refunded_fee,omitempty" json:"refundedFee,omitempty"` // of Fee so far; on a reversal, by it
+	ReversalOf     *primitive.ObjectID `bson:"reversal_of,omitempty" json:"reversalOf,omitempty"`
+	Reason         string              `bson:"reason,omitempty" json:"reason,omitempty"`
+	ActorID        *primitive.ObjectID `bson:"actor_id,omitempty" json:"actorId,omitempty"`
+	CreatedAt      time.Time           `bson:"created_at" json:"createdAt"`
+}
+
+// ReverseTransferRequest refunds part or all of a transfer. A zero Amount refunds whatever is left.
+// The fee the sender paid is refunded in proportion to the amount, so reversing a transfer
+// in full gives back the whole fee.
+type ReverseTransferRequest struct {
+	Amount float64 `json:"amount" validate:"omitempty,money"`
+	Reason string  `json:"reason" validate:"required,max=500"`
+}
+
+// FeeRefund is the part of t's fee to give back when amount of it is reversed: the same
+// share of the fee, or everything not yet refunded once the whole amount has been.
+func (t Transfer) FeeRefund(amount float64) float64 {
+	if t.Fee == 0 {
+		return 0
+	}
+	if t.Amount-t.ReversedAmount-amount <= 1e-9 {
+		return math.Round((t.Fee-t.RefundedFee)*100) / 100
+	}
+	return math.Round(t.Fee*amount/t.Amount*100) / 100
+}
+
+// SourceCurrency is the currency Amount and Fee leave the sender in.
+func (t Transfer) SourceCurrency() string {
+	return NormalizeCurrency(t.Currency)
+}
+
+// DestCurrency is the currency the recipient is credited in.
+func (t Transfer) DestCurrency() string {
+	if t.ToCurrency == "" {
+		return t.SourceCurrency()
+	}
+	return NormalizeCurrency(t.ToCurrency)
+}
+
+// Credited is how much the recipient receives, in DestCurrency.
+func (t Transfer) Credited() float64 {
+	if t.ToAmount == 0 {
+		return t.Amount
+	}
+	return t.ToAmount
+}
+
+// LimitAmount is the amount counted against transfer limits, which are set in DefaultCurrency.
+func (t Transfer) LimitAmount() float64 {
+	if t.BaseAmount == 0 {
+		return t.Amount
+	}
+	return t.BaseAmount
+}
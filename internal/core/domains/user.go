@@ -1,36 +1,153 @@
 package domains
 
 import (
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type User struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty"`
-	Name      string             `bson:"name"`
-	Email     string             `bson:"email"`
-	Password  string             `bson:"password"`
-	Balance   float64            `bson:"balance"`
-	CreatedAt time.Time          `bson:"created_at"`
+	ID             primitive.ObjectID `bson:"_id,omitempty"`
+	Name           string             `bson:"name"`
+	NameKey        string             `bson:"name_key,omitempty"` // SearchKey(Name), for indexed prefix search
+	Email          string             `bson:"email"`
+	EmailKey       string             `bson:"email_key,omitempty" log:"redact"` // SearchKey(Email)
+	EmailVerified  bool               `bson:"email_verified,omitempty"`
+	Handle         string             `bson:"handle,omitempty"` // lower-case, without the leading @
+	Phone          string             `bson:"phone,omitempty"`  // +digits
+	PhoneVerified  bool               `bson:"phone_verified,omitempty"`
+	Password       string             `bson:"password"`
+	Balance        float64            `bson:"balance"`           // available to spend
+	HeldBalance    float64            `bson:"held_balance"`      // reserved by open holds
+	Wallets        map[string]float64 `bson:"wallets,omitempty"` // balances in currencies other than DefaultCurrency
+	Opening        map[string]float64 `bson:"opening,omitempty"` // credited per currency on sign-up; see OpeningBalances
+	CreatedAt      time.Time          `bson:"created_at"`
+	Role           string             `bson:"role,omitempty"`
+	Tier           string             `bson:"tier,omitempty"`
+	LimitOverrides *LimitOverrides    `bson:"limit_overrides,omitempty"`
+	Profile        *Profile           `bson:"profile,omitempty"`
+	Attributes     Attributes         `bson:"attributes,omitempty"` // checked against the configured schemas
+	Status         string             `bson:"status,omitempty"`     // see AccountStatus
+	StatusReason   string             `bson:"status_reason,omitempty"`
+	Frozen         bool               `bson:"frozen,omitempty"` // kept equal to Status == StatusFrozen
+	FrozenReason   string             `bson:"frozen_reason,omitempty"`
+	FrozenAt       *time.Time         `bson:"frozen_at,omitempty"`
+	DeletedAt      *time.Time         `bson:"deleted_at,omitempty"`    // soft-deleted: cannot log in or move money
+	DeletedEmail   string             `bson:"deleted_email,omitempty"` // original email once it has been released for reuse
+	ErasedAt       *time.Time         `bson:"erased_at,omitempty"`     // personal data pseudonymized on request
+	Version        int64              `bson:"version"`                 // incremented by every write, for conditional updates
 }
 
-type CreateUser struct {
-	Name      string
-	Email     string
-	Password  string
-	CreatedAt time.Time
+func (u User) Deleted() bool {
+	return u.DeletedAt != nil
 }
 
+// SearchKey is the form names and emails are stored and searched in: trimmed and lower-case.
+func SearchKey(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+const (
+	RoleUser    = "user"
+	RoleSupport = "support"
+	RoleAdmin   = "admin"
+)
+
+// CreateUserRequest is what a client may set when signing up; everything else on User is
+// decided by the service.
+type CreateUserRequest struct {
+	Name     string `json:"name" validate:"required,name"`
+	Email    string `json:"email" validate:"required,emailaddr"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+	Handle   string `json:"handle" validate:"omitempty,max=31"`
+	Phone    string `json:"phone" validate:"omitempty,max=24"`
+
+	Profile    *Profile   `json:"profile"`
+	Attributes Attributes `json:"attributes"`
+}
+
+// UpdateUserRequest changes a user's profile. Only the fields given are changed; an empty
+// handle or phone removes it. Each attribute namespace given replaces the stored one, and
+// a null namespace removes it.
+type UpdateUserRequest struct {
+	Name   *string `json:"name" validate:"omitempty,name"`
+	Handle *string `json:"handle" validate:"omitempty,max=31"`
+	Phone  *string `json:"phone" validate:"omitempty,max=24"`
+
+	Profile    *ProfileUpdate `json:"profile"`
+	Attributes Attributes     `json:"attributes"`
+}
+
+// UserStatusDeleted filters GET /users to soft-deleted users, whatever their status.
+const UserStatusDeleted = "deleted"
+
+// FindAllUsers filters and pages GET /users. Name and Email match by prefix, ignoring case;
+// Search is a full-text search on names ordered by relevance. Attr matches custom attributes
+// exactly, e.g. ?attr[loyalty.tier]=gold. Pages follow Cursor, the NextCursor of the
+// previous page.
 type FindAllUsers struct {
-	Name  string
-	Email string
-	Page  int
-	Limit int
+	Name           string            `form:"name" validate:"omitempty,max=100"`
+	Email          string            `form:"email" validate:"omitempty,max=254"`
+	Search         string            `form:"search" validate:"omitempty,max=100"`
+	CreatedFrom    *time.Time        `form:"createdFrom"`
+	CreatedTo      *time.Time        `form:"createdTo"`
+	MinBalance     *float64          `form:"minBalance"`
+	MaxBalance     *float64          `form:"maxBalance"`
+	Status         string            `form:"status" validate:"omitempty,oneof=active suspended frozen closed deleted"`
+	Attr           map[string]string `form:"-" validate:"max=5"` // ?attr[path]=value, read with QueryMap
+	Limit          int               `form:"limit" validate:"gte=0"`
+	Cursor         string            `form:"cursor"`
+	Sort           string            `form:"sort"`
+	Total          bool              `form:"total"`
+	IncludeDeleted bool              `form:"includeDeleted"`
+}
+
+// UserQuery is a FindAllUsers checked by the service, with its sort and cursor parsed and
+// its attribute filters typed by their schemas.
+type UserQuery struct {
+	FindAllUsers
+	SortBy     UserSort
+	After      *UserCursor
+	Attributes map[string]interface{} // attribute path, such as loyalty.tier, to the value it must equal
 }
 
+// TransferRequest moves Amount of Currency. The recipient is either ToUserID or To, an
+// alias resolved by ParseAlias. A transfer into a different ToCurrency needs QuoteID, a
+// quote from POST /fx/quotes that locks the rate.
 type TransferRequest struct {
-	FromUserID string  `json:"fromUserId"`
-	ToUserID   string  `json:"toUserId"`
-	Amount     float64 `json:"amount"`
+	FromUserID string  `json:"fromUserId" validate:"required,objectid"`
+	ToUserID   string  `json:"toUserId" validate:"required_without=To,excluded_with=To,omitempty,objectid"`
+	To         string  `json:"to" validate:"omitempty,max=254" log:"redact"` // email, phone or handle
+	Amount     float64 `json:"amount" validate:"required,money"`
+	Currency   string  `json:"currency" validate:"omitempty,currency"`
+	ToCurrency string  `json:"toCurrency" validate:"omitempty,currency"`
+	QuoteID    string  `json:"quoteId" validate:"omitempty,objectid"`
+}
+
+// BalanceIn returns the available balance in currency.
+func (u User) BalanceIn(currency string) float64 {
+	if currency == DefaultCurrency {
+		return u.Balance
+	}
+	return u.Wallets[currency]
+}
+
+// OpeningBalances returns what the account was credited with when it was opened, the
+// starting point of its ledger. Accounts opened before this was recorded were all opened
+// with OpeningBalance.
+func (u User) OpeningBalances() map[string]float64 {
+	if u.Opening == nil {
+		return map[string]float64{DefaultCurrency: OpeningBalance}
+	}
+	return u.Opening
+}
+
+// Balances returns the available balance of every wallet the user holds.
+func (u User) Balances() map[string]float64 {
+	out := map[string]float64{DefaultCurrency: u.Balance}
+	for c, b := range u.Wallets {
+		out[c] = b
+	}
+	return out
 }
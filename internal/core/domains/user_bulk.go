@@ -0,0 +1,80 @@
+package domains
+
+import "strings"
+
+// Formats users can be imported from and exported to.
+const (
+	BulkCSV    = "csv"
+	BulkNDJSON = "ndjson"
+)
+
+func ValidBulkFormat(format string) bool {
+	return format == BulkCSV || format == BulkNDJSON
+}
+
+// ImportRowError is why one row of an import was not created. Line is the row's line in
+// the file, counting a CSV header as line 1.
+type ImportRowError struct {
+	Line   int          `json:"line"`
+	Errors []FieldError `json:"errors"`
+}
+
+// UserImportReport is the outcome of an import. In a dry run Created counts the rows that
+// passed every check and would have been created.
+type UserImportReport struct {
+	DryRun  bool             `json:"dryRun"`
+	Rows    int              `json:"rows"`
+	Created int              `json:"created"`
+	Failed  int              `json:"failed"`
+	Errors  []ImportRowError `json:"errors"`
+}
+
+// userExportFields are the fields an export may include. Passwords are never exported.
+var userExportFields = map[string]func(User) interface{}{
+	"id":             func(u User) interface{} { return u.ID.Hex() },
+	"name":           func(u User) interface{} { return u.Name },
+	"email":          func(u User) interface{} { return u.Email },
+	"handle":         func(u User) interface{} { return u.Handle },
+	"phone":          func(u User) interface{} { return u.Phone },
+	"email_verified": func(u User) interface{} { return u.EmailVerified },
+	"phone_verified": func(u User) interface{} { return u.PhoneVerified },
+	"role":           func(u User) interface{} { return u.Role },
+	"tier":           func(u User) interface{} { return u.Tier },
+	"status":         func(u User) interface{} { return u.AccountStatus() },
+	"balance":        func(u User) interface{} { return u.Balance },
+	"held_balance":   func(u User) interface{} { return u.HeldBalance },
+	"created_at":     func(u User) interface{} { return u.CreatedAt },
+	"deleted_at":     func(u User) interface{} { return u.DeletedAt },
+}
+
+var DefaultUserExportFields = []string{"id", "name", "email", "handle", "phone", "status", "created_at"}
+
+// ParseUserExportFields reads a comma-separated list of export fields, keeping the order
+// given. Empty means DefaultUserExportFields.
+func ParseUserExportFields(s string) ([]string, error) {
+	if strings.TrimSpace(s) == "" {
+		return DefaultUserExportFields, nil
+	}
+	var out []string
+	seen := map[string]bool{}
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+		if _, ok := userExportFields[f]; !ok {
+			return nil, Invalid("fields", "unknown export field "+f)
+		}
+		if !seen[f] {
+			seen[f] = true
+			out = append(out, f)
+		}
+	}
+	return out, nil
+}
+
+// ExportValue is u's value for an export field: a string, bool, float64, time.Time or
+// *time.Time.
+func (u User) ExportValue(field string) interface{} {
+	if get, ok := userExportFields[field]; ok {
+		return get(u)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package domains
+
+import (
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Visibility is how much of an account a viewer may see.
+type Visibility int
+
+const (
+	VisibilityPublic Visibility = iota // any signed-in user
+	VisibilityOwner                    // the account holder
+	VisibilityStaff                    // support and admin
+)
+
+// VisibilityFor returns what viewer may see of the account with userID.
+func VisibilityFor(viewer *JWTClaims, userID string) Visibility {
+	switch {
+	case viewer.Role == RoleAdmin || viewer.Role == RoleSupport:
+		return VisibilityStaff
+	case viewer.ID == userID:
+		return VisibilityOwner
+	default:
+		return VisibilityPublic
+	}
+}
+
+// UserView is the API representation of a user. The `visible` tag on each field is the
+// least Visibility that may see it; Redact clears the rest.
+type UserView struct {
+	ID            primitive.ObjectID `json:"ID" visible:"public"`
+	Name          string             `json:"Name" visible:"public"`
+	Email         string             `json:"Email,omitempty" visible:"owner"`
+	Handle        string             `json:"Handle,omitempty" visible:"public"`
+	CreatedAt     time.Time          `json:"CreatedAt" visible:"public"`
+	Phone         string             `json:"Phone,omitempty" visible:"owner"`
+	EmailVerified *bool              `json:"EmailVerified,omitempty" visible:"owner"`
+	PhoneVerified *bool              `json:"PhoneVerified,omitempty" visible:"owner"`
+	Balance       *float64           `json:"Balance,omitempty" visible:"owner"`
+	HeldBalance   *float64           `json:"HeldBalance,omitempty" visible:"owner"`
+	Balances      map[string]float64 `json:"Balances,omitempty" visible:"owner"`
+	Tier          string             `json:"Tier,omitempty" visible:"owner"`
+	Limits        *TransferLimits    `json:"Limits,omitempty" visible:"owner"`
+	Status        string             `json:"Status,omitempty" visible:"owner"`
+	Frozen        *bool              `json:"Frozen,omitempty" visible:"owner"`
+	Profile       *Profile           `json:"Profile,omitempty" visible:"owner"`
+	Attributes    Attributes         `json:"Attributes,omitempty" visible:"owner"`
+	Role          string             `json:"Role,omitempty" visible:"staff"`
+	StatusReason  string             `json:"StatusReason,omitempty" visible:"staff"`
+	FrozenReason  string             `json:"FrozenReason,omitempty" visible:"staff"`
+	DeletedAt     *time.Time         `json:"DeletedAt,omitempty" visible:"staff"`
+	ErasedAt      *time.Time         `json:"ErasedAt,omitempty" visible:"staff"`
+	Version       int64              `json:"-" visible:"public"` // sent as the ETag
+}
+
+var visibilityTags = map[string]Visibility{
+	"public": VisibilityPublic,
+	"owner":  VisibilityOwner,
+	"staff":  VisibilityStaff,
+}
+
+// View returns every field of u. Limits are left for the caller to fill in; pass the result
+// through Redact before showing it to anyone.
+func (u User) View() UserView {
+	tier := u.Tier
+	if tier == "" {
+		tier = DefaultTier
+	}
+	return UserView{
+		ID:            u.ID,
+		Name:          u.Name,
+		Email:         u.Email,
+		Handle:        u.Handle,
+		CreatedAt:     u.CreatedAt,
+		Phone:         u.Phone,
+		EmailVerified: &u.EmailVerified,
+		PhoneVerified: &u.PhoneVerified,
+		Balance:       &u.Balance,
+		HeldBalance:   &u.HeldBalance,
+		Balances:      u.Balances(),
+		Tier:          tier,
+		Status:        u.AccountStatus(),
+		Frozen:        &u.Frozen,
+		Profile:       u.Profile,
+		Attributes:    u.Attributes,
+		Role:          u.Role,
+		StatusReason:  u.StatusReason,
+		FrozenReason:  u.FrozenReason,
+		DeletedAt:     u.DeletedAt,
+		ErasedAt:      u.ErasedAt,
+		Version:       u.Version,
+	}
+}
+
+// Redact clears every field that level may not see. A field without a `visible` tag is
+// never shown.
+func (v UserView) Redact(level Visibility) UserView {
+	rv := reflect.ValueOf(&v).Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		min, ok := visibilityTags[rt.Field(i).Tag.Get("visible")]
+		if !ok || level < min {
+			rv.Field(i).SetZero()
+		}
+	}
+	return v
+}
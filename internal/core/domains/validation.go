@@ -0,0 +1,170 @@
+package domains
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/mail"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	MaxNameLength  = 100
+	MaxEmailLength = 254
+)
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return f.Name
+		}
+		return name
+	})
+	_ = v.RegisterValidation("name", validName)
+	_ = v.RegisterValidation("emailaddr", validEmail)
+	_ = v.RegisterValidation("money", validMoney)
+	_ = v.RegisterValidation("objectid", validObjectID)
+	_ = v.RegisterValidation("currency", validCurrency)
+	return v
+}
+
+// Validate checks the `validate` tags on a request and reports every failing field.
+// A slice is checked element by element, with fields named "[i].field".
+func Validate(req any) error {
+	rv := reflect.Indirect(reflect.ValueOf(req))
+	if rv.Kind() != reflect.Slice {
+		return fieldErrors("", validate.Struct(req))
+	}
+	var fields []FieldError
+	for i := 0; i < rv.Len(); i++ {
+		if err := fieldErrors(fmt.Sprintf("[%d]", i), validate.Struct(rv.Index(i).Interface())); err != nil {
+			fields = append(fields, err.(*Error).Fields...)
+		}
+	}
+	if len(fields) > 0 {
+		return InvalidFields(fields)
+	}
+	return nil
+}
+
+func fieldErrors(prefix string, err error) error {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return err
+	}
+	fields := make([]FieldError, 0, len(verrs))
+	for _, fe := range verrs {
+		// Namespace is Struct.field.sub; drop the struct name.
+		_, path, _ := strings.Cut(fe.Namespace(), ".")
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+		code, msg := describe(fe)
+		fields = append(fields, FieldError{Field: path, Code: code, Message: fe.Field() + " " + msg})
+	}
+	return InvalidFields(fields)
+}
+
+// describe turns a failed rule into a stable code and the end of a sentence about the field.
+func describe(fe validator.FieldError) (string, string) {
+	sized := fe.Kind() == reflect.String || fe.Kind() == reflect.Slice || fe.Kind() == reflect.Map
+	switch fe.Tag() {
+	case "required", "required_without":
+		return "required", "is required"
+	case "name":
+		return "invalid_name", fmt.Sprintf("must be 1 to %d characters with no control characters", MaxNameLength)
+	case "emailaddr":
+		return "invalid_email", "must be a valid email address"
+	case "money":
+		return "invalid_amount", "must be greater than zero with at most two decimal places"
+	case "objectid":
+		return "invalid_id", "must be a 24-character hex id"
+	case "currency":
+		return "invalid_currency", "must be a three-letter currency code"
+	case "iso3166_1_alpha2":
+		return "invalid_country", "must be a two-letter ISO 3166 country code"
+	case "bcp47_language_tag":
+		return "invalid_locale", "must be a BCP 47 language tag such as th-TH"
+	case "datetime":
+		return "invalid_date", "must be a date in YYYY-MM-DD form"
+	case "oneof":
+		return "invalid_choice", "must be one of: " + fe.Param()
+	case "min", "gte":
+		if sized {
+			return "too_short", "must have at least " + fe.Param() + " characters or items"
+		}
+		return "too_small", "must be at least " + fe.Param()
+	case "max", "lte":
+		if sized {
+			return "too_long", "must have at most " + fe.Param() + " characters or items"
+		}
+		return "too_large", "must be at most " + fe.Param()
+	case "gt":
+		return "too_small", "must be greater than " + fe.Param()
+	case "excluded_with":
+		return "conflicting", "cannot be combined with " + fe.Param()
+	default:
+		return "invalid", "is invalid"
+	}
+}
+
+func validName(fl validator.FieldLevel) bool {
+	s := strings.TrimSpace(fl.Field().String())
+	if s == "" || utf8.RuneCountInString(s) > MaxNameLength {
+		return false
+	}
+	return strings.IndexFunc(s, unicode.IsControl) < 0
+}
+
+// validEmail accepts a bare address such as a@b.co, without a display name.
+func validEmail(fl validator.FieldLevel) bool {
+	s := fl.Field().String()
+	if len(s) > MaxEmailLength {
+		return false
+	}
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s || addr.Name != "" {
+		return false
+	}
+	_, domain, _ := strings.Cut(s, "@")
+	return strings.Contains(domain, ".") && !strings.HasPrefix(domain, ".") && !strings.HasSuffix(domain, ".")
+}
+
+// validMoney accepts positive amounts with at most two decimal places.
+func validMoney(fl validator.FieldLevel) bool {
+	v := fl.Field().Float()
+	if v <= 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+		return false
+	}
+	return math.Abs(v*100-math.Round(v*100)) < 1e-6
+}
+
+func validObjectID(fl validator.FieldLevel) bool {
+	return primitive.IsValidObjectID(fl.Field().String())
+}
+
+func validCurrency(fl validator.FieldLevel) bool {
+	s := fl.Field().String()
+	if len(s) != 3 {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) || r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,14 @@
+package ports
+
+// KeyProvider holds the keys that protect personal data at rest. Data is encrypted with
+// data keys of its own, which are wrapped by a named key-encryption key; only the provider
+// sees those.
+type KeyProvider interface {
+	// ActiveKeyID names the key new data keys are wrapped with.
+	ActiveKeyID() string
+	WrapKey(keyID string, dataKey []byte) ([]byte, error)
+	UnwrapKey(keyID string, wrapped []byte) ([]byte, error)
+	// BlindIndex is a keyed hash of value for finding it without decrypting anything. kind
+	// keeps equal values of different kinds, such as an email and a phone, apart.
+	BlindIndex(kind, value string) string
+}
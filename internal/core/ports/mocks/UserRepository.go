@@ -4,6 +4,7 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 
 	mock "github.com/stretchr/testify/mock"
 	domains "github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
@@ -74,6 +75,54 @@ func (_m *UserRepository) Create(ctx context.Context, data domains.User) (*domai
 	return r0, r1
 }
 
+// EnsureEmailIndex provides a mock function with given fields: ctx
+func (_m *UserRepository) EnsureEmailIndex(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnsureEmailIndex")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExistingIDs provides a mock function with given fields: ctx, ids
+func (_m *UserRepository) ExistingIDs(ctx context.Context, ids []primitive.ObjectID) ([]primitive.ObjectID, error) {
+	ret := _m.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExistingIDs")
+	}
+
+	var r0 []primitive.ObjectID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []primitive.ObjectID) ([]primitive.ObjectID, error)); ok {
+		return rf(ctx, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []primitive.ObjectID) []primitive.ObjectID); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]primitive.ObjectID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []primitive.ObjectID) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindByEmail provides a mock function with given fields: ctx, email
 func (_m *UserRepository) FindByEmail(ctx context.Context, email string) (*domains.User, error) {
 	ret := _m.Called(ctx, email)
@@ -104,6 +153,96 @@ func (_m *UserRepository) FindByEmail(ctx context.Context, email string) (*domai
 	return r0, r1
 }
 
+// FindByHandle provides a mock function with given fields: ctx, handle
+func (_m *UserRepository) FindByHandle(ctx context.Context, handle string) (*domains.User, error) {
+	ret := _m.Called(ctx, handle)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByHandle")
+	}
+
+	var r0 *domains.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domains.User, error)); ok {
+		return rf(ctx, handle)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domains.User); ok {
+		r0 = rf(ctx, handle)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, handle)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByPhone provides a mock function with given fields: ctx, phone
+func (_m *UserRepository) FindByPhone(ctx context.Context, phone string) (*domains.User, error) {
+	ret := _m.Called(ctx, phone)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByPhone")
+	}
+
+	var r0 *domains.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domains.User, error)); ok {
+		return rf(ctx, phone)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domains.User); ok {
+		r0 = rf(ctx, phone)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, phone)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindExisting provides a mock function with given fields: ctx, emails, handles
+func (_m *UserRepository) FindExisting(ctx context.Context, emails []string, handles []string) ([]domains.User, error) {
+	ret := _m.Called(ctx, emails, handles)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindExisting")
+	}
+
+	var r0 []domains.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, []string) ([]domains.User, error)); ok {
+		return rf(ctx, emails, handles)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string, []string) []domains.User); ok {
+		r0 = rf(ctx, emails, handles)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domains.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string, []string) error); ok {
+		r1 = rf(ctx, emails, handles)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByID provides a mock function with given fields: ctx, id
 func (_m *UserRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*domains.User, error) {
 	ret := _m.Called(ctx, id)
@@ -134,29 +273,29 @@ func (_m *UserRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*
 	return r0, r1
 }
 
-// GetUsers provides a mock function with given fields: ctx, data
-func (_m *UserRepository) GetUsers(ctx context.Context, data domains.FindAllUsers) ([]domains.User, error) {
-	ret := _m.Called(ctx, data)
+// GetUsers provides a mock function with given fields: ctx, q
+func (_m *UserRepository) GetUsers(ctx context.Context, q domains.UserQuery) (*domains.UserPage, error) {
+	ret := _m.Called(ctx, q)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUsers")
 	}
 
-	var r0 []domains.User
+	var r0 *domains.UserPage
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, domains.FindAllUsers) ([]domains.User, error)); ok {
-		return rf(ctx, data)
+	if rf, ok := ret.Get(0).(func(context.Context, domains.UserQuery) (*domains.UserPage, error)); ok {
+		return rf(ctx, q)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, domains.FindAllUsers) []domains.User); ok {
-		r0 = rf(ctx, data)
+	if rf, ok := ret.Get(0).(func(context.Context, domains.UserQuery) *domains.UserPage); ok {
+		r0 = rf(ctx, q)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]domains.User)
+			r0 = ret.Get(0).(*domains.UserPage)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, domains.FindAllUsers) error); ok {
-		r1 = rf(ctx, data)
+	if rf, ok := ret.Get(1).(func(context.Context, domains.UserQuery) error); ok {
+		r1 = rf(ctx, q)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -164,17 +303,301 @@ func (_m *UserRepository) GetUsers(ctx context.Context, data domains.FindAllUser
 	return r0, r1
 }
 
-// TransferWithTransaction provides a mock function with given fields: ctx, fromID, toID, amount
-func (_m *UserRepository) TransferWithTransaction(ctx context.Context, fromID primitive.ObjectID, toID primitive.ObjectID, amount float64) error {
-	ret := _m.Called(ctx, fromID, toID, amount)
+// InsertMany provides a mock function with given fields: ctx, users
+func (_m *UserRepository) InsertMany(ctx context.Context, users []domains.User) ([]error, error) {
+	ret := _m.Called(ctx, users)
 
 	if len(ret) == 0 {
-		panic("no return value specified for TransferWithTransaction")
+		panic("no return value specified for InsertMany")
+	}
+
+	var r0 []error
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domains.User) ([]error, error)); ok {
+		return rf(ctx, users)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []domains.User) []error); ok {
+		r0 = rf(ctx, users)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]error)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []domains.User) error); ok {
+		r1 = rf(ctx, users)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListEmails provides a mock function with given fields: ctx
+func (_m *UserRepository) ListEmails(ctx context.Context) ([]domains.EmailRecord, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListEmails")
+	}
+
+	var r0 []domains.EmailRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domains.EmailRecord, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domains.EmailRecord); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domains.EmailRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListStatusChanges provides a mock function with given fields: ctx, userID
+func (_m *UserRepository) ListStatusChanges(ctx context.Context, userID primitive.ObjectID) ([]domains.StatusChange, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListStatusChanges")
+	}
+
+	var r0 []domains.StatusChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) ([]domains.StatusChange, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) []domains.StatusChange); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domains.StatusChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReencryptUsers provides a mock function with given fields: ctx, limit
+func (_m *UserRepository) ReencryptUsers(ctx context.Context, limit int) (int, error) {
+	ret := _m.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReencryptUsers")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseDeletedEmails provides a mock function with given fields: ctx, cutoff
+func (_m *UserRepository) ReleaseDeletedEmails(ctx context.Context, cutoff time.Time) (int64, error) {
+	ret := _m.Called(ctx, cutoff)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseDeletedEmails")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, cutoff)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, cutoff)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, cutoff)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, id
+func (_m *UserRepository) Restore(ctx context.Context, id primitive.ObjectID) (*domains.User, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 *domains.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) (*domains.User, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) *domains.User); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetEmail provides a mock function with given fields: ctx, id, email
+func (_m *UserRepository) SetEmail(ctx context.Context, id primitive.ObjectID, email string) error {
+	ret := _m.Called(ctx, id, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, string) error); ok {
+		r0 = rf(ctx, id, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetStatus provides a mock function with given fields: ctx, change
+func (_m *UserRepository) SetStatus(ctx context.Context, change domains.StatusChange) (*domains.User, error) {
+	ret := _m.Called(ctx, change)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetStatus")
+	}
+
+	var r0 *domains.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domains.StatusChange) (*domains.User, error)); ok {
+		return rf(ctx, change)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domains.StatusChange) *domains.User); ok {
+		r0 = rf(ctx, change)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domains.StatusChange) error); ok {
+		r1 = rf(ctx, change)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetVerified provides a mock function with given fields: ctx, id, in
+func (_m *UserRepository) SetVerified(ctx context.Context, id primitive.ObjectID, in domains.VerifyAliasesRequest) (*domains.User, error) {
+	ret := _m.Called(ctx, id, in)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetVerified")
+	}
+
+	var r0 *domains.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, domains.VerifyAliasesRequest) (*domains.User, error)); ok {
+		return rf(ctx, id, in)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, domains.VerifyAliasesRequest) *domains.User); ok {
+		r0 = rf(ctx, id, in)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID, domains.VerifyAliasesRequest) error); ok {
+		r1 = rf(ctx, id, in)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SoftDelete provides a mock function with given fields: ctx, id, at
+func (_m *UserRepository) SoftDelete(ctx context.Context, id primitive.ObjectID, at time.Time) (*domains.User, error) {
+	ret := _m.Called(ctx, id, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SoftDelete")
+	}
+
+	var r0 *domains.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, time.Time) (*domains.User, error)); ok {
+		return rf(ctx, id, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, time.Time) *domains.User); ok {
+		r0 = rf(ctx, id, at)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID, time.Time) error); ok {
+		r1 = rf(ctx, id, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StreamUsers provides a mock function with given fields: ctx, q, fn
+func (_m *UserRepository) StreamUsers(ctx context.Context, q domains.UserQuery, fn func(domains.User) error) error {
+	ret := _m.Called(ctx, q, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamUsers")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, primitive.ObjectID, float64) error); ok {
-		r0 = rf(ctx, fromID, toID, amount)
+	if rf, ok := ret.Get(0).(func(context.Context, domains.UserQuery, func(domains.User) error) error); ok {
+		r0 = rf(ctx, q, fn)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -182,6 +605,96 @@ func (_m *UserRepository) TransferWithTransaction(ctx context.Context, fromID pr
 	return r0
 }
 
+// TransferWithTransaction provides a mock function with given fields: ctx, in, limits
+func (_m *UserRepository) TransferWithTransaction(ctx context.Context, in domains.Transfer, limits domains.TransferLimits) (*domains.Transfer, error) {
+	ret := _m.Called(ctx, in, limits)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TransferWithTransaction")
+	}
+
+	var r0 *domains.Transfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domains.Transfer, domains.TransferLimits) (*domains.Transfer, error)); ok {
+		return rf(ctx, in, limits)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domains.Transfer, domains.TransferLimits) *domains.Transfer); ok {
+		r0 = rf(ctx, in, limits)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.Transfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domains.Transfer, domains.TransferLimits) error); ok {
+		r1 = rf(ctx, in, limits)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, id, in, version
+func (_m *UserRepository) Update(ctx context.Context, id primitive.ObjectID, in domains.UpdateUserRequest, version *int64) (*domains.User, error) {
+	ret := _m.Called(ctx, id, in, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 *domains.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, domains.UpdateUserRequest, *int64) (*domains.User, error)); ok {
+		return rf(ctx, id, in, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, domains.UpdateUserRequest, *int64) *domains.User); ok {
+		r0 = rf(ctx, id, in, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID, domains.UpdateUserRequest, *int64) error); ok {
+		r1 = rf(ctx, id, in, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateAliases provides a mock function with given fields: ctx, id, in
+func (_m *UserRepository) UpdateAliases(ctx context.Context, id primitive.ObjectID, in domains.UpdateAliasesRequest) (*domains.User, error) {
+	ret := _m.Called(ctx, id, in)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAliases")
+	}
+
+	var r0 *domains.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, domains.UpdateAliasesRequest) (*domains.User, error)); ok {
+		return rf(ctx, id, in)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, domains.UpdateAliasesRequest) *domains.User); ok {
+		r0 = rf(ctx, id, in)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID, domains.UpdateAliasesRequest) error); ok {
+		r1 = rf(ctx, id, in)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewUserRepository creates a new instance of UserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserRepository(t interface {
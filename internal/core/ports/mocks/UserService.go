@@ -4,6 +4,7 @@ package mocks
 
 import (
 	context "context"
+	io "io"
 
 	mock "github.com/stretchr/testify/mock"
 	domains "github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
@@ -14,6 +15,36 @@ type UserService struct {
 	mock.Mock
 }
 
+// ChangeStatus provides a mock function with given fields: ctx, id, actorID, in
+func (_m *UserService) ChangeStatus(ctx context.Context, id string, actorID string, in domains.ChangeStatusRequest) (*domains.User, error) {
+	ret := _m.Called(ctx, id, actorID, in)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangeStatus")
+	}
+
+	var r0 *domains.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domains.ChangeStatusRequest) (*domains.User, error)); ok {
+		return rf(ctx, id, actorID, in)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domains.ChangeStatusRequest) *domains.User); ok {
+		r0 = rf(ctx, id, actorID, in)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, domains.ChangeStatusRequest) error); ok {
+		r1 = rf(ctx, id, actorID, in)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CountUsers provides a mock function with given fields: ctx
 func (_m *UserService) CountUsers(ctx context.Context) (int64, error) {
 	ret := _m.Called(ctx)
@@ -42,9 +73,9 @@ func (_m *UserService) CountUsers(ctx context.Context) (int64, error) {
 	return r0, r1
 }
 
-// CreateUser provides a mock function with given fields: ctx, data
-func (_m *UserService) CreateUser(ctx context.Context, data domains.User) (*domains.User, error) {
-	ret := _m.Called(ctx, data)
+// CreateUser provides a mock function with given fields: ctx, in
+func (_m *UserService) CreateUser(ctx context.Context, in domains.CreateUserRequest) (*domains.User, error) {
+	ret := _m.Called(ctx, in)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateUser")
@@ -52,19 +83,107 @@ func (_m *UserService) CreateUser(ctx context.Context, data domains.User) (*doma
 
 	var r0 *domains.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, domains.User) (*domains.User, error)); ok {
-		return rf(ctx, data)
+	if rf, ok := ret.Get(0).(func(context.Context, domains.CreateUserRequest) (*domains.User, error)); ok {
+		return rf(ctx, in)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, domains.User) *domains.User); ok {
-		r0 = rf(ctx, data)
+	if rf, ok := ret.Get(0).(func(context.Context, domains.CreateUserRequest) *domains.User); ok {
+		r0 = rf(ctx, in)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domains.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, domains.User) error); ok {
-		r1 = rf(ctx, data)
+	if rf, ok := ret.Get(1).(func(context.Context, domains.CreateUserRequest) error); ok {
+		r1 = rf(ctx, in)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteUser provides a mock function with given fields: ctx, id
+func (_m *UserService) DeleteUser(ctx context.Context, id string) (*domains.User, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUser")
+	}
+
+	var r0 *domains.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domains.User, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domains.User); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExportUsers provides a mock function with given fields: ctx, q, format, fields, w
+func (_m *UserService) ExportUsers(ctx context.Context, q domains.FindAllUsers, format string, fields []string, w io.Writer) (int, error) {
+	ret := _m.Called(ctx, q, format, fields, w)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportUsers")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domains.FindAllUsers, string, []string, io.Writer) (int, error)); ok {
+		return rf(ctx, q, format, fields, w)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domains.FindAllUsers, string, []string, io.Writer) int); ok {
+		r0 = rf(ctx, q, format, fields, w)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domains.FindAllUsers, string, []string, io.Writer) error); ok {
+		r1 = rf(ctx, q, format, fields, w)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccount provides a mock function with given fields: ctx, id, level
+func (_m *UserService) GetAccount(ctx context.Context, id string, level domains.Visibility) (*domains.UserView, error) {
+	ret := _m.Called(ctx, id, level)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccount")
+	}
+
+	var r0 *domains.UserView
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domains.Visibility) (*domains.UserView, error)); ok {
+		return rf(ctx, id, level)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domains.Visibility) *domains.UserView); ok {
+		r0 = rf(ctx, id, level)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.UserView)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domains.Visibility) error); ok {
+		r1 = rf(ctx, id, level)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -103,23 +222,23 @@ func (_m *UserService) GetUserByID(ctx context.Context, id string) (*domains.Use
 }
 
 // GetUsers provides a mock function with given fields: ctx, data
-func (_m *UserService) GetUsers(ctx context.Context, data domains.FindAllUsers) ([]domains.User, error) {
+func (_m *UserService) GetUsers(ctx context.Context, data domains.FindAllUsers) (*domains.UserPage, error) {
 	ret := _m.Called(ctx, data)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUsers")
 	}
 
-	var r0 []domains.User
+	var r0 *domains.UserPage
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, domains.FindAllUsers) ([]domains.User, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, domains.FindAllUsers) (*domains.UserPage, error)); ok {
 		return rf(ctx, data)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, domains.FindAllUsers) []domains.User); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, domains.FindAllUsers) *domains.UserPage); ok {
 		r0 = rf(ctx, data)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]domains.User)
+			r0 = ret.Get(0).(*domains.UserPage)
 		}
 	}
 
@@ -132,22 +251,300 @@ func (_m *UserService) GetUsers(ctx context.Context, data domains.FindAllUsers)
 	return r0, r1
 }
 
-// TransferBalance provides a mock function with given fields: ctx, fromID, toID, amount
-func (_m *UserService) TransferBalance(ctx context.Context, fromID string, toID string, amount float64) error {
-	ret := _m.Called(ctx, fromID, toID, amount)
+// ImportUsers provides a mock function with given fields: ctx, format, r, dryRun
+func (_m *UserService) ImportUsers(ctx context.Context, format string, r io.Reader, dryRun bool) (*domains.UserImportReport, error) {
+	ret := _m.Called(ctx, format, r, dryRun)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImportUsers")
+	}
+
+	var r0 *domains.UserImportReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.Reader, bool) (*domains.UserImportReport, error)); ok {
+		return rf(ctx, format, r, dryRun)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.Reader, bool) *domains.UserImportReport); ok {
+		r0 = rf(ctx, format, r, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.UserImportReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.Reader, bool) error); ok {
+		r1 = rf(ctx, format, r, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MigrateEmails provides a mock function with given fields: ctx, apply
+func (_m *UserService) MigrateEmails(ctx context.Context, apply bool) (*domains.EmailMigrationReport, error) {
+	ret := _m.Called(ctx, apply)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MigrateEmails")
+	}
+
+	var r0 *domains.EmailMigrationReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, bool) (*domains.EmailMigrationReport, error)); ok {
+		return rf(ctx, apply)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, bool) *domains.EmailMigrationReport); ok {
+		r0 = rf(ctx, apply)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.EmailMigrationReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, bool) error); ok {
+		r1 = rf(ctx, apply)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReencryptUsers provides a mock function with given fields: ctx
+func (_m *UserService) ReencryptUsers(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReencryptUsers")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseDeletedEmails provides a mock function with given fields: ctx
+func (_m *UserService) ReleaseDeletedEmails(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseDeletedEmails")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreUser provides a mock function with given fields: ctx, id
+func (_m *UserService) RestoreUser(ctx context.Context, id string) (*domains.User, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreUser")
+	}
+
+	var r0 *domains.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domains.User, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domains.User); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StatusHistory provides a mock function with given fields: ctx, id
+func (_m *UserService) StatusHistory(ctx context.Context, id string) ([]domains.StatusChange, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StatusHistory")
+	}
+
+	var r0 []domains.StatusChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domains.StatusChange, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domains.StatusChange); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domains.StatusChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransferBalance provides a mock function with given fields: ctx, in
+func (_m *UserService) TransferBalance(ctx context.Context, in domains.TransferRequest) (*domains.Transfer, error) {
+	ret := _m.Called(ctx, in)
 
 	if len(ret) == 0 {
 		panic("no return value specified for TransferBalance")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, float64) error); ok {
-		r0 = rf(ctx, fromID, toID, amount)
+	var r0 *domains.Transfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domains.TransferRequest) (*domains.Transfer, error)); ok {
+		return rf(ctx, in)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domains.TransferRequest) *domains.Transfer); ok {
+		r0 = rf(ctx, in)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.Transfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domains.TransferRequest) error); ok {
+		r1 = rf(ctx, in)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateAliases provides a mock function with given fields: ctx, id, in
+func (_m *UserService) UpdateAliases(ctx context.Context, id string, in domains.UpdateAliasesRequest) (*domains.User, error) {
+	ret := _m.Called(ctx, id, in)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAliases")
+	}
+
+	var r0 *domains.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domains.UpdateAliasesRequest) (*domains.User, error)); ok {
+		return rf(ctx, id, in)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domains.UpdateAliasesRequest) *domains.User); ok {
+		r0 = rf(ctx, id, in)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domains.UpdateAliasesRequest) error); ok {
+		r1 = rf(ctx, id, in)
 	} else {
-		r0 = ret.Error(0)
+		r1 = ret.Error(1)
 	}
 
-	return r0
+	return r0, r1
+}
+
+// UpdateUser provides a mock function with given fields: ctx, id, in, ifVersion
+func (_m *UserService) UpdateUser(ctx context.Context, id string, in domains.UpdateUserRequest, ifVersion *int64) (*domains.User, error) {
+	ret := _m.Called(ctx, id, in, ifVersion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUser")
+	}
+
+	var r0 *domains.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domains.UpdateUserRequest, *int64) (*domains.User, error)); ok {
+		return rf(ctx, id, in, ifVersion)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domains.UpdateUserRequest, *int64) *domains.User); ok {
+		r0 = rf(ctx, id, in, ifVersion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domains.UpdateUserRequest, *int64) error); ok {
+		r1 = rf(ctx, id, in, ifVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyAliases provides a mock function with given fields: ctx, id, in
+func (_m *UserService) VerifyAliases(ctx context.Context, id string, in domains.VerifyAliasesRequest) (*domains.User, error) {
+	ret := _m.Called(ctx, id, in)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyAliases")
+	}
+
+	var r0 *domains.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domains.VerifyAliasesRequest) (*domains.User, error)); ok {
+		return rf(ctx, id, in)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domains.VerifyAliasesRequest) *domains.User); ok {
+		r0 = rf(ctx, id, in)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domains.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domains.VerifyAliasesRequest) error); ok {
+		r1 = rf(ctx, id, in)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // NewUserService creates a new instance of UserService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -2,6 +2,8 @@ package ports
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -10,10 +12,133 @@ import (
 type UserRepository interface {
 	Create(ctx context.Context, data domains.User) (*domains.User, error)
 	GetByID(ctx context.Context, id primitive.ObjectID) (*domains.User, error)
-	GetUsers(ctx context.Context, data domains.FindAllUsers) ([]domains.User, error)
+	GetUsers(ctx context.Context, q domains.UserQuery) (*domains.UserPage, error)
 	Count(ctx context.Context) (int64, error)
-	TransferWithTransaction(ctx context.Context, fromID, toID primitive.ObjectID, amount float64) error
+	TransferWithTransaction(ctx context.Context, in domains.Transfer, limits domains.TransferLimits) (*domains.Transfer, error)
+	ExistingIDs(ctx context.Context, ids []primitive.ObjectID) ([]primitive.ObjectID, error)
+
+	//Bulk
+	// InsertMany inserts users independently of each other, returning each one's error.
+	InsertMany(ctx context.Context, users []domains.User) ([]error, error)
+	// FindExisting returns users already holding any of emails or handles.
+	FindExisting(ctx context.Context, emails, handles []string) ([]domains.User, error)
+	StreamUsers(ctx context.Context, q domains.UserQuery, fn func(domains.User) error) error
 
 	//Auth
+	// FindByEmail matches email ignoring case.
 	FindByEmail(ctx context.Context, email string) (*domains.User, error)
+
+	//Email identity
+	ListEmails(ctx context.Context) ([]domains.EmailRecord, error)
+	SetEmail(ctx context.Context, id primitive.ObjectID, email string) error
+	// EnsureEmailIndex builds the case-insensitive unique email index and drops the old
+	// case-sensitive one. It fails if two accounts' emails differ only by case.
+	EnsureEmailIndex(ctx context.Context) error
+
+	//Aliases
+	FindByHandle(ctx context.Context, handle string) (*domains.User, error)
+	FindByPhone(ctx context.Context, phone string) (*domains.User, error)
+	UpdateAliases(ctx context.Context, id primitive.ObjectID, in domains.UpdateAliasesRequest) (*domains.User, error)
+	SetVerified(ctx context.Context, id primitive.ObjectID, in domains.VerifyAliasesRequest) (*domains.User, error)
+
+	//Profile
+	// Update changes a user's profile. If version is set, only while the user is still at
+	// that version.
+	Update(ctx context.Context, id primitive.ObjectID, in domains.UpdateUserRequest, version *int64) (*domains.User, error)
+	SoftDelete(ctx context.Context, id primitive.ObjectID, at time.Time) (*domains.User, error)
+	Restore(ctx context.Context, id primitive.ObjectID) (*domains.User, error)
+	// ReleaseDeletedEmails frees the email and aliases of users deleted before cutoff.
+	ReleaseDeletedEmails(ctx context.Context, cutoff time.Time) (int64, error)
+	// ReencryptUsers moves up to limit users' personal data onto the active encryption key.
+	ReencryptUsers(ctx context.Context, limit int) (int, error)
+
+	//Status
+	// SetStatus applies change and records it, returning nil if the user is no longer in
+	// change.From.
+	SetStatus(ctx context.Context, change domains.StatusChange) (*domains.User, error)
+	ListStatusChanges(ctx context.Context, userID primitive.ObjectID) ([]domains.StatusChange, error)
+}
+
+type HoldRepository interface {
+	Create(ctx context.Context, in domains.Hold, limits domains.TransferLimits) (*domains.Hold, error)
+	GetByID(ctx context.Context, id primitive.ObjectID) (*domains.Hold, error)
+	Capture(ctx context.Context, id primitive.ObjectID, payment domains.Transfer, limits domains.TransferLimits) (*domains.Hold, error)
+	Void(ctx context.Context, id primitive.ObjectID) (*domains.Hold, error)
+	ReleaseExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type TransferRepository interface {
+	GetByID(ctx context.Context, id primitive.ObjectID) (*domains.Transfer, error)
+	Reverse(ctx context.Context, reversal domains.Transfer, allowNegative bool) (*domains.Transfer, error)
+	ExecuteBatch(ctx context.Context, batch domains.TransferBatch, limits domains.TransferLimits) (*domains.TransferBatch, error)
+	GetBatchByID(ctx context.Context, id primitive.ObjectID) (*domains.TransferBatch, error)
+}
+
+type ScheduledTransferRepository interface {
+	Create(ctx context.Context, in domains.ScheduledTransfer) (*domains.ScheduledTransfer, error)
+	GetByID(ctx context.Context, id primitive.ObjectID) (*domains.ScheduledTransfer, error)
+	ListByUser(ctx context.Context, userID primitive.ObjectID) ([]domains.ScheduledTransfer, error)
+	ListRuns(ctx context.Context, scheduleID primitive.ObjectID) ([]domains.ScheduledTransferRun, error)
+	SetStatus(ctx context.Context, id primitive.ObjectID, from []string, status string, next *time.Time) (*domains.ScheduledTransfer, error)
+
+	// ClaimDue leases one due schedule to owner so that no other replica runs it until the lease ends.
+	ClaimDue(ctx context.Context, owner string, now time.Time, lease time.Duration) (*domains.ScheduledTransfer, error)
+	// StartRun records an attempt. If that attempt was already recorded it returns the existing run instead.
+	StartRun(ctx context.Context, run domains.ScheduledTransferRun) (*domains.ScheduledTransferRun, bool, error)
+	// ExecuteRun performs the transfer and marks the run succeeded in one transaction.
+	ExecuteRun(ctx context.Context, runID primitive.ObjectID, in domains.Transfer, limits domains.TransferLimits) (*domains.Transfer, error)
+	FailRun(ctx context.Context, runID primitive.ObjectID, reason string) error
+	// Advance saves the schedule's next state if it is still on prev's occurrence and attempt, and drops the lease.
+	Advance(ctx context.Context, prev, next domains.ScheduledTransfer) error
+	ReleaseLease(ctx context.Context, id primitive.ObjectID, owner string) error
+}
+
+type ReconciliationRepository interface {
+	CreateRun(ctx context.Context, run domains.ReconciliationRun) (*domains.ReconciliationRun, error)
+	FinishRun(ctx context.Context, run domains.ReconciliationRun) error
+	// BalancesAfter pages through users in _id order, starting after the given id.
+	BalancesAfter(ctx context.Context, after primitive.ObjectID, limit int) ([]domains.AccountBalance, error)
+	// RecordMismatches adds a page of mismatches to the report.
+	RecordMismatches(ctx context.Context, ms []domains.BalanceMismatch) error
+	// Freeze freezes a user, returning them frozen, or nil if their account was left as it was.
+	Freeze(ctx context.Context, userID primitive.ObjectID, reason string) (*domains.User, error)
+}
+
+type StatementRepository interface {
+	// BalanceBefore is the ledger balance of a user from every transfer created before t.
+	BalanceBefore(ctx context.Context, userID primitive.ObjectID, t time.Time) (float64, error)
+	// StreamTransfers calls fn for each transfer touching userID in [from, to), oldest first, without loading them all.
+	StreamTransfers(ctx context.Context, userID primitive.ObjectID, from, to time.Time, fn func(domains.Transfer) error) error
+	UserIDsAfter(ctx context.Context, after primitive.ObjectID, limit int) ([]primitive.ObjectID, error)
+	PeriodDone(ctx context.Context, period string) (bool, error)
+	MarkPeriodDone(ctx context.Context, period string) error
+	CreateStored(ctx context.Context, in domains.StoredStatement) (*domains.StoredStatement, bool, error)
+	UploadStored(ctx context.Context, id primitive.ObjectID, filename string, write func(w io.Writer) error) error
+	CompleteStored(ctx context.Context, in domains.StoredStatement) error
+	DeleteStored(ctx context.Context, id primitive.ObjectID) error
+	ListStored(ctx context.Context, userID primitive.ObjectID) ([]domains.StoredStatement, error)
+	GetStored(ctx context.Context, id primitive.ObjectID) (*domains.StoredStatement, error)
+	OpenStored(ctx context.Context, id primitive.ObjectID) (io.ReadCloser, error)
+}
+
+type FXRepository interface {
+	UpsertRates(ctx context.Context, rates []domains.FXRate) (int, error)
+	RateAt(ctx context.Context, base, quote string, t time.Time) (*domains.FXRate, error)
+	ListRates(ctx context.Context, at time.Time) ([]domains.FXRate, error)
+	CreateQuote(ctx context.Context, in domains.FXQuote) (*domains.FXQuote, error)
+	GetQuote(ctx context.Context, id primitive.ObjectID) (*domains.FXQuote, error)
+}
+
+type PrivacyRepository interface {
+	// StreamTransfers calls fn for each transfer the user sent or received, oldest first.
+	StreamTransfers(ctx context.Context, userID primitive.ObjectID, fn func(domains.Transfer) error) error
+	ListHolds(ctx context.Context, userID primitive.ObjectID) ([]domains.Hold, error)
+	ListSchedules(ctx context.Context, userID primitive.ObjectID) ([]domains.ScheduledTransfer, error)
+	ListFXQuotes(ctx context.Context, userID primitive.ObjectID) ([]domains.FXQuote, error)
+	ListErasureRequests(ctx context.Context, userID primitive.ObjectID) ([]domains.ErasureRequest, error)
+	CreateErasureRequest(ctx context.Context, in domains.ErasureRequest) (*domains.ErasureRequest, error)
+	PendingErasure(ctx context.Context, userID primitive.ObjectID) (*domains.ErasureRequest, error)
+	// Erase pseudonymizes the user of a pending request and completes it. It returns nil if
+	// the request or the account changed first.
+	Erase(ctx context.Context, req domains.ErasureRequest, actorID string) (*domains.ErasureRequest, error)
 }
@@ -2,18 +2,89 @@ package ports
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
 )
 
 type UserService interface {
-	CreateUser(ctx context.Context, data domains.User) (*domains.User, error)
+	CreateUser(ctx context.Context, in domains.CreateUserRequest) (*domains.User, error)
 	GetUserByID(ctx context.Context, id string) (*domains.User, error)
-	GetUsers(ctx context.Context, data domains.FindAllUsers) ([]domains.User, error)
-	TransferBalance(ctx context.Context, fromID, toID string, amount float64) error
+	GetAccount(ctx context.Context, id string, level domains.Visibility) (*domains.UserView, error)
+	GetUsers(ctx context.Context, data domains.FindAllUsers) (*domains.UserPage, error)
+	TransferBalance(ctx context.Context, in domains.TransferRequest) (*domains.Transfer, error)
 	CountUsers(ctx context.Context) (int64, error)
+	UpdateAliases(ctx context.Context, id string, in domains.UpdateAliasesRequest) (*domains.User, error)
+	VerifyAliases(ctx context.Context, id string, in domains.VerifyAliasesRequest) (*domains.User, error)
+	UpdateUser(ctx context.Context, id string, in domains.UpdateUserRequest, ifVersion *int64) (*domains.User, error)
+	DeleteUser(ctx context.Context, id string) (*domains.User, error)
+	RestoreUser(ctx context.Context, id string) (*domains.User, error)
+	ReleaseDeletedEmails(ctx context.Context) (int64, error)
+	ReencryptUsers(ctx context.Context) (int, error)
+	MigrateEmails(ctx context.Context, apply bool) (*domains.EmailMigrationReport, error)
+	ChangeStatus(ctx context.Context, id, actorID string, in domains.ChangeStatusRequest) (*domains.User, error)
+	StatusHistory(ctx context.Context, id string) ([]domains.StatusChange, error)
+	ImportUsers(ctx context.Context, format string, r io.Reader, dryRun bool) (*domains.UserImportReport, error)
+	ExportUsers(ctx context.Context, q domains.FindAllUsers, format string, fields []string, w io.Writer) (int, error)
+}
+
+type RecipientService interface {
+	LookupRecipient(ctx context.Context, alias string) (*domains.RecipientPreview, error)
 }
 
 type AuthService interface {
 	Login(ctx context.Context, in domains.LoginRequest) (*domains.LoginResponse, error)
 }
+
+type HoldService interface {
+	CreateHold(ctx context.Context, in domains.CreateHoldRequest) (*domains.Hold, error)
+	GetHold(ctx context.Context, id string) (*domains.Hold, error)
+	CaptureHold(ctx context.Context, id string, amount float64) (*domains.Hold, error)
+	VoidHold(ctx context.Context, id string) (*domains.Hold, error)
+	ReleaseExpiredHolds(ctx context.Context) (int64, error)
+}
+
+type TransferService interface {
+	GetTransfer(ctx context.Context, id string) (*domains.Transfer, error)
+	ReverseTransfer(ctx context.Context, id, actorID string, in domains.ReverseTransferRequest) (*domains.Transfer, error)
+	CreateBatch(ctx context.Context, in domains.BatchTransferRequest) (*domains.TransferBatch, error)
+	GetBatch(ctx context.Context, id string) (*domains.TransferBatch, error)
+	QuoteTransfer(ctx context.Context, in domains.QuoteTransferRequest) (*domains.FeeQuote, error)
+}
+
+type ScheduledTransferService interface {
+	CreateScheduledTransfer(ctx context.Context, in domains.CreateScheduledTransferRequest) (*domains.ScheduledTransfer, error)
+	GetScheduledTransfer(ctx context.Context, id string) (*domains.ScheduledTransfer, error)
+	ListScheduledTransfers(ctx context.Context, userID string) ([]domains.ScheduledTransfer, error)
+	ListRuns(ctx context.Context, id string) ([]domains.ScheduledTransferRun, error)
+	PauseScheduledTransfer(ctx context.Context, id string) (*domains.ScheduledTransfer, error)
+	ResumeScheduledTransfer(ctx context.Context, id string) (*domains.ScheduledTransfer, error)
+	CancelScheduledTransfer(ctx context.Context, id string) (*domains.ScheduledTransfer, error)
+	RunDueTransfers(ctx context.Context) (int, error)
+}
+
+type ReconciliationService interface {
+	Reconcile(ctx context.Context, freeze bool) (*domains.ReconciliationRun, error)
+}
+
+type StatementService interface {
+	PrepareStatement(ctx context.Context, userID string, from, to time.Time) (*domains.Statement, error)
+	WriteStatement(ctx context.Context, st *domains.Statement, format string, w io.Writer) error
+	GenerateMonthlyStatements(ctx context.Context, now time.Time) (int, error)
+	ListStatements(ctx context.Context, userID string) ([]domains.StoredStatement, error)
+	OpenStatement(ctx context.Context, userID, id string) (*domains.StoredStatement, io.ReadCloser, error)
+}
+
+type FXService interface {
+	ImportRates(ctx context.Context, rates []domains.FXRate) (int, error)
+	ListRates(ctx context.Context) ([]domains.FXRate, error)
+	CreateQuote(ctx context.Context, in domains.CreateFXQuoteRequest) (*domains.FXQuote, error)
+}
+
+type PrivacyService interface {
+	ExportData(ctx context.Context, userID string, w io.Writer) error
+	RequestErasure(ctx context.Context, userID, actorID string) (*domains.ErasureRequest, error)
+	Erase(ctx context.Context, userID, actorID string) (*domains.ErasureRequest, error)
+	ListErasureRequests(ctx context.Context, userID string) ([]domains.ErasureRequest, error)
+}
@@ -2,7 +2,6 @@ package services
 
 import (
 	"context"
-	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
@@ -24,24 +23,43 @@ func NewAuthService(userrepo ports.UserRepository) ports.AuthService {
 
 func (s *authService) Login(ctx context.Context, in domains.LoginRequest) (*domains.LoginResponse, error) {
 	if in.Email == "" {
-		return nil, errors.New("email is required")
+		return nil, domains.Invalid("email", "email is required")
 	}
 	if in.Password == "" {
-		return nil, errors.New("password is required")
+		return nil, domains.Invalid("password", "password is required")
 	}
 
-	user, err := s.userrepo.FindByEmail(ctx, in.Email)
+	email, err := domains.NormalizeEmail(in.Email)
+	if err != nil {
+		return nil, domains.Unauthorized("invalid_credentials", "invalid email or password")
+	}
+	user, err := s.userrepo.FindByEmail(ctx, email)
 	if err != nil || user == nil {
-		return nil, errors.New("invalid email or password")
+		return nil, domains.Unauthorized("invalid_credentials", "invalid email or password")
 	}
 
 	if err := utils.VerifyPassword(in.Password, user.Password); err != nil {
-		return nil, errors.New("invalid email or password")
+		return nil, domains.Unauthorized("invalid_credentials", "invalid email or password")
+	}
+	if user.Deleted() {
+		return nil, domains.Unauthorized("account_deleted", "account has been deleted")
+	}
+	switch user.AccountStatus() {
+	case domains.StatusSuspended:
+		return nil, domains.Unauthorized("account_suspended", "account is suspended")
+	case domains.StatusClosed:
+		return nil, domains.Unauthorized("account_closed", "account is closed")
+	}
+
+	role := user.Role
+	if role == "" {
+		role = domains.RoleUser
 	}
 
 	claims := domains.JWTClaims{
 		ID:    user.ID.Hex(),
 		Email: user.Email,
+		Role:  role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
 		},
@@ -80,3 +80,52 @@ func TestAuthService_Login_InvalidPassword(t *testing.T) {
 
 	mockRepo.AssertExpectations(t)
 }
+
+func TestAuthService_Login_DeletedUser(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	authService := services.NewAuthService(mockRepo)
+
+	hashedPassword, _ := utils.HashPassword("hashedpassword456")
+	deletedAt := time.Now()
+	mockRepo.On("FindByEmail", mock.Anything, "john@example.com").Return(&domains.User{
+		ID:        primitive.NewObjectID(),
+		Email:     "john@example.com",
+		Password:  hashedPassword,
+		DeletedAt: &deletedAt,
+	}, nil)
+
+	resp, err := authService.Login(context.Background(), domains.LoginRequest{
+		Email:    "john@example.com",
+		Password: "hashedpassword456",
+	})
+
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, domains.ErrUnauthorized)
+	assert.Equal(t, "account has been deleted", err.Error())
+}
+
+func TestAuthService_Login_SuspendedOrClosed(t *testing.T) {
+	hashedPassword, _ := utils.HashPassword("hashedpassword456")
+	for status, code := range map[string]string{
+		domains.StatusSuspended: "account is suspended",
+		domains.StatusClosed:    "account is closed",
+	} {
+		mockRepo := mocks.NewUserRepository(t)
+		authService := services.NewAuthService(mockRepo)
+		mockRepo.On("FindByEmail", mock.Anything, "john@example.com").Return(&domains.User{
+			ID:       primitive.NewObjectID(),
+			Email:    "john@example.com",
+			Password: hashedPassword,
+			Status:   status,
+		}, nil)
+
+		resp, err := authService.Login(context.Background(), domains.LoginRequest{
+			Email:    "john@example.com",
+			Password: "hashedpassword456",
+		})
+
+		assert.Nil(t, resp, status)
+		assert.ErrorIs(t, err, domains.ErrUnauthorized, status)
+		assert.Equal(t, code, err.Error(), status)
+	}
+}
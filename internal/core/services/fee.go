@@ -0,0 +1,64 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/wansanjou/backend-exercise-user-api/config"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// feeRules converts the configured fee schedule into domain rules.
+func feeRules() []domains.FeeRule {
+	cfg := config.Get().Fees
+	rules := make([]domains.FeeRule, 0, len(cfg.Rules))
+	for _, r := range cfg.Rules {
+		rule := domains.FeeRule{
+			Tier:     strings.ToLower(r.Tier),
+			Currency: strings.ToUpper(r.Currency),
+			Type:     r.Type,
+			Amount:   r.Amount,
+			Percent:  r.Percent,
+			Min:      r.Min,
+			Max:      r.Max,
+		}
+		for _, b := range r.Brackets {
+			rule.Brackets = append(rule.Brackets, domains.FeeBracket{UpTo: b.UpTo, Flat: b.Flat, Percent: b.Percent})
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// CheckFees reports a fee schedule that could not be collected: one with rules but no
+// valid house account, which would fail every transfer the rules charge.
+func CheckFees() error {
+	cfg := config.Get().Fees
+	if len(cfg.Rules) == 0 {
+		return nil
+	}
+	if _, err := primitive.ObjectIDFromHex(cfg.HouseAccountID); err != nil {
+		return errors.New("rules are configured but houseAccountId is not a valid account ID")
+	}
+	return nil
+}
+
+// applyFee prices a transfer from u and, when there is a fee, points it at the house account.
+func applyFee(u *domains.User, in *domains.Transfer) error {
+	tier := u.Tier
+	if tier == "" {
+		tier = config.Get().Transfer.DefaultTier
+	}
+	in.Fee = domains.TransferFee(feeRules(), strings.ToLower(tier), *in)
+	if in.Fee == 0 {
+		return nil
+	}
+
+	house, err := primitive.ObjectIDFromHex(config.Get().Fees.HouseAccountID)
+	if err != nil {
+		return domains.NoFeeAccount()
+	}
+	in.FeeAccountID = &house
+	return nil
+}
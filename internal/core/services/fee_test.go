@@ -0,0 +1,93 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports/mocks"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFee(t *testing.T) {
+	rules := []domains.FeeRule{
+		{Tier: "premium", Type: domains.FeeFlat, Amount: 0},
+		{Currency: "USD", Type: domains.FeeFlat, Amount: 2},
+		{Currency: "THB", Type: domains.FeeTiered, Max: 100, Brackets: []domains.FeeBracket{
+			{UpTo: 1000, Flat: 5},
+			{UpTo: 50000, Flat: 10, Percent: 0.1},
+			{Percent: 0.2},
+		}},
+		{Type: domains.FeePercentage, Percent: 0.5, Min: 1, Max: 250},
+	}
+
+	assert.Equal(t, 0.0, domains.Fee(rules, "premium", "THB", 5000))
+	assert.Equal(t, 2.0, domains.Fee(rules, "standard", "USD", 5000))
+	assert.Equal(t, 5.0, domains.Fee(rules, "standard", "THB", 1000))
+	assert.Equal(t, 12.5, domains.Fee(rules, "standard", "THB", 2500))
+	assert.Equal(t, 100.0, domains.Fee(rules, "standard", "THB", 80000))
+	assert.Equal(t, 1.0, domains.Fee(rules, "standard", "EUR", 10))
+	assert.Equal(t, 1.25, domains.Fee(rules, "standard", "EUR", 250))
+	assert.Equal(t, 250.0, domains.Fee(rules, "standard", "EUR", 1000000))
+	assert.Equal(t, 0.0, domains.Fee(nil, "standard", "THB", 1000))
+
+	// A rule without a currency is in THB: at 40 THB to the euro its 1 THB minimum is
+	// 0.03 EUR and its 250 THB maximum 6.25 EUR.
+	eur := func(amount, base float64) domains.Transfer {
+		return domains.Transfer{Currency: "EUR", Amount: amount, BaseAmount: base}
+	}
+	assert.Equal(t, 0.05, domains.TransferFee(rules, "standard", eur(10, 400)))
+	assert.Equal(t, 0.03, domains.TransferFee(rules, "standard", eur(1, 40)))
+	assert.Equal(t, 6.25, domains.TransferFee(rules, "standard", eur(1000000, 40000000)))
+	assert.Equal(t, 2.0, domains.TransferFee(rules, "standard", domains.Transfer{Currency: "USD", Amount: 5000, BaseAmount: 180000}))
+	assert.Equal(t, 12.5, domains.TransferFee(rules, "standard", domains.Transfer{Amount: 2500}))
+}
+
+func TestTransferService_QuoteTransfer(t *testing.T) {
+	mockRepo := mocks.NewTransferRepository(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	transferService := services.NewTransferService(mockRepo, mockUserRepo)
+
+	ctx := context.Background()
+	fromID := primitive.NewObjectID()
+
+	mockUserRepo.On("GetByID", ctx, fromID).Return(&domains.User{ID: fromID, Balance: 100}, nil)
+
+	quote, err := transferService.QuoteTransfer(ctx, domains.QuoteTransferRequest{
+		FromUserID: fromID.Hex(),
+		ToUserID:   primitive.NewObjectID().Hex(),
+		Amount:     40,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &domains.FeeQuote{
+		Currency:          domains.DefaultCurrency,
+		Amount:            40,
+		Total:             40,
+		BalanceBefore:     100,
+		BalanceAfter:      60,
+		RecipientReceives: 40,
+	}, quote)
+}
+
+func TestTransferService_QuoteTransfer_InsufficientBalance(t *testing.T) {
+	mockRepo := mocks.NewTransferRepository(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	transferService := services.NewTransferService(mockRepo, mockUserRepo)
+
+	ctx := context.Background()
+	fromID := primitive.NewObjectID()
+
+	mockUserRepo.On("GetByID", ctx, fromID).Return(&domains.User{ID: fromID, Balance: 10}, nil)
+
+	quote, err := transferService.QuoteTransfer(ctx, domains.QuoteTransferRequest{
+		FromUserID: fromID.Hex(),
+		ToUserID:   primitive.NewObjectID().Hex(),
+		Amount:     40,
+	})
+
+	assert.EqualError(t, err, "insufficient balance")
+	assert.Nil(t, quote)
+}
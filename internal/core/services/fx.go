@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/wansanjou/backend-exercise-user-api/config"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type fxService struct {
+	fxrepo   ports.FXRepository
+	userrepo ports.UserRepository
+}
+
+func NewFXService(fxrepo ports.FXRepository, userrepo ports.UserRepository) ports.FXService {
+	return &fxService{
+		fxrepo:   fxrepo,
+		userrepo: userrepo,
+	}
+}
+
+// ImportRates validates and stores a batch of rates. Nothing is stored if any rate is invalid.
+func (s *fxService) ImportRates(ctx context.Context, rates []domains.FXRate) (int, error) {
+	if len(rates) == 0 {
+		return 0, domains.Invalid("rates", "no rates given")
+	}
+	for i := range rates {
+		r := &rates[i]
+		r.Base, r.Quote = domains.NormalizeCurrency(r.Base), domains.NormalizeCurrency(r.Quote)
+		if r.ValidFrom.IsZero() {
+			r.ValidFrom = time.Now()
+		}
+		r.ValidFrom = r.ValidFrom.UTC()
+		if err := r.Validate(); err != nil {
+			return 0, domains.Invalid(fmt.Sprintf("rates[%d]", i), err.Error())
+		}
+		if !supportedCurrency(r.Base) || !supportedCurrency(r.Quote) {
+			return 0, domains.Invalid(fmt.Sprintf("rates[%d]", i), "unsupported currency")
+		}
+	}
+	return s.fxrepo.UpsertRates(ctx, rates)
+}
+
+func (s *fxService) ListRates(ctx context.Context) ([]domains.FXRate, error) {
+	return s.fxrepo.ListRates(ctx, time.Now().UTC())
+}
+
+// CreateQuote locks today's rate for converting in.Amount so the sender can see what the
+// recipient gets before confirming the transfer.
+func (s *fxService) CreateQuote(ctx context.Context, in domains.CreateFXQuoteRequest) (*domains.FXQuote, error) {
+	from, to := domains.NormalizeCurrency(in.FromCurrency), domains.NormalizeCurrency(in.ToCurrency)
+	if from == to {
+		return nil, domains.Invalid("toCurrency", "currencies must differ")
+	}
+	if !supportedCurrency(from) || !supportedCurrency(to) {
+		return nil, domains.Invalid("currency", "unsupported currency")
+	}
+	if in.Amount <= 0 {
+		return nil, domains.Invalid("amount", "amount must be greater than zero")
+	}
+	uid, err := primitive.ObjectIDFromHex(in.UserID)
+	if err != nil {
+		return nil, domains.Invalid("userId", "invalid user id")
+	}
+	u, err := s.userrepo.GetByID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, domains.NotFound("user")
+	}
+
+	now := time.Now().UTC()
+	rate, err := s.fxrepo.RateAt(ctx, from, to, now)
+	if err != nil {
+		return nil, err
+	}
+	if rate == nil {
+		return nil, domains.Conflict("rate_unavailable", "no rate available for "+from+"/"+to)
+	}
+
+	return s.fxrepo.CreateQuote(ctx, domains.FXQuote{
+		UserID:       uid,
+		FromCurrency: from,
+		ToCurrency:   to,
+		Rate:         rate.Rate,
+		Amount:       in.Amount,
+		ToAmount:     rate.Convert(in.Amount),
+		ExpiresAt:    now.Add(quoteTTL()),
+		CreatedAt:    now,
+	})
+}
+
+// LoadRatesFile imports a JSON array of rates, as served by GET /fx/rates.
+func LoadRatesFile(ctx context.Context, fxsvc ports.FXService, path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	var rates []domains.FXRate
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return 0, fmt.Errorf("parse %s: %w", path, err)
+	}
+	for i := range rates {
+		if rates[i].Source == "" {
+			rates[i].Source = "file"
+		}
+	}
+	return fxsvc.ImportRates(ctx, rates)
+}
+
+// supportedCurrency reports whether users may hold currency. The default currency is always
+// allowed; with no configured list, so is any three-letter code.
+func supportedCurrency(currency string) bool {
+	allowed := config.Get().FX.Currencies
+	if currency == domains.DefaultCurrency || (len(allowed) == 0 && len(currency) == 3) {
+		return true
+	}
+	for _, c := range allowed {
+		if domains.NormalizeCurrency(c) == currency {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteTTL() time.Duration {
+	if secs := config.Get().FX.QuoteTTLSeconds; secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	return domains.DefaultQuoteTTL
+}
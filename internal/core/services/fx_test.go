@@ -0,0 +1,143 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports/mocks"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFXRate_ConvertAndInvert(t *testing.T) {
+	rate := domains.FXRate{Base: "USD", Quote: "THB", Rate: 36.5}
+
+	assert.Equal(t, 365.0, rate.Convert(10))
+	inv := rate.Invert()
+	assert.Equal(t, "THB", inv.Base)
+	assert.Equal(t, "USD", inv.Quote)
+	assert.Equal(t, 10.0, inv.Convert(365))
+}
+
+func TestFXService_CreateQuote(t *testing.T) {
+	mockFXRepo := mocks.NewFXRepository(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	fxService := services.NewFXService(mockFXRepo, mockUserRepo)
+
+	ctx := context.Background()
+	userID := primitive.NewObjectID()
+
+	mockUserRepo.On("GetByID", ctx, userID).Return(&domains.User{ID: userID}, nil)
+	mockFXRepo.On("RateAt", ctx, "USD", "THB", mock.AnythingOfType("time.Time")).
+		Return(&domains.FXRate{Base: "USD", Quote: "THB", Rate: 36.5}, nil)
+	mockFXRepo.On("CreateQuote", ctx, mock.AnythingOfType("domains.FXQuote")).
+		Return(func(_ context.Context, q domains.FXQuote) (*domains.FXQuote, error) { return &q, nil })
+
+	quote, err := fxService.CreateQuote(ctx, domains.CreateFXQuoteRequest{
+		UserID: userID.Hex(), FromCurrency: "usd", ToCurrency: "thb", Amount: 10,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", quote.FromCurrency)
+	assert.Equal(t, "THB", quote.ToCurrency)
+	assert.Equal(t, 365.0, quote.ToAmount)
+	assert.WithinDuration(t, time.Now().Add(domains.DefaultQuoteTTL), quote.ExpiresAt, 5*time.Second)
+}
+
+func TestFXService_CreateQuote_NoRate(t *testing.T) {
+	mockFXRepo := mocks.NewFXRepository(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	fxService := services.NewFXService(mockFXRepo, mockUserRepo)
+
+	ctx := context.Background()
+	userID := primitive.NewObjectID()
+
+	mockUserRepo.On("GetByID", ctx, userID).Return(&domains.User{ID: userID}, nil)
+	mockFXRepo.On("RateAt", ctx, "EUR", "USD", mock.AnythingOfType("time.Time")).Return(nil, nil)
+
+	quote, err := fxService.CreateQuote(ctx, domains.CreateFXQuoteRequest{
+		UserID: userID.Hex(), FromCurrency: "EUR", ToCurrency: "USD", Amount: 10,
+	})
+
+	assert.EqualError(t, err, "no rate available for EUR/USD")
+	assert.Nil(t, quote)
+	mockFXRepo.AssertNotCalled(t, "CreateQuote", mock.Anything, mock.Anything)
+}
+
+func TestTransfer_CrossCurrencyRequiresQuote(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+
+	ctx := context.Background()
+	fromID := primitive.NewObjectID()
+	toID := primitive.NewObjectID()
+
+	mockRepo.On("GetByID", ctx, fromID).Return(&domains.User{ID: fromID, Balance: 100}, nil)
+
+	result, err := userService.TransferBalance(ctx, domains.TransferRequest{
+		FromUserID: fromID.Hex(), ToUserID: toID.Hex(), Amount: 50, ToCurrency: "USD",
+	})
+
+	assert.EqualError(t, err, "a quote is required to transfer between currencies")
+	assert.Nil(t, result)
+}
+
+func TestTransfer_CrossCurrencyWithQuote(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	mockFXRepo := mocks.NewFXRepository(t)
+	userService := services.NewUserService(mockRepo, mockFXRepo)
+
+	ctx := context.Background()
+	fromID := primitive.NewObjectID()
+	toID := primitive.NewObjectID()
+	quote := &domains.FXQuote{
+		ID: primitive.NewObjectID(), UserID: fromID, FromCurrency: "USD", ToCurrency: "THB",
+		Rate: 36.5, Amount: 10, ToAmount: 365, ExpiresAt: time.Now().Add(time.Minute),
+	}
+
+	mockRepo.On("GetByID", ctx, fromID).Return(&domains.User{ID: fromID, Wallets: map[string]float64{"USD": 20}}, nil)
+	mockFXRepo.On("GetQuote", ctx, quote.ID).Return(quote, nil)
+	mockFXRepo.On("RateAt", ctx, "USD", "THB", mock.AnythingOfType("time.Time")).
+		Return(&domains.FXRate{Base: "USD", Quote: "THB", Rate: 36.4}, nil)
+	mockRepo.On("TransferWithTransaction", ctx, mock.MatchedBy(func(in domains.Transfer) bool {
+		return in.Currency == "USD" && in.ToCurrency == "THB" && in.Amount == 10 && in.ToAmount == 365 &&
+			in.Rate == 36.5 && in.BaseAmount == 364 && *in.QuoteID == quote.ID
+	}), domains.TransferLimits{}).Return(&domains.Transfer{ID: primitive.NewObjectID()}, nil)
+
+	result, err := userService.TransferBalance(ctx, domains.TransferRequest{
+		FromUserID: fromID.Hex(), ToUserID: toID.Hex(), Amount: 10,
+		Currency: "USD", ToCurrency: "THB", QuoteID: quote.ID.Hex(),
+	})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+}
+
+func TestTransfer_ExpiredQuote(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	mockFXRepo := mocks.NewFXRepository(t)
+	userService := services.NewUserService(mockRepo, mockFXRepo)
+
+	ctx := context.Background()
+	fromID := primitive.NewObjectID()
+	toID := primitive.NewObjectID()
+	quote := &domains.FXQuote{
+		ID: primitive.NewObjectID(), UserID: fromID, FromCurrency: "THB", ToCurrency: "USD",
+		Rate: 0.0274, Amount: 100, ToAmount: 2.74, ExpiresAt: time.Now().Add(-time.Second),
+	}
+
+	mockRepo.On("GetByID", ctx, fromID).Return(&domains.User{ID: fromID, Balance: 100}, nil)
+	mockFXRepo.On("GetQuote", ctx, quote.ID).Return(quote, nil)
+
+	result, err := userService.TransferBalance(ctx, domains.TransferRequest{
+		FromUserID: fromID.Hex(), ToUserID: toID.Hex(), Amount: 100, ToCurrency: "USD", QuoteID: quote.ID.Hex(),
+	})
+
+	assert.EqualError(t, err, "quote has expired or was already used")
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "TransferWithTransaction", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type holdService struct {
+	holdrepo ports.HoldRepository
+	userrepo ports.UserRepository
+}
+
+func NewHoldService(holdrepo ports.HoldRepository, userrepo ports.UserRepository) ports.HoldService {
+	return &holdService{
+		holdrepo: holdrepo,
+		userrepo: userrepo,
+	}
+}
+
+func (s *holdService) CreateHold(ctx context.Context, in domains.CreateHoldRequest) (*domains.Hold, error) {
+	if in.FromUserID == in.ToUserID {
+		return nil, domains.Invalid("toUserId", "cannot hold funds for the same user")
+	}
+	if in.Amount <= 0 {
+		return nil, domains.Invalid("amount", "amount must be greater than zero")
+	}
+
+	expiry := domains.DefaultHoldExpiry
+	if in.ExpiresInSeconds > 0 {
+		expiry = time.Duration(in.ExpiresInSeconds) * time.Second
+	}
+	if expiry > domains.MaxHoldExpiry {
+		return nil, domains.Invalid("expiresInSeconds", "hold expiry exceeds the maximum of 30 days")
+	}
+
+	foid, err := primitive.ObjectIDFromHex(in.FromUserID)
+	if err != nil {
+		return nil, domains.Invalid("fromUserId", "invalid from user ID")
+	}
+	toid, err := primitive.ObjectIDFromHex(in.ToUserID)
+	if err != nil {
+		return nil, domains.Invalid("toUserId", "invalid to user ID")
+	}
+
+	from, err := s.userrepo.GetByID(ctx, foid)
+	if err != nil {
+		return nil, err
+	}
+	if from == nil {
+		return nil, domains.NotFound("sender")
+	}
+
+	limits, err := transferLimits(from)
+	if err != nil {
+		return nil, err
+	}
+	if err := limits.Check(domains.TransferUsage{}, in.Amount, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return s.holdrepo.Create(ctx, domains.Hold{
+		FromUserID: foid,
+		ToUserID:   toid,
+		Amount:     in.Amount,
+		ExpiresAt:  time.Now().UTC().Add(expiry),
+	}, limits)
+}
+
+func (s *holdService) GetHold(ctx context.Context, id string) (*domains.Hold, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domains.Invalid("id", "invalid id")
+	}
+	hold, err := s.holdrepo.GetByID(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+	if hold == nil {
+		return nil, domains.NotFound("hold")
+	}
+	return hold, nil
+}
+
+func (s *holdService) CaptureHold(ctx context.Context, id string, amount float64) (*domains.Hold, error) {
+	if amount < 0 {
+		return nil, domains.Invalid("amount", "amount must not be negative")
+	}
+	hold, err := s.GetHold(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	from, err := s.userrepo.GetByID(ctx, hold.FromUserID)
+	if err != nil {
+		return nil, err
+	}
+	if from == nil {
+		return nil, domains.NotFound("sender")
+	}
+	if amount == 0 {
+		amount = hold.Amount
+	}
+	if amount > hold.Amount {
+		return nil, domains.Invalid("amount", "capture amount exceeds held amount")
+	}
+	// A capture is a transfer, so it pays the same fee; the fee is charged to the payer's
+	// balance on top of the held amount.
+	payment := domains.Transfer{FromUserID: hold.FromUserID, ToUserID: hold.ToUserID, Amount: amount}
+	if err := applyFee(from, &payment); err != nil {
+		return nil, err
+	}
+	limits, err := transferLimits(from)
+	if err != nil {
+		return nil, err
+	}
+	return s.holdrepo.Capture(ctx, hold.ID, payment, limits)
+}
+
+func (s *holdService) VoidHold(ctx context.Context, id string) (*domains.Hold, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domains.Invalid("id", "invalid id")
+	}
+	return s.holdrepo.Void(ctx, oid)
+}
+
+func (s *holdService) ReleaseExpiredHolds(ctx context.Context) (int64, error) {
+	return s.holdrepo.ReleaseExpired(ctx, time.Now().UTC())
+}
@@ -0,0 +1,127 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports/mocks"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestHoldService_CreateHold(t *testing.T) {
+	mockHoldRepo := mocks.NewHoldRepository(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	holdService := services.NewHoldService(mockHoldRepo, mockUserRepo)
+
+	ctx := context.Background()
+	fromID := primitive.NewObjectID()
+	toID := primitive.NewObjectID()
+
+	mockUserRepo.On("GetByID", ctx, fromID).Return(&domains.User{ID: fromID, Balance: 100}, nil)
+	mockHoldRepo.On("Create", ctx, mock.MatchedBy(func(h domains.Hold) bool {
+		return h.FromUserID == fromID && h.ToUserID == toID && h.Amount == 40 &&
+			time.Until(h.ExpiresAt) > 59*time.Minute && time.Until(h.ExpiresAt) <= time.Hour
+	}), domains.TransferLimits{}).Return(&domains.Hold{ID: primitive.NewObjectID(), Status: domains.HoldActive}, nil)
+
+	hold, err := holdService.CreateHold(ctx, domains.CreateHoldRequest{
+		FromUserID:       fromID.Hex(),
+		ToUserID:         toID.Hex(),
+		Amount:           40,
+		ExpiresInSeconds: 3600,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domains.HoldActive, hold.Status)
+}
+
+func TestHoldService_CreateHold_ExpiryTooLong(t *testing.T) {
+	mockHoldRepo := mocks.NewHoldRepository(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	holdService := services.NewHoldService(mockHoldRepo, mockUserRepo)
+
+	hold, err := holdService.CreateHold(context.Background(), domains.CreateHoldRequest{
+		FromUserID:       primitive.NewObjectID().Hex(),
+		ToUserID:         primitive.NewObjectID().Hex(),
+		Amount:           40,
+		ExpiresInSeconds: int((31 * 24 * time.Hour).Seconds()),
+	})
+
+	assert.Error(t, err)
+	assert.Nil(t, hold)
+}
+
+func TestHoldService_CaptureHold_Partial(t *testing.T) {
+	mockHoldRepo := mocks.NewHoldRepository(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	holdService := services.NewHoldService(mockHoldRepo, mockUserRepo)
+
+	ctx := context.Background()
+	holdID, payer := primitive.NewObjectID(), primitive.NewObjectID()
+	expected := &domains.Hold{ID: holdID, Amount: 40, CapturedAmount: 25, Status: domains.HoldCaptured}
+
+	mockHoldRepo.On("GetByID", ctx, holdID).Return(&domains.Hold{ID: holdID, FromUserID: payer, Amount: 40, Status: domains.HoldActive}, nil)
+	mockUserRepo.On("GetByID", ctx, payer).Return(&domains.User{ID: payer}, nil)
+	// The capture is checked against the payer's limits again.
+	mockHoldRepo.On("Capture", ctx, holdID, mock.MatchedBy(func(p domains.Transfer) bool {
+		return p.Amount == 25 && p.FromUserID == payer
+	}), mock.AnythingOfType("domains.TransferLimits")).Return(expected, nil)
+
+	hold, err := holdService.CaptureHold(ctx, holdID.Hex(), 25)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, hold)
+}
+
+func TestHoldService_CaptureHold_Full(t *testing.T) {
+	mockHoldRepo := mocks.NewHoldRepository(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	holdService := services.NewHoldService(mockHoldRepo, mockUserRepo)
+
+	ctx := context.Background()
+	holdID, payer := primitive.NewObjectID(), primitive.NewObjectID()
+	expected := &domains.Hold{ID: holdID, Amount: 40, CapturedAmount: 40, Status: domains.HoldCaptured}
+
+	mockHoldRepo.On("GetByID", ctx, holdID).Return(&domains.Hold{ID: holdID, FromUserID: payer, Amount: 40, Status: domains.HoldActive}, nil)
+	mockUserRepo.On("GetByID", ctx, payer).Return(&domains.User{ID: payer}, nil)
+	mockHoldRepo.On("Capture", ctx, holdID, mock.MatchedBy(func(p domains.Transfer) bool {
+		return p.Amount == 40
+	}), mock.AnythingOfType("domains.TransferLimits")).Return(expected, nil)
+
+	hold, err := holdService.CaptureHold(ctx, holdID.Hex(), 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, hold)
+
+	_, err = holdService.CaptureHold(ctx, holdID.Hex(), 41)
+	assert.ErrorIs(t, err, domains.ErrValidation)
+}
+
+func TestHoldService_VoidHold_InvalidID(t *testing.T) {
+	mockHoldRepo := mocks.NewHoldRepository(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	holdService := services.NewHoldService(mockHoldRepo, mockUserRepo)
+
+	hold, err := holdService.VoidHold(context.Background(), "not-an-id")
+
+	assert.Error(t, err)
+	assert.Nil(t, hold)
+}
+
+func TestHoldService_ReleaseExpiredHolds(t *testing.T) {
+	mockHoldRepo := mocks.NewHoldRepository(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	holdService := services.NewHoldService(mockHoldRepo, mockUserRepo)
+
+	ctx := context.Background()
+	mockHoldRepo.On("ReleaseExpired", ctx, mock.AnythingOfType("time.Time")).Return(int64(3), nil)
+
+	released, err := holdService.ReleaseExpiredHolds(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), released)
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// exportReadme opens every data export so the person receiving it knows what it holds.
+const exportReadme = `This archive holds the personal data we keep about your account.
+
+profile.json              your account as you see it, including balances and limits
+transfers.ndjson          every transfer you sent or received, one per line, oldest first
+holds.json                money reserved from or for you
+scheduled_transfers.json  your recurring and future-dated transfers
+fx_quotes.json            currency conversion quotes you asked for
+audit.json                changes to your account status, and your erasure requests
+
+Sign-ins use short-lived tokens that are not stored, so there are no sessions to list.
+`
+
+type privacyService struct {
+	privrepo ports.PrivacyRepository
+	userrepo ports.UserRepository
+}
+
+func NewPrivacyService(privrepo ports.PrivacyRepository, userrepo ports.UserRepository) ports.PrivacyService {
+	return &privacyService{
+		privrepo: privrepo,
+		userrepo: userrepo,
+	}
+}
+
+// exportAudit is audit.json of a data export.
+type exportAudit struct {
+	StatusChanges   []domains.StatusChange   `json:"statusChanges"`
+	ErasureRequests []domains.ErasureRequest `json:"erasureRequests"`
+}
+
+// ExportData writes a zip archive of everything held about the user to w. Nothing is
+// written if the user cannot be found.
+func (s *privacyService) ExportData(ctx context.Context, userID string, w io.Writer) error {
+	u, err := s.user(ctx, userID)
+	if err != nil {
+		return err
+	}
+	profile := u.View()
+	limits, err := transferLimits(u)
+	if err != nil {
+		return err
+	}
+	profile.Limits = &limits
+	profile = profile.Redact(domains.VisibilityOwner)
+	holds, err := s.privrepo.ListHolds(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+	schedules, err := s.privrepo.ListSchedules(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+	quotes, err := s.privrepo.ListFXQuotes(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+	var audit exportAudit
+	if audit.StatusChanges, err = s.userrepo.ListStatusChanges(ctx, u.ID); err != nil {
+		return err
+	}
+	if audit.ErasureRequests, err = s.privrepo.ListErasureRequests(ctx, u.ID); err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	now := time.Now().UTC()
+	create := func(name string) (io.Writer, error) {
+		return zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: now})
+	}
+	for _, f := range []struct {
+		name string
+		v    interface{}
+	}{
+		{"profile.json", profile},
+		{"holds.json", holds},
+		{"scheduled_transfers.json", schedules},
+		{"fx_quotes.json", quotes},
+		{"audit.json", audit},
+	} {
+		fw, err := create(f.name)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(fw)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(f.v); err != nil {
+			return err
+		}
+	}
+
+	fw, err := create("transfers.ndjson")
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(fw)
+	if err := s.privrepo.StreamTransfers(ctx, u.ID, func(t domains.Transfer) error { return enc.Encode(t) }); err != nil {
+		return err
+	}
+	fw, err = create("README.txt")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(fw, exportReadme); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// RequestErasure records that the user asked, through actorID, to have their data erased.
+// The erasure itself is carried out by Erase.
+func (s *privacyService) RequestErasure(ctx context.Context, userID, actorID string) (*domains.ErasureRequest, error) {
+	u, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u.Erased() {
+		return nil, domains.Conflict("already_erased", "account has already been erased")
+	}
+	return s.privrepo.CreateErasureRequest(ctx, domains.ErasureRequest{UserID: u.ID, RequestedBy: actorID})
+}
+
+// Erase pseudonymizes the user's personal data on behalf of actorID, completing their
+// pending erasure request or recording one if they asked some other way. The account must
+// hold no money, so the ledger it leaves behind balances.
+func (s *privacyService) Erase(ctx context.Context, userID, actorID string) (*domains.ErasureRequest, error) {
+	u, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u.Erased() {
+		return nil, domains.Conflict("already_erased", "account has already been erased")
+	}
+	if u.HeldBalance != 0 {
+		return nil, domains.Conflict("balance_not_zero", "account has money on hold; release or capture it first")
+	}
+	for _, amount := range u.Balances() {
+		if amount != 0 {
+			return nil, domains.Conflict("balance_not_zero", "account still holds money; pay it out first")
+		}
+	}
+
+	req, err := s.privrepo.PendingErasure(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	if req == nil {
+		if req, err = s.privrepo.CreateErasureRequest(ctx, domains.ErasureRequest{UserID: u.ID, RequestedBy: actorID}); err != nil {
+			return nil, err
+		}
+	}
+
+	done, err := s.privrepo.Erase(ctx, *req, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if done == nil {
+		return nil, domains.Conflict("erasure_conflict", "account changed while it was being erased; try again")
+	}
+	return done, nil
+}
+
+func (s *privacyService) ListErasureRequests(ctx context.Context, userID string) ([]domains.ErasureRequest, error) {
+	u, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.privrepo.ListErasureRequests(ctx, u.ID)
+}
+
+func (s *privacyService) user(ctx context.Context, id string) (*domains.User, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domains.Invalid("id", "invalid id")
+	}
+	u, err := s.userrepo.GetByID(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, domains.NotFound("user")
+	}
+	return u, nil
+}
@@ -0,0 +1,113 @@
+package services_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports/mocks"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestPrivacyService_ExportData(t *testing.T) {
+	mockPrivRepo := mocks.NewPrivacyRepository(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	privacyService := services.NewPrivacyService(mockPrivRepo, mockUserRepo)
+	id, other := primitive.NewObjectID(), primitive.NewObjectID()
+
+	mockUserRepo.On("GetByID", mock.Anything, id).Return(&domains.User{
+		ID: id, Name: "Somchai Jaidee", Email: "somchai@example.com", Password: "hash", Balance: 100, Role: domains.RoleUser,
+	}, nil)
+	mockPrivRepo.On("ListHolds", mock.Anything, id).Return([]domains.Hold{}, nil)
+	mockPrivRepo.On("ListSchedules", mock.Anything, id).Return([]domains.ScheduledTransfer{}, nil)
+	mockPrivRepo.On("ListFXQuotes", mock.Anything, id).Return([]domains.FXQuote{}, nil)
+	mockUserRepo.On("ListStatusChanges", mock.Anything, id).Return([]domains.StatusChange{
+		{UserID: id, From: domains.StatusActive, To: domains.StatusFrozen, Reason: "drift", ActorID: domains.ReconciliationActor},
+	}, nil)
+	mockPrivRepo.On("ListErasureRequests", mock.Anything, id).Return([]domains.ErasureRequest{}, nil)
+	mockPrivRepo.On("StreamTransfers", mock.Anything, id, mock.Anything).Run(func(args mock.Arguments) {
+		fn := args.Get(2).(func(domains.Transfer) error)
+		_ = fn(domains.Transfer{ID: primitive.NewObjectID(), FromUserID: id, ToUserID: other, Amount: 20, CreatedAt: time.Now()})
+		_ = fn(domains.Transfer{ID: primitive.NewObjectID(), FromUserID: other, ToUserID: id, Amount: 5, CreatedAt: time.Now()})
+	}).Return(nil)
+
+	var buf bytes.Buffer
+	err := privacyService.ExportData(context.Background(), id.Hex(), &buf)
+	assert.NoError(t, err)
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		assert.NoError(t, err)
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(b)
+	}
+	assert.Len(t, files, 7)
+	assert.Equal(t, 2, bytes.Count([]byte(files["transfers.ndjson"]), []byte("\n")))
+	assert.Contains(t, files["audit.json"], `"reason": "drift"`)
+
+	var profile map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(files["profile.json"]), &profile))
+	assert.Equal(t, "somchai@example.com", profile["Email"])
+	assert.Equal(t, 100.0, profile["Balance"])
+	assert.NotContains(t, profile, "Password")
+	assert.NotContains(t, profile, "Role", "staff-only fields stay out")
+	assert.NotNil(t, profile["Limits"])
+}
+
+func TestPrivacyService_Erase_RequiresEmptyAccount(t *testing.T) {
+	mockPrivRepo := mocks.NewPrivacyRepository(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	privacyService := services.NewPrivacyService(mockPrivRepo, mockUserRepo)
+	ctx := context.Background()
+	rich, held, erased := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	erasedAt := time.Now()
+
+	mockUserRepo.On("GetByID", mock.Anything, rich).Return(&domains.User{ID: rich, Wallets: map[string]float64{"USD": 1}}, nil)
+	mockUserRepo.On("GetByID", mock.Anything, held).Return(&domains.User{ID: held, HeldBalance: 10}, nil)
+	mockUserRepo.On("GetByID", mock.Anything, erased).Return(&domains.User{ID: erased, ErasedAt: &erasedAt}, nil)
+
+	_, err := privacyService.Erase(ctx, rich.Hex(), "admin-1")
+	assert.ErrorIs(t, err, domains.ErrConflict)
+	_, err = privacyService.Erase(ctx, held.Hex(), "admin-1")
+	assert.ErrorIs(t, err, domains.ErrConflict)
+	_, err = privacyService.Erase(ctx, erased.Hex(), "admin-1")
+	assert.ErrorIs(t, err, domains.ErrConflict)
+	_, err = privacyService.RequestErasure(ctx, erased.Hex(), erased.Hex())
+	assert.ErrorIs(t, err, domains.ErrConflict)
+
+	mockPrivRepo.AssertNotCalled(t, "Erase", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPrivacyService_Erase_RecordsRequest(t *testing.T) {
+	mockPrivRepo := mocks.NewPrivacyRepository(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	privacyService := services.NewPrivacyService(mockPrivRepo, mockUserRepo)
+	id, reqID := primitive.NewObjectID(), primitive.NewObjectID()
+	now := time.Now()
+
+	mockUserRepo.On("GetByID", mock.Anything, id).Return(&domains.User{ID: id}, nil)
+	// Asked by letter rather than through the app: the admin's request is recorded first.
+	mockPrivRepo.On("PendingErasure", mock.Anything, id).Return(nil, nil)
+	mockPrivRepo.On("CreateErasureRequest", mock.Anything, domains.ErasureRequest{UserID: id, RequestedBy: "admin-1"}).
+		Return(&domains.ErasureRequest{ID: reqID, UserID: id, Status: domains.ErasurePending, RequestedBy: "admin-1"}, nil)
+	mockPrivRepo.On("Erase", mock.Anything, mock.MatchedBy(func(r domains.ErasureRequest) bool { return r.ID == reqID }), "admin-1").
+		Return(&domains.ErasureRequest{ID: reqID, UserID: id, Status: domains.ErasureCompleted, CompletedBy: "admin-1", CompletedAt: &now}, nil)
+
+	req, err := privacyService.Erase(context.Background(), id.Hex(), "admin-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, domains.ErasureCompleted, req.Status)
+	assert.Equal(t, &now, req.CompletedAt)
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/wansanjou/backend-exercise-user-api/config"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+)
+
+// attributeSchemas compiles the configured attribute schemas the first time they are needed.
+var attributeSchemas = sync.OnceValues(func() (*domains.AttributeSchemas, error) {
+	return domains.CompileAttributeSchemas(config.Get().Users.AttributeSchemas)
+})
+
+// LoadAttributeSchemas compiles the configured attribute schemas, so a bad one stops the
+// server at startup rather than failing every write.
+func LoadAttributeSchemas() error {
+	_, err := attributeSchemas()
+	return err
+}
+
+// checkAttributes checks custom attributes against their namespaces' schemas.
+func checkAttributes(attrs domains.Attributes) error {
+	if len(attrs) == 0 {
+		return nil
+	}
+	schemas, err := attributeSchemas()
+	if err != nil {
+		return err
+	}
+	return schemas.Validate(attrs)
+}
+
+// attributeFilters types the ?attr[path]=value filters of a user search by their schemas.
+func attributeFilters(attr map[string]string) (map[string]interface{}, error) {
+	if len(attr) == 0 {
+		return nil, nil
+	}
+	schemas, err := attributeSchemas()
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]interface{}, len(attr))
+	for _, path := range slices.Sorted(maps.Keys(attr)) {
+		v, err := schemas.FilterValue(path, attr[path])
+		if err != nil {
+			return nil, err
+		}
+		out[path] = v
+	}
+	return out, nil
+}
@@ -0,0 +1,214 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports/mocks"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const loyaltySchema = `{
+	"type": "object",
+	"properties": {
+		"tier": {"enum": ["bronze", "silver", "gold"]},
+		"points": {"type": "integer", "minimum": 0},
+		"optIn": {"type": "boolean"}
+	},
+	"required": ["tier"],
+	"additionalProperties": false
+}`
+
+func fieldCodes(t *testing.T, err error) map[string]string {
+	t.Helper()
+	codes := map[string]string{}
+	var derr *domains.Error
+	if assert.ErrorAs(t, err, &derr) {
+		for _, f := range derr.Fields {
+			codes[f.Field] = f.Code
+		}
+	}
+	return codes
+}
+
+func TestAttributeSchemas_Validate(t *testing.T) {
+	schemas, err := domains.CompileAttributeSchemas(map[string]string{"loyalty": loyaltySchema})
+	assert.NoError(t, err)
+
+	assert.NoError(t, schemas.Validate(domains.Attributes{
+		"loyalty": {"tier": "gold", "points": 120.0},
+	}))
+	assert.NoError(t, schemas.Validate(domains.Attributes{"loyalty": nil}), "removing a namespace is not checked")
+
+	err = schemas.Validate(domains.Attributes{
+		"loyalty": {"tier": "platinum", "points": -1.0},
+		"growth":  {"campaign": "q4"},
+	})
+	assert.ErrorIs(t, err, domains.ErrValidation)
+	assert.Equal(t, map[string]string{
+		"attributes.growth":         "unknown_namespace",
+		"attributes.loyalty.tier":   "invalid_attribute",
+		"attributes.loyalty.points": "invalid_attribute",
+	}, fieldCodes(t, err))
+
+	err = schemas.Validate(domains.Attributes{"loyalty": {"points": 1.5}})
+	assert.Equal(t, map[string]string{
+		"attributes.loyalty":        "invalid_attribute",
+		"attributes.loyalty.points": "invalid_attribute",
+	}, fieldCodes(t, err), "missing tier is reported on the namespace")
+}
+
+func TestCompileAttributeSchemas_Rejects(t *testing.T) {
+	for name, docs := range map[string]map[string]string{
+		"namespace":  {"Loyalty!": loyaltySchema},
+		"json":       {"loyalty": `{"type": "object",`},
+		"not object": {"loyalty": `{"type": "string"}`},
+		"bad schema": {"loyalty": `{"type": "object", "properties": {"tier": {"type": "colour"}}}`},
+	} {
+		_, err := domains.CompileAttributeSchemas(docs)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestAttributeSchemas_FilterValue(t *testing.T) {
+	schemas, err := domains.CompileAttributeSchemas(map[string]string{"loyalty": loyaltySchema})
+	assert.NoError(t, err)
+
+	v, err := schemas.FilterValue("loyalty.points", "10")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), v)
+	v, err = schemas.FilterValue("loyalty.optIn", "true")
+	assert.NoError(t, err)
+	assert.Equal(t, true, v)
+	v, err = schemas.FilterValue("loyalty.tier", "gold")
+	assert.NoError(t, err)
+	assert.Equal(t, "gold", v)
+
+	for _, path := range []string{"loyalty", "loyalty.level", "growth.campaign"} {
+		_, err = schemas.FilterValue(path, "x")
+		assert.ErrorIs(t, err, domains.ErrValidation, path)
+	}
+	_, err = schemas.FilterValue("loyalty.points", "ten")
+	assert.ErrorIs(t, err, domains.ErrValidation)
+}
+
+func TestAttributeWriters_CheckWrite(t *testing.T) {
+	writers := domains.AttributeWriters{"loyalty": {domains.RoleSupport}, "preferences": {domains.RoleUser}}
+	loyalty := domains.Attributes{"loyalty": {"tier": "gold"}}
+
+	assert.ErrorIs(t, writers.CheckWrite(domains.RoleUser, loyalty), domains.ErrForbidden)
+	assert.ErrorIs(t, writers.CheckWrite(domains.RoleUser, domains.Attributes{"loyalty": nil}), domains.ErrForbidden, "removals too")
+	assert.NoError(t, writers.CheckWrite(domains.RoleSupport, loyalty))
+	assert.NoError(t, writers.CheckWrite(domains.RoleAdmin, domains.Attributes{"unlisted": {}}))
+	assert.NoError(t, writers.CheckWrite(domains.RoleUser, domains.Attributes{"preferences": {"theme": "dark"}}))
+	assert.NoError(t, writers.CheckWrite(domains.RoleUser, nil))
+}
+
+func TestUserService_CreateUser_Profile(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+	ctx := context.Background()
+
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(u domains.User) bool {
+		return u.Profile != nil && u.Profile.Locale == "th-TH" && u.Profile.Address.Country == "TH" &&
+			u.Profile.Marketing.Email && len(u.Attributes) == 0
+	})).Return(&domains.User{}, nil)
+
+	_, err := userService.CreateUser(ctx, domains.CreateUserRequest{
+		Name:     "Somchai",
+		Email:    "somchai@example.com",
+		Password: "password123",
+		Profile: &domains.Profile{
+			Locale:      " th-TH ",
+			DateOfBirth: "1990-04-13",
+			Address:     &domains.Address{Line1: "1 Sukhumvit Rd", City: "Bangkok", Country: "th"},
+			Marketing:   &domains.Marketing{Email: true},
+		},
+		Attributes: domains.Attributes{"loyalty": nil},
+	})
+	assert.NoError(t, err)
+}
+
+func TestUserService_CreateUser_ProfileInvalid(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+	ctx := context.Background()
+	in := func(p *domains.Profile, attrs domains.Attributes) domains.CreateUserRequest {
+		return domains.CreateUserRequest{
+			Name: "Somchai", Email: "somchai@example.com", Password: "password123",
+			Profile: p, Attributes: attrs,
+		}
+	}
+
+	_, err := userService.CreateUser(ctx, in(&domains.Profile{
+		Locale:      "not a locale!",
+		DateOfBirth: "1990-02-30",
+		Address:     &domains.Address{City: "Bangkok", Country: "XX"},
+	}, nil))
+	assert.Equal(t, map[string]string{
+		"profile.locale":          "invalid_locale",
+		"profile.dateOfBirth":     "invalid_date",
+		"profile.address.line1":   "required",
+		"profile.address.country": "invalid_country",
+	}, fieldCodes(t, err))
+
+	tomorrow := time.Now().UTC().AddDate(0, 0, 2).Format(time.DateOnly)
+	_, err = userService.CreateUser(ctx, in(&domains.Profile{DateOfBirth: tomorrow}, nil))
+	assert.ErrorIs(t, err, domains.ErrValidation)
+
+	// No namespaces are configured here, so any attribute is refused.
+	_, err = userService.CreateUser(ctx, in(nil, domains.Attributes{"loyalty": {"tier": "gold"}}))
+	assert.Equal(t, map[string]string{"attributes.loyalty": "unknown_namespace"}, fieldCodes(t, err))
+
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestUserService_UpdateUser_Profile(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+	id := primitive.NewObjectID()
+	sms := true
+
+	mockRepo.On("Update", mock.Anything, id, mock.MatchedBy(func(in domains.UpdateUserRequest) bool {
+		m := in.Profile.Marketing
+		return in.Profile.Address.Country == "TH" && m != nil && *m.SMS && in.Profile.Locale == nil
+	}), (*int64)(nil)).Return(&domains.User{ID: id}, nil)
+
+	_, err := userService.UpdateUser(context.Background(), id.Hex(), domains.UpdateUserRequest{
+		Profile: &domains.ProfileUpdate{
+			Address:   &domains.Address{Line1: "1 Sukhumvit Rd", City: "Bangkok", Country: "th"},
+			Marketing: &domains.MarketingUpdate{SMS: &sms},
+		},
+	}, nil)
+	assert.NoError(t, err)
+
+	// An empty address removes it, so it is not checked.
+	mockRepo.On("Update", mock.Anything, id, mock.MatchedBy(func(in domains.UpdateUserRequest) bool {
+		return *in.Profile.Address == domains.Address{}
+	}), (*int64)(nil)).Return(&domains.User{ID: id}, nil)
+	_, err = userService.UpdateUser(context.Background(), id.Hex(), domains.UpdateUserRequest{
+		Profile: &domains.ProfileUpdate{Address: &domains.Address{}},
+	}, nil)
+	assert.NoError(t, err)
+
+	future := time.Now().UTC().AddDate(1, 0, 0).Format(time.DateOnly)
+	_, err = userService.UpdateUser(context.Background(), id.Hex(), domains.UpdateUserRequest{
+		Profile: &domains.ProfileUpdate{DateOfBirth: &future},
+	}, nil)
+	assert.ErrorIs(t, err, domains.ErrValidation)
+}
+
+func TestUserService_GetUsers_UnknownAttributeFilter(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+
+	_, err := userService.GetUsers(context.Background(), domains.FindAllUsers{Attr: map[string]string{"loyalty.tier": "gold"}})
+
+	assert.ErrorIs(t, err, domains.ErrValidation)
+	mockRepo.AssertNotCalled(t, "GetUsers", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports"
+)
+
+type recipientService struct {
+	userrepo ports.UserRepository
+}
+
+func NewRecipientService(userrepo ports.UserRepository) ports.RecipientService {
+	return &recipientService{
+		userrepo: userrepo,
+	}
+}
+
+// LookupRecipient shows the masked details of whoever alias belongs to, so the sender can
+// check they have the right person before paying.
+func (s *recipientService) LookupRecipient(ctx context.Context, alias string) (*domains.RecipientPreview, error) {
+	a, u, err := resolveRecipient(ctx, s.userrepo, alias)
+	if err != nil {
+		return nil, err
+	}
+	preview := u.Preview(a)
+	return &preview, nil
+}
+
+// resolveRecipient finds the user an alias belongs to. Emails and phone numbers only count
+// once verified, and closed accounts cannot be paid; any miss is reported the same way so
+// callers cannot tell which it was.
+func resolveRecipient(ctx context.Context, userrepo ports.UserRepository, alias string) (domains.Alias, *domains.User, error) {
+	a, err := domains.ParseAlias(alias)
+	if err != nil {
+		return a, nil, err
+	}
+
+	var u *domains.User
+	switch a.Kind {
+	case domains.AliasEmail:
+		u, err = userrepo.FindByEmail(ctx, a.Value)
+		if u != nil && (!u.EmailVerified || u.Deleted()) {
+			u = nil
+		}
+	case domains.AliasHandle:
+		u, err = userrepo.FindByHandle(ctx, a.Value)
+	case domains.AliasPhone:
+		u, err = userrepo.FindByPhone(ctx, a.Value)
+	}
+	if err != nil {
+		return a, nil, err
+	}
+	if u == nil || u.AccountStatus() == domains.StatusClosed {
+		return a, nil, domains.NotFound("recipient")
+	}
+	return a, u, nil
+}
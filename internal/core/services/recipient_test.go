@@ -0,0 +1,113 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports/mocks"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParseAlias(t *testing.T) {
+	a, err := domains.ParseAlias(" @Somchai_J ")
+	assert.NoError(t, err)
+	assert.Equal(t, domains.Alias{Kind: domains.AliasHandle, Value: "somchai_j"}, a)
+
+	a, err = domains.ParseAlias("+66 (81) 234-5678")
+	assert.NoError(t, err)
+	assert.Equal(t, domains.Alias{Kind: domains.AliasPhone, Value: "+66812345678"}, a)
+
+	a, err = domains.ParseAlias("somchai@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, domains.AliasEmail, a.Kind)
+
+	_, err = domains.ParseAlias(primitive.NewObjectID().Hex())
+	assert.Error(t, err)
+	_, err = domains.ParseAlias("@a!")
+	assert.Error(t, err)
+}
+
+func TestRecipientService_LookupRecipient_Masked(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	recipientService := services.NewRecipientService(mockRepo)
+
+	ctx := context.Background()
+	mockRepo.On("FindByHandle", ctx, "somchai").Return(&domains.User{
+		ID: primitive.NewObjectID(), Name: "Somchai Jaidee", Email: "somchai@example.com", Handle: "somchai",
+	}, nil)
+
+	preview, err := recipientService.LookupRecipient(ctx, "@Somchai")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &domains.RecipientPreview{Alias: "@somchai", Kind: domains.AliasHandle, DisplayName: "Som**** J."}, preview)
+}
+
+func TestRecipientService_LookupRecipient_UnverifiedEmail(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	recipientService := services.NewRecipientService(mockRepo)
+
+	ctx := context.Background()
+	mockRepo.On("FindByEmail", ctx, "somchai@example.com").Return(&domains.User{
+		ID: primitive.NewObjectID(), Name: "Somchai Jaidee", Email: "somchai@example.com",
+	}, nil)
+
+	preview, err := recipientService.LookupRecipient(ctx, "somchai@example.com")
+
+	assert.EqualError(t, err, "recipient not found")
+	assert.Nil(t, preview)
+}
+
+func TestTransfer_ToAlias(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+
+	ctx := context.Background()
+	fromID := primitive.NewObjectID()
+	toID := primitive.NewObjectID()
+
+	mockRepo.On("FindByPhone", ctx, "+66812345678").Return(&domains.User{ID: toID, PhoneVerified: true}, nil)
+	mockRepo.On("GetByID", ctx, fromID).Return(&domains.User{ID: fromID, Balance: 100}, nil)
+	mockRepo.On("TransferWithTransaction", ctx, mock.MatchedBy(func(in domains.Transfer) bool {
+		return in.ToUserID == toID && in.Amount == 50
+	}), domains.TransferLimits{}).Return(&domains.Transfer{ID: primitive.NewObjectID()}, nil)
+
+	result, err := userService.TransferBalance(ctx, domains.TransferRequest{
+		FromUserID: fromID.Hex(), To: "+66 81 234 5678", Amount: 50,
+	})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+}
+
+func TestTransfer_ToAlias_SenderCheckedFirst(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+
+	ctx := context.Background()
+	fromID := primitive.NewObjectID()
+	mockRepo.On("GetByID", ctx, fromID).Return(&domains.User{ID: fromID, Balance: 10}, nil)
+
+	// Whether the alias exists cannot be told from a transfer the sender cannot afford.
+	_, err := userService.TransferBalance(ctx, domains.TransferRequest{
+		FromUserID: fromID.Hex(), To: "+66 81 234 5678", Amount: 50,
+	})
+
+	assert.ErrorIs(t, err, domains.ErrInsufficientFunds)
+	mockRepo.AssertNotCalled(t, "FindByPhone", mock.Anything, mock.Anything)
+}
+
+func TestUserService_UpdateAliases_InvalidHandle(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+
+	handle := "no spaces"
+	user, err := userService.UpdateAliases(context.Background(), primitive.NewObjectID().Hex(), domains.UpdateAliasesRequest{Handle: &handle})
+
+	assert.EqualError(t, err, "handle may only contain letters, digits, dots and underscores")
+	assert.Nil(t, user)
+	mockRepo.AssertNotCalled(t, "UpdateAliases", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/wansanjou/backend-exercise-user-api/config"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports"
+	"github.com/wansanjou/backend-exercise-user-api/logging"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type reconciliationService struct {
+	reconrepo ports.ReconciliationRepository
+}
+
+func NewReconciliationService(reconrepo ports.ReconciliationRepository) ports.ReconciliationService {
+	return &reconciliationService{
+		reconrepo: reconrepo,
+	}
+}
+
+// Reconcile recomputes every user's balance in each currency from their opening credit and
+// the transfer history, records each balance that does not match and, when freeze is set,
+// freezes the account so no more money can leave it. Mismatches are logged and recorded in
+// the report as each page is checked; the run only keeps their counts.
+func (s *reconciliationService) Reconcile(ctx context.Context, freeze bool) (*domains.ReconciliationRun, error) {
+	run, err := s.reconrepo.CreateRun(ctx, domains.ReconciliationRun{
+		Status:    domains.ReconciliationRunning,
+		Freeze:    freeze,
+		StartedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.check(ctx, run); err != nil {
+		run.Status = domains.ReconciliationFailed
+		run.Error = err.Error()
+	} else {
+		run.Status = domains.ReconciliationCompleted
+	}
+	finished := time.Now().UTC()
+	run.FinishedAt = &finished
+	if err := s.reconrepo.FinishRun(ctx, *run); err != nil {
+		return run, err
+	}
+	if run.Error != "" {
+		return run, fmt.Errorf("reconciliation failed after %d users: %s", run.Checked, run.Error)
+	}
+	return run, nil
+}
+
+func (s *reconciliationService) check(ctx context.Context, run *domains.ReconciliationRun) error {
+	pageSize := config.Get().Reconciliation.PageSize
+	if pageSize <= 0 {
+		pageSize = domains.DefaultReconciliationPageSize
+	}
+
+	after := primitive.NilObjectID
+	for {
+		page, err := s.reconrepo.BalancesAfter(ctx, after, pageSize)
+		if err != nil {
+			return err
+		}
+		var found []domains.BalanceMismatch
+		for _, acc := range page {
+			run.Checked++
+			mismatches := acc.Mismatches()
+			if len(mismatches) == 0 {
+				continue
+			}
+
+			frozen := acc.Frozen
+			if run.Freeze && !acc.Frozen {
+				m := mismatches[0]
+				reason := fmt.Sprintf("balance drift of %.2f %s found by reconciliation %s", m.Drift, m.Currency, run.ID.Hex())
+				u, err := s.reconrepo.Freeze(ctx, acc.UserID, reason)
+				if err != nil {
+					return err
+				}
+				if u != nil {
+					run.Frozen++
+					frozen = true
+				}
+			}
+			for _, m := range mismatches {
+				m.RunID = run.ID
+				m.DetectedAt = time.Now().UTC()
+				m.Frozen = frozen
+				found = append(found, m)
+			}
+		}
+		// Recorded a page at a time so a run never holds more than one page of the report.
+		if len(found) > 0 {
+			if err := s.reconrepo.RecordMismatches(ctx, found); err != nil {
+				return err
+			}
+		}
+		for _, m := range found {
+			run.Mismatched++
+			logging.FromContext(ctx).Warn("balance mismatch", "runId", run.ID.Hex(), "userId", m.UserID.Hex(),
+				"currency", m.Currency, "drift", m.Drift, "heldDrift", m.HeldDrift, "frozen", m.Frozen)
+		}
+		if len(page) < pageSize {
+			return nil
+		}
+		after = page[len(page)-1].UserID
+	}
+}
@@ -0,0 +1,117 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports/mocks"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestReconciliationService_Reconcile_FreezesDrift(t *testing.T) {
+	mockRepo := mocks.NewReconciliationRepository(t)
+	reconService := services.NewReconciliationService(mockRepo)
+
+	ctx := context.Background()
+	runID := primitive.NewObjectID()
+	okID := primitive.NewObjectID()
+	driftID := primitive.NewObjectID()
+
+	mockRepo.On("CreateRun", ctx, mock.MatchedBy(func(r domains.ReconciliationRun) bool {
+		return r.Status == domains.ReconciliationRunning && r.Freeze
+	})).Return(&domains.ReconciliationRun{ID: runID, Freeze: true}, nil)
+	mockRepo.On("BalancesAfter", ctx, primitive.NilObjectID, domains.DefaultReconciliationPageSize).Return([]domains.AccountBalance{
+		// 100 opening + 50 received - 20 sent - 1 fee, with 30 of it on hold; 5 USD received.
+		{UserID: okID, Balances: map[string]float64{"THB": 99, "USD": 5}, HeldBalance: 30, Opening: thb(100), Ledger: map[string]domains.LedgerTotals{
+			"THB": {Received: 50, Sent: 20, FeesPaid: 1, Held: 30},
+			"USD": {Received: 5},
+		}},
+		{UserID: driftID, Balances: thb(150), Opening: thb(100), Ledger: map[string]domains.LedgerTotals{"THB": {Sent: 10}}},
+	}, nil)
+	mockRepo.On("Freeze", ctx, driftID, mock.Anything).Return(&domains.User{ID: driftID, Status: domains.StatusFrozen}, nil)
+	mockRepo.On("RecordMismatches", ctx, mock.MatchedBy(func(ms []domains.BalanceMismatch) bool {
+		m := ms[0]
+		return len(ms) == 1 && m.UserID == driftID && m.RunID == runID && m.Currency == "THB" && m.ExpectedBalance == 90 && m.Drift == 60 && m.Frozen
+	})).Return(nil)
+	mockRepo.On("FinishRun", ctx, mock.MatchedBy(func(r domains.ReconciliationRun) bool {
+		return r.Status == domains.ReconciliationCompleted && r.Checked == 2 && r.Mismatched == 1 && r.Frozen == 1
+	})).Return(nil)
+
+	run, err := reconService.Reconcile(ctx, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), run.Mismatched)
+	mockRepo.AssertNotCalled(t, "Freeze", ctx, okID, mock.Anything)
+}
+
+func TestReconciliationService_Reconcile_FreezeLeftAlone(t *testing.T) {
+	mockRepo := mocks.NewReconciliationRepository(t)
+	reconService := services.NewReconciliationService(mockRepo)
+
+	ctx := context.Background()
+	closedID := primitive.NewObjectID()
+
+	mockRepo.On("CreateRun", ctx, mock.Anything).Return(&domains.ReconciliationRun{ID: primitive.NewObjectID(), Freeze: true}, nil)
+	mockRepo.On("BalancesAfter", ctx, primitive.NilObjectID, domains.DefaultReconciliationPageSize).Return([]domains.AccountBalance{
+		{UserID: closedID, Balances: thb(150), Opening: thb(100)},
+	}, nil)
+	// Closed accounts cannot be frozen.
+	mockRepo.On("Freeze", ctx, closedID, mock.Anything).Return(nil, nil)
+	mockRepo.On("RecordMismatches", ctx, mock.MatchedBy(func(ms []domains.BalanceMismatch) bool {
+		return len(ms) == 1 && !ms[0].Frozen
+	})).Return(nil)
+	mockRepo.On("FinishRun", ctx, mock.MatchedBy(func(r domains.ReconciliationRun) bool {
+		return r.Mismatched == 1 && r.Frozen == 0
+	})).Return(nil)
+
+	_, err := reconService.Reconcile(ctx, true)
+
+	assert.NoError(t, err)
+}
+
+func TestReconciliationService_Reconcile_ReportOnly(t *testing.T) {
+	mockRepo := mocks.NewReconciliationRepository(t)
+	reconService := services.NewReconciliationService(mockRepo)
+
+	ctx := context.Background()
+	driftID := primitive.NewObjectID()
+
+	mockRepo.On("CreateRun", ctx, mock.Anything).Return(&domains.ReconciliationRun{ID: primitive.NewObjectID()}, nil)
+	mockRepo.On("BalancesAfter", ctx, primitive.NilObjectID, domains.DefaultReconciliationPageSize).Return([]domains.AccountBalance{
+		{UserID: driftID, Balances: thb(100), HeldBalance: 5, Opening: thb(100)},
+	}, nil)
+	mockRepo.On("RecordMismatches", ctx, mock.MatchedBy(func(ms []domains.BalanceMismatch) bool {
+		return len(ms) == 1 && ms[0].UserID == driftID && ms[0].HeldDrift == 5 && !ms[0].Frozen
+	})).Return(nil)
+	mockRepo.On("FinishRun", ctx, mock.Anything).Return(nil)
+
+	run, err := reconService.Reconcile(ctx, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), run.Mismatched)
+	mockRepo.AssertNotCalled(t, "Freeze", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func thb(amount float64) map[string]float64 {
+	return map[string]float64{"THB": amount}
+}
+
+func TestAccountBalance_Mismatches(t *testing.T) {
+	acc := domains.AccountBalance{
+		// Opened with 500 rather than the usual sign-up credit, and 20 USD short.
+		Balances: map[string]float64{"THB": 500, "USD": 10},
+		Opening:  thb(500),
+		Ledger:   map[string]domains.LedgerTotals{"USD": {Received: 40, Sent: 10}},
+	}
+
+	mismatches := acc.Mismatches()
+
+	assert.Len(t, mismatches, 1)
+	assert.Equal(t, "USD", mismatches[0].Currency)
+	assert.Equal(t, 30.0, mismatches[0].ExpectedBalance)
+	assert.Equal(t, -20.0, mismatches[0].Drift)
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports"
+	"github.com/wansanjou/backend-exercise-user-api/logging"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	scheduleLease  = 2 * time.Minute
+	maxRunsPerTick = 100
+)
+
+type scheduleService struct {
+	schedrepo ports.ScheduledTransferRepository
+	userrepo  ports.UserRepository
+	owner     string
+}
+
+func NewScheduledTransferService(schedrepo ports.ScheduledTransferRepository, userrepo ports.UserRepository) ports.ScheduledTransferService {
+	return &scheduleService{
+		schedrepo: schedrepo,
+		userrepo:  userrepo,
+		owner:     leaseOwner(),
+	}
+}
+
+// leaseOwner identifies this process when it claims scheduled transfers.
+func leaseOwner() string {
+	host, _ := os.Hostname()
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(b))
+}
+
+func (s *scheduleService) CreateScheduledTransfer(ctx context.Context, in domains.CreateScheduledTransferRequest) (*domains.ScheduledTransfer, error) {
+	if in.FromUserID == in.ToUserID {
+		return nil, domains.Invalid("toUserId", "cannot transfer to the same user")
+	}
+	if in.Amount <= 0 {
+		return nil, domains.Invalid("amount", "amount must be greater than zero")
+	}
+	foid, err := primitive.ObjectIDFromHex(in.FromUserID)
+	if err != nil {
+		return nil, domains.Invalid("fromUserId", "invalid from user ID")
+	}
+	toid, err := primitive.ObjectIDFromHex(in.ToUserID)
+	if err != nil {
+		return nil, domains.Invalid("toUserId", "invalid to user ID")
+	}
+
+	now := time.Now().UTC()
+	start := now
+	if in.StartAt != nil {
+		start = in.StartAt.UTC()
+	}
+	st := domains.ScheduledTransfer{
+		FromUserID:      foid,
+		ToUserID:        toid,
+		Amount:          in.Amount,
+		Cron:            in.Cron,
+		IntervalSeconds: in.IntervalSeconds,
+		StartAt:         start.Truncate(time.Second),
+		EndAt:           in.EndAt,
+		MaxRuns:         in.MaxRuns,
+		Status:          domains.ScheduleActive,
+	}
+	if err := st.Validate(); err != nil {
+		return nil, err
+	}
+
+	next, err := st.Upcoming(now.Add(-time.Second))
+	if err != nil {
+		return nil, err
+	}
+	if next == nil {
+		return nil, domains.Invalid("schedule", "schedule has no future runs")
+	}
+	st.Occurrence, st.NextRunAt = next, next
+
+	existing, err := s.userrepo.ExistingIDs(ctx, []primitive.ObjectID{foid, toid})
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) != 2 {
+		return nil, domains.NotFound("user")
+	}
+
+	return s.schedrepo.Create(ctx, st)
+}
+
+func (s *scheduleService) GetScheduledTransfer(ctx context.Context, id string) (*domains.ScheduledTransfer, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domains.Invalid("id", "invalid id")
+	}
+	st, err := s.schedrepo.GetByID(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+	if st == nil {
+		return nil, domains.NotFound("scheduled transfer")
+	}
+	return st, nil
+}
+
+func (s *scheduleService) ListScheduledTransfers(ctx context.Context, userID string) ([]domains.ScheduledTransfer, error) {
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, domains.Invalid("userId", "invalid user id")
+	}
+	return s.schedrepo.ListByUser(ctx, oid)
+}
+
+func (s *scheduleService) ListRuns(ctx context.Context, id string) ([]domains.ScheduledTransferRun, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domains.Invalid("id", "invalid id")
+	}
+	return s.schedrepo.ListRuns(ctx, oid)
+}
+
+func (s *scheduleService) PauseScheduledTransfer(ctx context.Context, id string) (*domains.ScheduledTransfer, error) {
+	st, err := s.GetScheduledTransfer(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.schedrepo.SetStatus(ctx, st.ID, []string{domains.ScheduleActive}, domains.SchedulePaused, nil)
+}
+
+// ResumeScheduledTransfer restarts a paused schedule from its next occurrence; runs missed while paused are skipped.
+func (s *scheduleService) ResumeScheduledTransfer(ctx context.Context, id string) (*domains.ScheduledTransfer, error) {
+	st, err := s.GetScheduledTransfer(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	next, err := st.Upcoming(time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if next == nil {
+		return s.schedrepo.SetStatus(ctx, st.ID, []string{domains.SchedulePaused}, domains.ScheduleCompleted, nil)
+	}
+	return s.schedrepo.SetStatus(ctx, st.ID, []string{domains.SchedulePaused}, domains.ScheduleActive, next)
+}
+
+func (s *scheduleService) CancelScheduledTransfer(ctx context.Context, id string) (*domains.ScheduledTransfer, error) {
+	st, err := s.GetScheduledTransfer(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.schedrepo.SetStatus(ctx, st.ID,
+		[]string{domains.ScheduleActive, domains.SchedulePaused}, domains.ScheduleCancelled, nil)
+}
+
+// RunDueTransfers executes every schedule that is due, claiming each with a lease so that
+// replicas running the same job never execute one occurrence twice.
+func (s *scheduleService) RunDueTransfers(ctx context.Context) (int, error) {
+	ran := 0
+	for ran < maxRunsPerTick {
+		st, err := s.schedrepo.ClaimDue(ctx, s.owner, time.Now().UTC(), scheduleLease)
+		if err != nil {
+			return ran, err
+		}
+		if st == nil {
+			return ran, nil
+		}
+		done, err := s.runOnce(ctx, *st)
+		if err != nil {
+			return ran, err
+		}
+		if done {
+			ran++
+		}
+	}
+	return ran, nil
+}
+
+// runOnce makes one attempt at a claimed schedule and reports whether it did anything.
+// It does not when another replica is still executing the attempt; the lease is then kept
+// so that this tick does not claim the schedule again, and the other replica's Advance, or
+// the lease running out if that replica dies, frees it.
+func (s *scheduleService) runOnce(ctx context.Context, st domains.ScheduledTransfer) (bool, error) {
+	now := time.Now().UTC()
+	run, started, err := s.schedrepo.StartRun(ctx, domains.ScheduledTransferRun{
+		ScheduleID:   st.ID,
+		ScheduledFor: *st.Occurrence,
+		Attempt:      st.Attempt + 1,
+		Status:       domains.RunRunning,
+		StartedAt:    now,
+	})
+	if err != nil {
+		if lerr := s.schedrepo.ReleaseLease(ctx, st.ID, s.owner); lerr != nil {
+			// The lease runs out on its own; until then no other replica picks this up.
+			logging.FromContext(ctx).Warn("releasing schedule lease", "scheduleId", st.ID.Hex(), "error", lerr)
+		}
+		return false, err
+	}
+
+	var runErr error
+	switch {
+	case started:
+		runErr = s.execute(ctx, st, run.ID)
+	case run.Status == domains.RunRunning && now.Sub(run.StartedAt) < scheduleLease:
+		// Another replica is still executing this attempt.
+		return false, nil
+	case run.Status == domains.RunRunning:
+		// Its executor died mid-run. The transfer and the run status commit together,
+		// so a run still marked running never moved any money.
+		runErr = errors.New("abandoned after lease expired")
+		if err := s.schedrepo.FailRun(ctx, run.ID, runErr.Error()); err != nil {
+			return false, err
+		}
+	case run.Status == domains.RunFailed:
+		runErr = errors.New(run.Error)
+	}
+
+	return true, s.schedrepo.Advance(ctx, st, s.nextState(st, runErr))
+}
+
+func (s *scheduleService) execute(ctx context.Context, st domains.ScheduledTransfer, runID primitive.ObjectID) error {
+	from, err := s.userrepo.GetByID(ctx, st.FromUserID)
+	if err == nil && from == nil {
+		err = domains.NotFound("sender")
+	}
+	in := domains.Transfer{
+		FromUserID: st.FromUserID,
+		ToUserID:   st.ToUserID,
+		Amount:     st.Amount,
+		ScheduleID: &st.ID,
+	}
+	if err == nil {
+		err = applyFee(from, &in)
+	}
+	var limits domains.TransferLimits
+	if err == nil {
+		limits, err = transferLimits(from)
+	}
+	if err == nil {
+		_, err = s.schedrepo.ExecuteRun(ctx, runID, in, limits)
+	}
+	if err != nil {
+		if ferr := s.schedrepo.FailRun(ctx, runID, err.Error()); ferr != nil {
+			return ferr
+		}
+	}
+	return err
+}
+
+// nextState works out where a schedule goes after an attempt: on to the next occurrence
+// after a success or the last allowed retry, otherwise back for another try after a delay.
+func (s *scheduleService) nextState(st domains.ScheduledTransfer, runErr error) domains.ScheduledTransfer {
+	now := time.Now().UTC()
+	next := st
+	attempt := st.Attempt + 1
+
+	if runErr != nil && attempt < domains.MaxRunAttempts {
+		retry := now.Add(time.Duration(attempt) * domains.RunRetryDelay)
+		next.Attempt = attempt
+		next.NextRunAt = &retry
+		return next
+	}
+
+	next.Attempt = 0
+	if runErr == nil {
+		next.RunCount++
+		next.LastRunAt = &now
+	}
+
+	occ, err := next.Upcoming(*st.Occurrence)
+	if err == nil && occ != nil && !occ.After(now) {
+		// Do not replay every occurrence missed during downtime; resume from the next one.
+		occ, err = next.Upcoming(now)
+	}
+	if err != nil || occ == nil {
+		next.Status = domains.ScheduleCompleted
+		next.Occurrence, next.NextRunAt = nil, nil
+		return next
+	}
+	next.Occurrence, next.NextRunAt = occ, occ
+	return next
+}
@@ -0,0 +1,179 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports/mocks"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestScheduledTransferService_Create_Interval(t *testing.T) {
+	mockSchedRepo := mocks.NewScheduledTransferRepository(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	schedService := services.NewScheduledTransferService(mockSchedRepo, mockUserRepo)
+
+	ctx := context.Background()
+	fromID := primitive.NewObjectID()
+	toID := primitive.NewObjectID()
+	start := time.Now().UTC().Add(48 * time.Hour).Truncate(time.Second)
+
+	mockUserRepo.On("ExistingIDs", ctx, []primitive.ObjectID{fromID, toID}).Return([]primitive.ObjectID{fromID, toID}, nil)
+	mockSchedRepo.On("Create", ctx, mock.MatchedBy(func(st domains.ScheduledTransfer) bool {
+		return st.Status == domains.ScheduleActive && st.NextRunAt.Equal(start) && st.Occurrence.Equal(start)
+	})).Return(&domains.ScheduledTransfer{ID: primitive.NewObjectID()}, nil)
+
+	_, err := schedService.CreateScheduledTransfer(ctx, domains.CreateScheduledTransferRequest{
+		FromUserID:      fromID.Hex(),
+		ToUserID:        toID.Hex(),
+		Amount:          500,
+		IntervalSeconds: 3600,
+		StartAt:         &start,
+		MaxRuns:         12,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestScheduledTransferService_Create_InvalidCron(t *testing.T) {
+	mockSchedRepo := mocks.NewScheduledTransferRepository(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	schedService := services.NewScheduledTransferService(mockSchedRepo, mockUserRepo)
+
+	st, err := schedService.CreateScheduledTransfer(context.Background(), domains.CreateScheduledTransferRequest{
+		FromUserID: primitive.NewObjectID().Hex(),
+		ToUserID:   primitive.NewObjectID().Hex(),
+		Amount:     500,
+		Cron:       "0 9 32 * *",
+	})
+
+	assert.Error(t, err)
+	assert.Nil(t, st)
+}
+
+func TestScheduledTransferService_RunDueTransfers_Success(t *testing.T) {
+	mockSchedRepo := mocks.NewScheduledTransferRepository(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	schedService := services.NewScheduledTransferService(mockSchedRepo, mockUserRepo)
+
+	ctx := context.Background()
+	occurrence := time.Now().UTC().Add(-time.Minute).Truncate(time.Second)
+	st := domains.ScheduledTransfer{
+		ID:              primitive.NewObjectID(),
+		FromUserID:      primitive.NewObjectID(),
+		ToUserID:        primitive.NewObjectID(),
+		Amount:          25,
+		IntervalSeconds: 3600,
+		StartAt:         occurrence,
+		Status:          domains.ScheduleActive,
+		Occurrence:      &occurrence,
+		NextRunAt:       &occurrence,
+	}
+	runID := primitive.NewObjectID()
+
+	mockSchedRepo.On("ClaimDue", ctx, mock.Anything, mock.Anything, mock.Anything).Return(&st, nil).Once()
+	mockSchedRepo.On("ClaimDue", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Once()
+	mockSchedRepo.On("StartRun", ctx, mock.MatchedBy(func(r domains.ScheduledTransferRun) bool {
+		return r.ScheduleID == st.ID && r.ScheduledFor.Equal(occurrence) && r.Attempt == 1
+	})).Return(&domains.ScheduledTransferRun{ID: runID}, true, nil)
+	mockUserRepo.On("GetByID", ctx, st.FromUserID).Return(&domains.User{ID: st.FromUserID}, nil)
+	mockSchedRepo.On("ExecuteRun", ctx, runID, mock.MatchedBy(func(tr domains.Transfer) bool {
+		return tr.Amount == 25 && *tr.ScheduleID == st.ID
+	}), domains.TransferLimits{}).Return(&domains.Transfer{ID: primitive.NewObjectID()}, nil)
+	mockSchedRepo.On("Advance", ctx, st, mock.MatchedBy(func(next domains.ScheduledTransfer) bool {
+		return next.RunCount == 1 && next.Attempt == 0 && next.Status == domains.ScheduleActive &&
+			next.Occurrence.Equal(occurrence.Add(time.Hour)) && next.NextRunAt.Equal(occurrence.Add(time.Hour))
+	})).Return(nil)
+
+	ran, err := schedService.RunDueTransfers(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, ran)
+}
+
+func TestScheduledTransferService_RunDueTransfers_RetriesFailure(t *testing.T) {
+	mockSchedRepo := mocks.NewScheduledTransferRepository(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	schedService := services.NewScheduledTransferService(mockSchedRepo, mockUserRepo)
+
+	ctx := context.Background()
+	occurrence := time.Now().UTC().Add(-time.Minute).Truncate(time.Second)
+	st := domains.ScheduledTransfer{
+		ID:         primitive.NewObjectID(),
+		FromUserID: primitive.NewObjectID(),
+		ToUserID:   primitive.NewObjectID(),
+		Amount:     25,
+		StartAt:    occurrence,
+		Status:     domains.ScheduleActive,
+		Occurrence: &occurrence,
+		NextRunAt:  &occurrence,
+	}
+	runID := primitive.NewObjectID()
+
+	mockSchedRepo.On("ClaimDue", ctx, mock.Anything, mock.Anything, mock.Anything).Return(&st, nil).Once()
+	mockSchedRepo.On("ClaimDue", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Once()
+	mockSchedRepo.On("StartRun", ctx, mock.Anything).Return(&domains.ScheduledTransferRun{ID: runID}, true, nil)
+	mockUserRepo.On("GetByID", ctx, st.FromUserID).Return(&domains.User{ID: st.FromUserID}, nil)
+	mockSchedRepo.On("ExecuteRun", ctx, runID, mock.Anything, mock.Anything).Return(nil, errors.New("insufficient balance"))
+	mockSchedRepo.On("FailRun", ctx, runID, "insufficient balance").Return(nil)
+	mockSchedRepo.On("Advance", ctx, st, mock.MatchedBy(func(next domains.ScheduledTransfer) bool {
+		return next.Attempt == 1 && next.RunCount == 0 && next.Occurrence.Equal(occurrence) &&
+			next.NextRunAt.After(time.Now()) && next.Status == domains.ScheduleActive
+	})).Return(nil)
+
+	ran, err := schedService.RunDueTransfers(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, ran)
+}
+
+func TestScheduledTransferService_RunDueTransfers_SkipsRunInProgress(t *testing.T) {
+	mockSchedRepo := mocks.NewScheduledTransferRepository(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	schedService := services.NewScheduledTransferService(mockSchedRepo, mockUserRepo)
+
+	ctx := context.Background()
+	occurrence := time.Now().UTC().Add(-time.Minute)
+	st := domains.ScheduledTransfer{ID: primitive.NewObjectID(), Status: domains.ScheduleActive, Occurrence: &occurrence}
+
+	mockSchedRepo.On("ClaimDue", ctx, mock.Anything, mock.Anything, mock.Anything).Return(&st, nil).Once()
+	mockSchedRepo.On("ClaimDue", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Once()
+	mockSchedRepo.On("StartRun", ctx, mock.Anything).Return(&domains.ScheduledTransferRun{
+		Status:    domains.RunRunning,
+		StartedAt: time.Now().UTC(),
+	}, false, nil)
+
+	ran, err := schedService.RunDueTransfers(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, ran)
+	mockSchedRepo.AssertNotCalled(t, "ReleaseLease", mock.Anything, mock.Anything, mock.Anything)
+	mockSchedRepo.AssertNotCalled(t, "ExecuteRun", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockSchedRepo.AssertNotCalled(t, "Advance", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	from := time.Date(2026, 1, 31, 10, 0, 0, 0, time.UTC)
+
+	monthly, err := domains.ParseCron("@monthly")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), monthly.Next(from))
+
+	weekdays, err := domains.ParseCron("*/15 9-17 * * 1-5")
+	assert.NoError(t, err)
+	// 2026-01-31 is a Saturday.
+	assert.Equal(t, time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC), weekdays.Next(from))
+
+	rent, err := domains.ParseCron("0 8 31 * *")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 31, 8, 0, 0, 0, time.UTC), rent.Next(from))
+
+	_, err = domains.ParseCron("61 * * * *")
+	assert.Error(t, err)
+}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/wansanjou/backend-exercise-user-api/config"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	statementPageSize = 500
+	// statementClaimTimeout is how long a pending monthly statement may sit before it is
+	// assumed abandoned by a crashed run and regenerated.
+	statementClaimTimeout = 30 * time.Minute
+)
+
+type statementService struct {
+	stmtrepo ports.StatementRepository
+	userrepo ports.UserRepository
+}
+
+func NewStatementService(stmtrepo ports.StatementRepository, userrepo ports.UserRepository) ports.StatementService {
+	return &statementService{
+		stmtrepo: stmtrepo,
+		userrepo: userrepo,
+	}
+}
+
+// PrepareStatement checks the request and works out the opening balance, before anything is written.
+func (s *statementService) PrepareStatement(ctx context.Context, userID string, from, to time.Time) (*domains.Statement, error) {
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, domains.Invalid("userId", "invalid user id")
+	}
+	if !from.Before(to) {
+		return nil, domains.Invalid("from", "from must be before to")
+	}
+	u, err := s.userrepo.GetByID(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, domains.NotFound("user")
+	}
+	return s.prepare(ctx, oid, from, to)
+}
+
+func (s *statementService) prepare(ctx context.Context, userID primitive.ObjectID, from, to time.Time) (*domains.Statement, error) {
+	opening, err := s.stmtrepo.BalanceBefore(ctx, userID, from)
+	if err != nil {
+		return nil, err
+	}
+	return &domains.Statement{
+		UserID:         userID,
+		Currency:       domains.DefaultCurrency,
+		From:           from.UTC(),
+		To:             to.UTC(),
+		OpeningBalance: roundMoney(opening),
+	}, nil
+}
+
+// WriteStatement streams the entries of st to w in format, filling in the closing balance
+// and entry count as it goes.
+func (s *statementService) WriteStatement(ctx context.Context, st *domains.Statement, format string, w io.Writer) error {
+	if !domains.ValidStatementFormat(format) {
+		return domains.Invalid("format", "format must be csv, ndjson or ofx")
+	}
+
+	out := newStatementWriter(format, w)
+	if err := out.Begin(st); err != nil {
+		return err
+	}
+	balance := st.OpeningBalance
+	st.Entries = 0
+	err := s.stmtrepo.StreamTransfers(ctx, st.UserID, st.From, st.To, func(t domains.Transfer) error {
+		for _, e := range t.EntriesFor(st.UserID, st.Currency) {
+			balance = roundMoney(balance + e.Amount)
+			e.Balance = balance
+			st.Entries++
+			if err := out.Entry(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	st.ClosingBalance = balance
+	return out.End(st)
+}
+
+// GenerateMonthlyStatements stores last month's statement for every user in each configured
+// format. Statements already stored are skipped, so an interrupted run picks up where it stopped.
+func (s *statementService) GenerateMonthlyStatements(ctx context.Context, now time.Time) (int, error) {
+	now = now.UTC()
+	to := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	from := to.AddDate(0, -1, 0)
+	period := from.Format(domains.PeriodLayout)
+
+	done, err := s.stmtrepo.PeriodDone(ctx, period)
+	if err != nil || done {
+		return 0, err
+	}
+
+	generated := 0
+	complete := true
+	after := primitive.NilObjectID
+	for {
+		ids, err := s.stmtrepo.UserIDsAfter(ctx, after, statementPageSize)
+		if err != nil {
+			return generated, err
+		}
+		for _, id := range ids {
+			for _, format := range statementFormats() {
+				created, pending, err := s.store(ctx, id, period, format, from, to)
+				if err != nil {
+					return generated, err
+				}
+				if created {
+					generated++
+				}
+				if pending {
+					complete = false
+				}
+			}
+		}
+		if len(ids) < statementPageSize {
+			break
+		}
+		after = ids[len(ids)-1]
+	}
+
+	if !complete {
+		return generated, nil
+	}
+	return generated, s.stmtrepo.MarkPeriodDone(ctx, period)
+}
+
+// store generates one monthly statement unless it already exists. It reports pending when
+// the statement is still being generated elsewhere, so the period cannot be marked done yet.
+func (s *statementService) store(ctx context.Context, userID primitive.ObjectID, period, format string, from, to time.Time) (created, pending bool, err error) {
+	stored, created, err := s.stmtrepo.CreateStored(ctx, domains.StoredStatement{
+		UserID:    userID,
+		Period:    period,
+		Format:    format,
+		Status:    domains.StoredPending,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return false, false, err
+	}
+	if !created {
+		if stored.Status == domains.StoredReady {
+			return false, false, nil
+		}
+		if time.Since(stored.CreatedAt) > statementClaimTimeout {
+			// Left behind by a run that died; drop it so the next run regenerates it.
+			return false, true, s.stmtrepo.DeleteStored(ctx, stored.ID)
+		}
+		return false, true, nil
+	}
+
+	st, err := s.prepare(ctx, userID, from, to)
+	if err == nil {
+		filename := fmt.Sprintf("statement-%s-%s.%s", userID.Hex(), period, format)
+		err = s.stmtrepo.UploadStored(ctx, stored.ID, filename, func(w io.Writer) error {
+			return s.WriteStatement(ctx, st, format, w)
+		})
+	}
+	if err == nil {
+		stored.OpeningBalance, stored.ClosingBalance, stored.Entries = st.OpeningBalance, st.ClosingBalance, st.Entries
+		err = s.stmtrepo.CompleteStored(ctx, *stored)
+	}
+	if err != nil {
+		if derr := s.stmtrepo.DeleteStored(ctx, stored.ID); derr != nil {
+			return false, false, derr
+		}
+		return false, false, err
+	}
+	return true, false, nil
+}
+
+func (s *statementService) ListStatements(ctx context.Context, userID string) ([]domains.StoredStatement, error) {
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, domains.Invalid("userId", "invalid user id")
+	}
+	return s.stmtrepo.ListStored(ctx, oid)
+}
+
+func (s *statementService) OpenStatement(ctx context.Context, userID, id string) (*domains.StoredStatement, io.ReadCloser, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, nil, domains.Invalid("id", "invalid id")
+	}
+	stored, err := s.stmtrepo.GetStored(ctx, oid)
+	if err != nil {
+		return nil, nil, err
+	}
+	if stored == nil || stored.UserID.Hex() != userID || stored.Status != domains.StoredReady {
+		return nil, nil, domains.NotFound("statement")
+	}
+	file, err := s.stmtrepo.OpenStored(ctx, oid)
+	if err != nil {
+		return nil, nil, err
+	}
+	return stored, file, nil
+}
+
+// statementFormats lists the formats monthly statements are stored in.
+func statementFormats() []string {
+	var out []string
+	for _, f := range config.Get().Statements.Formats {
+		if domains.ValidStatementFormat(f) {
+			out = append(out, f)
+		}
+	}
+	if len(out) == 0 {
+		return []string{domains.StatementCSV}
+	}
+	return out
+}
+
+func roundMoney(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+)
+
+// statementWriter renders a statement as it is read, one entry at a time.
+type statementWriter interface {
+	Begin(st *domains.Statement) error
+	Entry(e domains.StatementEntry) error
+	End(st *domains.Statement) error
+}
+
+func newStatementWriter(format string, w io.Writer) statementWriter {
+	switch format {
+	case domains.StatementNDJSON:
+		return &ndjsonStatement{enc: json.NewEncoder(w)}
+	case domains.StatementOFX:
+		return &ofxStatement{w: w}
+	default:
+		return &csvStatement{w: csv.NewWriter(w)}
+	}
+}
+
+func money(v float64) string {
+	return fmt.Sprintf("%.2f", v)
+}
+
+type csvStatement struct {
+	w *csv.Writer
+}
+
+func (c *csvStatement) Begin(st *domains.Statement) error {
+	if err := c.w.Write([]string{"date", "entry_id", "transfer_id", "type", "description", "amount", "balance"}); err != nil {
+		return err
+	}
+	return c.w.Write([]string{st.From.Format(time.RFC3339), "", "", "opening_balance", "", "", money(st.OpeningBalance)})
+}
+
+func (c *csvStatement) Entry(e domains.StatementEntry) error {
+	return c.w.Write([]string{
+		e.Date.UTC().Format(time.RFC3339), e.ID, e.TransferID.Hex(), e.Type, e.Description, money(e.Amount), money(e.Balance),
+	})
+}
+
+func (c *csvStatement) End(st *domains.Statement) error {
+	if err := c.w.Write([]string{st.To.Format(time.RFC3339), "", "", "closing_balance", "", "", money(st.ClosingBalance)}); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// ndjsonStatement writes one JSON object per line: the opening record, each entry, then the closing record.
+type ndjsonStatement struct {
+	enc *json.Encoder
+}
+
+type ndjsonBalance struct {
+	Record   string    `json:"record"`
+	UserID   string    `json:"userId"`
+	Currency string    `json:"currency"`
+	At       time.Time `json:"at"`
+	Balance  float64   `json:"balance"`
+	Entries  *int      `json:"entries,omitempty"`
+}
+
+func (n *ndjsonStatement) Begin(st *domains.Statement) error {
+	return n.enc.Encode(ndjsonBalance{"opening", st.UserID.Hex(), st.Currency, st.From, st.OpeningBalance, nil})
+}
+
+func (n *ndjsonStatement) Entry(e domains.StatementEntry) error {
+	return n.enc.Encode(struct {
+		Record string `json:"record"`
+		domains.StatementEntry
+	}{"entry", e})
+}
+
+func (n *ndjsonStatement) End(st *domains.Statement) error {
+	return n.enc.Encode(ndjsonBalance{"closing", st.UserID.Hex(), st.Currency, st.To, st.ClosingBalance, &st.Entries})
+}
+
+// ofxStatement writes an OFX 2.2 bank statement. OFX puts the ledger balance after the
+// transaction list, so it can be written without knowing the closing balance up front.
+type ofxStatement struct {
+	w io.Writer
+}
+
+func ofxTime(t time.Time) string {
+	return t.UTC().Format("20060102150405") + "[0:GMT]"
+}
+
+func ofxText(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func (o *ofxStatement) Begin(st *domains.Statement) error {
+	now := ofxTime(time.Now())
+	_, err := fmt.Fprintf(o.w, `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+<OFX>
+<SIGNONMSGSRSV1><SONRS><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS><DTSERVER>%s</DTSERVER><LANGUAGE>ENG</LANGUAGE></SONRS></SIGNONMSGSRSV1>
+<BANKMSGSRSV1><STMTTRNRS><TRNUID>%s</TRNUID><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>
+<STMTRS><CURDEF>%s</CURDEF>
+<BANKACCTFROM><BANKID>USERAPI</BANKID><ACCTID>%s</ACCTID><ACCTTYPE>CHECKING</ACCTTYPE></BANKACCTFROM>
+<BANKTRANLIST><DTSTART>%s</DTSTART><DTEND>%s</DTEND>
+`, now, ofxText(st.UserID.Hex()+"-"+st.From.Format("20060102")), st.Currency, st.UserID.Hex(), ofxTime(st.From), ofxTime(st.To))
+	return err
+}
+
+func (o *ofxStatement) Entry(e domains.StatementEntry) error {
+	typ := "CREDIT"
+	switch e.Type {
+	case domains.EntryDebit:
+		typ = "DEBIT"
+	case domains.EntryFee:
+		typ = "FEE"
+	}
+	_, err := fmt.Fprintf(o.w, "<STMTTRN><TRNTYPE>%s</TRNTYPE><DTPOSTED>%s</DTPOSTED><TRNAMT>%s</TRNAMT><FITID>%s</FITID><MEMO>%s</MEMO></STMTTRN>\n",
+		typ, ofxTime(e.Date), money(e.Amount), ofxText(e.ID), ofxText(e.Description))
+	return err
+}
+
+func (o *ofxStatement) End(st *domains.Statement) error {
+	_, err := fmt.Fprintf(o.w, `</BANKTRANLIST>
+<LEDGERBAL><BALAMT>%s</BALAMT><DTASOF>%s</DTASOF></LEDGERBAL>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`, money(st.ClosingBalance), ofxTime(st.To))
+	return err
+}
@@ -0,0 +1,105 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports/mocks"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestStatementService_WriteStatement_CSV(t *testing.T) {
+	mockStmtRepo := mocks.NewStatementRepository(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	stmtService := services.NewStatementService(mockStmtRepo, mockUserRepo)
+
+	ctx := context.Background()
+	userID := primitive.NewObjectID()
+	otherID := primitive.NewObjectID()
+	from := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	transfers := []domains.Transfer{
+		{ID: primitive.NewObjectID(), FromUserID: otherID, ToUserID: userID, Amount: 50, CreatedAt: from.Add(time.Hour)},
+		{ID: primitive.NewObjectID(), FromUserID: userID, ToUserID: otherID, Amount: 20, Fee: 1, CreatedAt: from.Add(2 * time.Hour)},
+	}
+
+	mockUserRepo.On("GetByID", ctx, userID).Return(&domains.User{ID: userID}, nil)
+	mockStmtRepo.On("BalanceBefore", ctx, userID, from).Return(100.0, nil)
+	mockStmtRepo.On("StreamTransfers", ctx, userID, from, to, mock.Anything).
+		Run(func(args mock.Arguments) {
+			fn := args.Get(4).(func(domains.Transfer) error)
+			for _, tr := range transfers {
+				_ = fn(tr)
+			}
+		}).Return(nil)
+
+	st, err := stmtService.PrepareStatement(ctx, userID.Hex(), from, to)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = stmtService.WriteStatement(ctx, st, domains.StatementCSV, &buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, st.Entries)
+	assert.Equal(t, 129.0, st.ClosingBalance)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 6)
+	assert.Contains(t, lines[1], "opening_balance,,,100.00")
+	assert.Contains(t, lines[2], ",credit,")
+	assert.Contains(t, lines[4], ",fee,Transfer fee,-1.00,129.00")
+	assert.Contains(t, lines[5], "closing_balance,,,129.00")
+}
+
+func TestStatementService_PrepareStatement_InvalidPeriod(t *testing.T) {
+	mockStmtRepo := mocks.NewStatementRepository(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	stmtService := services.NewStatementService(mockStmtRepo, mockUserRepo)
+
+	now := time.Now()
+	st, err := stmtService.PrepareStatement(context.Background(), primitive.NewObjectID().Hex(), now, now.Add(-time.Hour))
+
+	assert.EqualError(t, err, "from must be before to")
+	assert.Nil(t, st)
+}
+
+func TestStatementService_GenerateMonthlyStatements(t *testing.T) {
+	mockStmtRepo := mocks.NewStatementRepository(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	stmtService := services.NewStatementService(mockStmtRepo, mockUserRepo)
+
+	ctx := context.Background()
+	doneID := primitive.NewObjectID()
+	newID := primitive.NewObjectID()
+	stored := &domains.StoredStatement{ID: primitive.NewObjectID(), UserID: newID, Period: "2026-09"}
+
+	mockStmtRepo.On("PeriodDone", ctx, "2026-09").Return(false, nil)
+	mockStmtRepo.On("UserIDsAfter", ctx, primitive.NilObjectID, 500).Return([]primitive.ObjectID{doneID, newID}, nil)
+	mockStmtRepo.On("CreateStored", ctx, mock.MatchedBy(func(s domains.StoredStatement) bool { return s.UserID == doneID })).
+		Return(&domains.StoredStatement{Status: domains.StoredReady}, false, nil)
+	mockStmtRepo.On("CreateStored", ctx, mock.MatchedBy(func(s domains.StoredStatement) bool {
+		return s.UserID == newID && s.Period == "2026-09" && s.Format == domains.StatementCSV && s.Status == domains.StoredPending
+	})).Return(stored, true, nil)
+	mockStmtRepo.On("BalanceBefore", ctx, newID, time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)).Return(100.0, nil)
+	mockStmtRepo.On("UploadStored", ctx, stored.ID, "statement-"+newID.Hex()+"-2026-09.csv", mock.Anything).
+		Run(func(args mock.Arguments) {
+			_ = args.Get(3).(func(io.Writer) error)(io.Discard)
+		}).Return(nil)
+	mockStmtRepo.On("StreamTransfers", ctx, newID, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockStmtRepo.On("CompleteStored", ctx, mock.MatchedBy(func(s domains.StoredStatement) bool {
+		return s.ID == stored.ID && s.OpeningBalance == 100 && s.ClosingBalance == 100
+	})).Return(nil)
+	mockStmtRepo.On("MarkPeriodDone", ctx, "2026-09").Return(nil)
+
+	generated, err := stmtService.GenerateMonthlyStatements(ctx, time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, generated)
+}
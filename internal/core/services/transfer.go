@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wansanjou/backend-exercise-user-api/config"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type transferService struct {
+	transferrepo ports.TransferRepository
+	userrepo     ports.UserRepository
+}
+
+func NewTransferService(transferrepo ports.TransferRepository, userrepo ports.UserRepository) ports.TransferService {
+	return &transferService{
+		transferrepo: transferrepo,
+		userrepo:     userrepo,
+	}
+}
+
+func (s *transferService) GetTransfer(ctx context.Context, id string) (*domains.Transfer, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domains.Invalid("id", "invalid id")
+	}
+	transfer, err := s.transferrepo.GetByID(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+	if transfer == nil {
+		return nil, domains.NotFound("transfer")
+	}
+	return transfer, nil
+}
+
+// QuoteTransfer prices a transfer without moving any money so the sender can confirm the fee first.
+func (s *transferService) QuoteTransfer(ctx context.Context, in domains.QuoteTransferRequest) (*domains.FeeQuote, error) {
+	if in.FromUserID == in.ToUserID {
+		return nil, domains.Invalid("toUserId", "cannot transfer to the same user")
+	}
+	if in.Amount <= 0 {
+		return nil, domains.Invalid("amount", "amount must be greater than zero")
+	}
+	foid, err := primitive.ObjectIDFromHex(in.FromUserID)
+	if err != nil {
+		return nil, domains.Invalid("fromUserId", "invalid from user ID")
+	}
+	if _, err := primitive.ObjectIDFromHex(in.ToUserID); err != nil {
+		return nil, domains.Invalid("toUserId", "invalid to user ID")
+	}
+
+	from, err := s.userrepo.GetByID(ctx, foid)
+	if err != nil {
+		return nil, err
+	}
+	if from == nil {
+		return nil, domains.NotFound("sender")
+	}
+	limits, err := transferLimits(from)
+	if err != nil {
+		return nil, err
+	}
+	if err := limits.Check(domains.TransferUsage{}, in.Amount, time.Now()); err != nil {
+		return nil, err
+	}
+
+	priced := domains.Transfer{Amount: in.Amount}
+	if err := applyFee(from, &priced); err != nil {
+		return nil, err
+	}
+	total := priced.Amount + priced.Fee
+	if from.Balance < total {
+		return nil, domains.InsufficientFunds("insufficient balance")
+	}
+
+	return &domains.FeeQuote{
+		Currency:          domains.DefaultCurrency,
+		Amount:            priced.Amount,
+		Fee:               priced.Fee,
+		Total:             total,
+		BalanceBefore:     from.Balance,
+		BalanceAfter:      from.Balance - total,
+		RecipientReceives: priced.Amount,
+	}, nil
+}
+
+func (s *transferService) ReverseTransfer(ctx context.Context, id, actorID string, in domains.ReverseTransferRequest) (*domains.Transfer, error) {
+	if in.Amount < 0 {
+		return nil, domains.Invalid("amount", "amount must not be negative")
+	}
+	in.Reason = strings.TrimSpace(in.Reason)
+	if in.Reason == "" {
+		return nil, domains.Invalid("reason", "reason is required")
+	}
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domains.Invalid("id", "invalid id")
+	}
+	actor, err := primitive.ObjectIDFromHex(actorID)
+	if err != nil {
+		return nil, domains.Invalid("actorId", "invalid actor id")
+	}
+
+	allowNegative := config.Get().Transfer.ReversalPolicy == domains.ReversalPolicyNegativeBalance
+
+	return s.transferrepo.Reverse(ctx, domains.Transfer{
+		Amount:     in.Amount,
+		ReversalOf: &oid,
+		Reason:     in.Reason,
+		ActorID:    &actor,
+	}, allowNegative)
+}
+
+func (s *transferService) CreateBatch(ctx context.Context, in domains.BatchTransferRequest) (*domains.TransferBatch, error) {
+	if in.Mode == "" {
+		in.Mode = domains.BatchModeAtomic
+	}
+	if in.Mode != domains.BatchModeAtomic && in.Mode != domains.BatchModePartial {
+		return nil, domains.Invalid("mode", "mode must be atomic or partial")
+	}
+	if len(in.Lines) == 0 {
+		return nil, domains.Invalid("lines", "batch has no lines")
+	}
+	if len(in.Lines) > domains.MaxBatchLines {
+		return nil, domains.Invalid("lines", fmt.Sprintf("batch has more than %d lines", domains.MaxBatchLines))
+	}
+
+	foid, err := primitive.ObjectIDFromHex(in.FromUserID)
+	if err != nil {
+		return nil, domains.Invalid("fromUserId", "invalid from user ID")
+	}
+
+	batch := domains.TransferBatch{FromUserID: foid, Mode: in.Mode}
+	verr := &domains.BatchValidationError{}
+	var recipients []primitive.ObjectID
+	for i, l := range in.Lines {
+		n := i + 1
+		toid, err := primitive.ObjectIDFromHex(l.ToUserID)
+		switch {
+		case err != nil:
+			verr.Lines = append(verr.Lines, domains.BatchLineError{Line: n, Error: "invalid to user ID"})
+			continue
+		case toid == foid:
+			verr.Lines = append(verr.Lines, domains.BatchLineError{Line: n, Error: "cannot transfer to the same user"})
+		case l.Amount <= 0:
+			verr.Lines = append(verr.Lines, domains.BatchLineError{Line: n, Error: "amount must be greater than zero"})
+		}
+		recipients = append(recipients, toid)
+		batch.Lines = append(batch.Lines, domains.BatchLineResult{Line: n, ToUserID: toid, Amount: l.Amount})
+		batch.Total += l.Amount
+	}
+
+	existing, err := s.userrepo.ExistingIDs(ctx, recipients)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[primitive.ObjectID]bool, len(existing))
+	for _, id := range existing {
+		found[id] = true
+	}
+	for _, l := range batch.Lines {
+		if !found[l.ToUserID] {
+			verr.Lines = append(verr.Lines, domains.BatchLineError{Line: l.Line, Error: "recipient not found"})
+		}
+	}
+	if len(verr.Lines) > 0 {
+		sort.Slice(verr.Lines, func(i, j int) bool { return verr.Lines[i].Line < verr.Lines[j].Line })
+		return nil, verr
+	}
+
+	from, err := s.userrepo.GetByID(ctx, foid)
+	if err != nil {
+		return nil, err
+	}
+	if from == nil {
+		return nil, domains.NotFound("sender")
+	}
+	for i := range batch.Lines {
+		priced := domains.Transfer{Amount: batch.Lines[i].Amount}
+		if err := applyFee(from, &priced); err != nil {
+			return nil, err
+		}
+		batch.Lines[i].Fee = priced.Fee
+		batch.TotalFees += priced.Fee
+		if priced.FeeAccountID != nil {
+			batch.FeeAccountID = priced.FeeAccountID
+		}
+	}
+	if in.Mode == domains.BatchModeAtomic && from.Balance < batch.Total+batch.TotalFees {
+		return nil, domains.InsufficientFunds("insufficient balance")
+	}
+
+	limits, err := transferLimits(from)
+	if err != nil {
+		return nil, err
+	}
+	return s.transferrepo.ExecuteBatch(ctx, batch, limits)
+}
+
+func (s *transferService) GetBatch(ctx context.Context, id string) (*domains.TransferBatch, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domains.Invalid("id", "invalid id")
+	}
+	batch, err := s.transferrepo.GetBatchByID(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+	if batch == nil {
+		return nil, domains.NotFound("batch")
+	}
+	return batch, nil
+}
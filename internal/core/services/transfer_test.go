@@ -0,0 +1,168 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports/mocks"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTransferService_ReverseTransfer(t *testing.T) {
+	mockRepo := mocks.NewTransferRepository(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	transferService := services.NewTransferService(mockRepo, mockUserRepo)
+
+	ctx := context.Background()
+	transferID := primitive.NewObjectID()
+	actorID := primitive.NewObjectID()
+	expected := &domains.Transfer{ID: primitive.NewObjectID(), ReversalOf: &transferID, Amount: 20}
+
+	mockRepo.On("Reverse", ctx, domains.Transfer{
+		Amount:     20,
+		ReversalOf: &transferID,
+		Reason:     "duplicate payment",
+		ActorID:    &actorID,
+	}, false).Return(expected, nil)
+
+	result, err := transferService.ReverseTransfer(ctx, transferID.Hex(), actorID.Hex(), domains.ReverseTransferRequest{
+		Amount: 20,
+		Reason: "  duplicate payment ",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestTransferService_ReverseTransfer_RequiresReason(t *testing.T) {
+	mockRepo := mocks.NewTransferRepository(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	transferService := services.NewTransferService(mockRepo, mockUserRepo)
+
+	result, err := transferService.ReverseTransfer(context.Background(),
+		primitive.NewObjectID().Hex(), primitive.NewObjectID().Hex(), domains.ReverseTransferRequest{Amount: 5})
+
+	assert.EqualError(t, err, "reason is required")
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "Reverse", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTransfer_FeeRefund(t *testing.T) {
+	orig := domains.Transfer{Amount: 300, Fee: 2.5}
+	assert.Equal(t, 2.5, orig.FeeRefund(300), "a full reversal refunds the whole fee")
+	assert.Equal(t, 0.83, orig.FeeRefund(100), "a partial one refunds the same share")
+
+	// The last reversal takes whatever is left, so rounding never strands part of the fee.
+	orig.ReversedAmount, orig.RefundedFee = 200, 1.66
+	assert.Equal(t, 0.84, orig.FeeRefund(100))
+
+	assert.Zero(t, domains.Transfer{Amount: 300}.FeeRefund(300))
+}
+
+func TestTransferService_GetTransfer_NotFound(t *testing.T) {
+	mockRepo := mocks.NewTransferRepository(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	transferService := services.NewTransferService(mockRepo, mockUserRepo)
+
+	ctx := context.Background()
+	transferID := primitive.NewObjectID()
+	mockRepo.On("GetByID", ctx, transferID).Return(nil, nil)
+
+	result, err := transferService.GetTransfer(ctx, transferID.Hex())
+
+	assert.EqualError(t, err, "transfer not found")
+	assert.Nil(t, result)
+}
+
+func TestTransferService_CreateBatch_ValidatesAllLines(t *testing.T) {
+	mockRepo := mocks.NewTransferRepository(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	transferService := services.NewTransferService(mockRepo, mockUserRepo)
+
+	ctx := context.Background()
+	fromID := primitive.NewObjectID()
+	known := primitive.NewObjectID()
+	unknown := primitive.NewObjectID()
+
+	mockUserRepo.On("ExistingIDs", ctx, mock.Anything).Return([]primitive.ObjectID{known}, nil)
+
+	batch, err := transferService.CreateBatch(ctx, domains.BatchTransferRequest{
+		FromUserID: fromID.Hex(),
+		Lines: []domains.BatchTransferLine{
+			{ToUserID: known.Hex(), Amount: 10},
+			{ToUserID: "bogus", Amount: 10},
+			{ToUserID: unknown.Hex(), Amount: 10},
+			{ToUserID: known.Hex(), Amount: -1},
+		},
+	})
+
+	var verr *domains.BatchValidationError
+	assert.ErrorAs(t, err, &verr)
+	assert.Nil(t, batch)
+	assert.Equal(t, []domains.BatchLineError{
+		{Line: 2, Error: "invalid to user ID"},
+		{Line: 3, Error: "recipient not found"},
+		{Line: 4, Error: "amount must be greater than zero"},
+	}, verr.Lines)
+	mockRepo.AssertNotCalled(t, "ExecuteBatch", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTransferService_CreateBatch(t *testing.T) {
+	mockRepo := mocks.NewTransferRepository(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	transferService := services.NewTransferService(mockRepo, mockUserRepo)
+
+	ctx := context.Background()
+	fromID := primitive.NewObjectID()
+	to1 := primitive.NewObjectID()
+	to2 := primitive.NewObjectID()
+
+	mockUserRepo.On("ExistingIDs", ctx, []primitive.ObjectID{to1, to2}).Return([]primitive.ObjectID{to1, to2}, nil)
+	mockUserRepo.On("GetByID", ctx, fromID).Return(&domains.User{ID: fromID, Balance: 100}, nil)
+	mockRepo.On("ExecuteBatch", ctx, domains.TransferBatch{
+		FromUserID: fromID,
+		Mode:       domains.BatchModePartial,
+		Total:      60,
+		Lines: []domains.BatchLineResult{
+			{Line: 1, ToUserID: to1, Amount: 25},
+			{Line: 2, ToUserID: to2, Amount: 35},
+		},
+	}, domains.TransferLimits{}).Return(&domains.TransferBatch{Status: domains.BatchCompleted}, nil)
+
+	batch, err := transferService.CreateBatch(ctx, domains.BatchTransferRequest{
+		FromUserID: fromID.Hex(),
+		Mode:       domains.BatchModePartial,
+		Lines: []domains.BatchTransferLine{
+			{ToUserID: to1.Hex(), Amount: 25},
+			{ToUserID: to2.Hex(), Amount: 35},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domains.BatchCompleted, batch.Status)
+}
+
+func TestTransferService_CreateBatch_AtomicInsufficientBalance(t *testing.T) {
+	mockRepo := mocks.NewTransferRepository(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	transferService := services.NewTransferService(mockRepo, mockUserRepo)
+
+	ctx := context.Background()
+	fromID := primitive.NewObjectID()
+	to := primitive.NewObjectID()
+
+	mockUserRepo.On("ExistingIDs", ctx, mock.Anything).Return([]primitive.ObjectID{to}, nil)
+	mockUserRepo.On("GetByID", ctx, fromID).Return(&domains.User{ID: fromID, Balance: 10}, nil)
+
+	batch, err := transferService.CreateBatch(ctx, domains.BatchTransferRequest{
+		FromUserID: fromID.Hex(),
+		Lines:      []domains.BatchTransferLine{{ToUserID: to.Hex(), Amount: 25}},
+	})
+
+	assert.EqualError(t, err, "insufficient balance")
+	assert.Nil(t, batch)
+}
@@ -2,9 +2,12 @@ package services
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"maps"
+	"strings"
+	"time"
 
+	"github.com/wansanjou/backend-exercise-user-api/config"
 	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
 	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports"
 	"github.com/wansanjou/backend-exercise-user-api/utils"
@@ -13,28 +16,23 @@ import (
 
 type service struct {
 	userrepo ports.UserRepository
+	fxrepo   ports.FXRepository
 }
 
-func NewUserService(userrepo ports.UserRepository) ports.UserService {
+func NewUserService(userrepo ports.UserRepository, fxrepo ports.FXRepository) ports.UserService {
 	return &service{
 		userrepo: userrepo,
+		fxrepo:   fxrepo,
 	}
 }
 
-func (s *service) CreateUser(ctx context.Context, data domains.User) (*domains.User, error) {
-	if data.Name == "" {
-		return nil, errors.New("name is required")
-	}
-	if data.Email == "" {
-		return nil, errors.New("email is required")
-	}
-	if data.Password == "" {
-		return nil, errors.New("password is required")
+func (s *service) CreateUser(ctx context.Context, in domains.CreateUserRequest) (*domains.User, error) {
+	data, err := newUser(in)
+	if err != nil {
+		return nil, err
 	}
 
-	data.Balance = 100.0
-
-	hash, err := utils.HashPassword(data.Password)
+	hash, err := utils.HashPassword(in.Password)
 	if err != nil {
 		return nil, err
 	}
@@ -43,40 +41,572 @@ func (s *service) CreateUser(ctx context.Context, data domains.User) (*domains.U
 	return s.userrepo.Create(ctx, data)
 }
 
+// newUser checks a sign-up and builds the user it creates, without hashing the password.
+func newUser(in domains.CreateUserRequest) (domains.User, error) {
+	in.Profile.Normalize()
+	if err := domains.Validate(in); err != nil {
+		return domains.User{}, err
+	}
+	email, err := domains.NormalizeEmail(in.Email)
+	if err != nil {
+		return domains.User{}, err
+	}
+	if in.Profile != nil {
+		if err := domains.CheckDateOfBirth(in.Profile.DateOfBirth, time.Now().UTC()); err != nil {
+			return domains.User{}, err
+		}
+	}
+	maps.DeleteFunc(in.Attributes, func(_ string, v map[string]interface{}) bool { return v == nil })
+	if err := checkAttributes(in.Attributes); err != nil {
+		return domains.User{}, err
+	}
+
+	data := domains.User{
+		Name:    strings.TrimSpace(in.Name),
+		Email:   email,
+		Handle:  in.Handle,
+		Phone:   in.Phone,
+		Balance: domains.OpeningBalance,
+		Opening: map[string]float64{domains.DefaultCurrency: domains.OpeningBalance},
+		Role:    domains.RoleUser,
+		Tier:    domains.DefaultTier,
+		Status:  domains.StatusActive,
+
+		Profile:    in.Profile,
+		Attributes: in.Attributes,
+	}
+	if err := normalizeAliases(&data.Handle, &data.Phone); err != nil {
+		return domains.User{}, err
+	}
+	return data, nil
+}
+
 func (s *service) GetUserByID(ctx context.Context, id string) (*domains.User, error) {
 	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, errors.New("invalid id")
+		return nil, domains.Invalid("id", "invalid id")
+	}
+	u, err := s.userrepo.GetByID(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, domains.NotFound("user")
 	}
-	return s.userrepo.GetByID(ctx, oid)
+	return u, nil
 }
 
-func (s *service) GetUsers(ctx context.Context, data domains.FindAllUsers) ([]domains.User, error) {
-	return s.userrepo.GetUsers(ctx, data)
+// GetAccount returns the parts of a user's account that level may see, including their
+// effective transfer limits. Deleted users are only visible to staff.
+func (s *service) GetAccount(ctx context.Context, id string, level domains.Visibility) (*domains.UserView, error) {
+	u, err := s.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if u.Deleted() && level < domains.VisibilityStaff {
+		return nil, domains.NotFound("user")
+	}
+	view := u.View()
+	limits, err := transferLimits(u)
+	if err != nil {
+		return nil, err
+	}
+	view.Limits = &limits
+	view = view.Redact(level)
+	return &view, nil
+}
+
+// GetUsers returns one page of users. Limit is capped at the configured maximum page size.
+func (s *service) GetUsers(ctx context.Context, data domains.FindAllUsers) (*domains.UserPage, error) {
+	cfg := config.Get().Users
+	if err := checkUserFilters(data); err != nil {
+		return nil, err
+	}
+	if data.Sort == "" && data.Search != "" {
+		data.Sort = domains.SortRelevance
+	}
+	if data.Limit == 0 {
+		data.Limit = cfg.DefaultPageSize
+	}
+	if data.Limit < 1 {
+		data.Limit = 10
+	}
+	if cfg.MaxPageSize > 0 && data.Limit > cfg.MaxPageSize {
+		data.Limit = cfg.MaxPageSize
+	}
+
+	q := domains.UserQuery{FindAllUsers: data}
+	var err error
+	if q.Attributes, err = attributeFilters(data.Attr); err != nil {
+		return nil, err
+	}
+	if q.SortBy, err = domains.ParseUserSort(data.Sort); err != nil {
+		return nil, err
+	}
+	if q.SortBy.Field == domains.SortRelevance && data.Search == "" {
+		return nil, domains.Invalid("sort", "relevance order needs a search")
+	}
+	if data.Cursor != "" {
+		if q.After, err = domains.DecodeUserCursor(data.Cursor, q.SortBy); err != nil {
+			return nil, err
+		}
+	}
+	return s.userrepo.GetUsers(ctx, q)
+}
+
+// checkUserFilters checks the filters of a user listing or export.
+func checkUserFilters(data domains.FindAllUsers) error {
+	if err := domains.Validate(data); err != nil {
+		return err
+	}
+	if data.MinBalance != nil && data.MaxBalance != nil && *data.MaxBalance < *data.MinBalance {
+		return domains.Invalid("maxBalance", "maxBalance must not be less than minBalance")
+	}
+	if data.CreatedFrom != nil && data.CreatedTo != nil && data.CreatedTo.Before(*data.CreatedFrom) {
+		return domains.Invalid("createdTo", "createdTo must not be before createdFrom")
+	}
+	if data.Search != "" && !config.Get().Users.TextSearch {
+		return domains.Invalid("search", "full-text search is not enabled")
+	}
+	return nil
 }
 
 func (s *service) CountUsers(ctx context.Context) (int64, error) {
 	return s.userrepo.Count(ctx)
 }
 
-func (s *service) TransferBalance(ctx context.Context, fromID, toID string, amount float64) error {
-	if fromID == toID {
-		return fmt.Errorf("cannot transfer to the same user")
+// UpdateAliases changes a user's handle or phone number. Only the fields given are changed.
+func (s *service) UpdateAliases(ctx context.Context, id string, in domains.UpdateAliasesRequest) (*domains.User, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domains.Invalid("id", "invalid id")
+	}
+	if err := normalizeAliases(in.Handle, in.Phone); err != nil {
+		return nil, err
+	}
+	u, err := s.userrepo.UpdateAliases(ctx, oid, in)
+	if err == nil && u == nil {
+		return nil, domains.NotFound("user")
+	}
+	return u, err
+}
+
+// VerifyAliases records that staff have confirmed a user's email or phone number.
+func (s *service) VerifyAliases(ctx context.Context, id string, in domains.VerifyAliasesRequest) (*domains.User, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domains.Invalid("id", "invalid id")
+	}
+	u, err := s.userrepo.GetByID(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, domains.NotFound("user")
+	}
+	if in.Phone != nil && *in.Phone && u.Phone == "" {
+		return nil, domains.Invalid("phone", "user has no phone number to verify")
+	}
+	return s.userrepo.SetVerified(ctx, oid, in)
+}
+
+// UpdateUser changes the profile of a user who has not been deleted. With ifVersion set, the
+// change is only made if nobody else has written to the user since that version.
+func (s *service) UpdateUser(ctx context.Context, id string, in domains.UpdateUserRequest, ifVersion *int64) (*domains.User, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domains.Invalid("id", "invalid id")
+	}
+	in.Profile.Normalize()
+	if err := domains.Validate(in); err != nil {
+		return nil, err
+	}
+	if in.Name != nil {
+		name := strings.TrimSpace(*in.Name)
+		in.Name = &name
+	}
+	if err := normalizeAliases(in.Handle, in.Phone); err != nil {
+		return nil, err
+	}
+	if in.Profile != nil && in.Profile.DateOfBirth != nil {
+		if err := domains.CheckDateOfBirth(*in.Profile.DateOfBirth, time.Now().UTC()); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkAttributes(in.Attributes); err != nil {
+		return nil, err
+	}
+	u, err := s.userrepo.Update(ctx, oid, in, ifVersion)
+	if err != nil || u != nil {
+		return u, err
+	}
+	if ifVersion != nil {
+		if current, err := s.userrepo.GetByID(ctx, oid); err != nil {
+			return nil, err
+		} else if current != nil && !current.Deleted() {
+			return nil, domains.Stale("user has changed since it was read; fetch it again and retry")
+		}
+	}
+	return nil, domains.NotFound("user")
+}
+
+// DeleteUser soft-deletes a user. They can no longer log in or send or receive money, and
+// their email is released after the configured retention period.
+func (s *service) DeleteUser(ctx context.Context, id string) (*domains.User, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domains.Invalid("id", "invalid id")
+	}
+	u, err := s.userrepo.SoftDelete(ctx, oid, time.Now().UTC())
+	if err != nil || u != nil {
+		return u, err
+	}
+	if existing, err := s.userrepo.GetByID(ctx, oid); err != nil {
+		return nil, err
+	} else if existing != nil {
+		return nil, domains.Conflict("user_deleted", "user is already deleted")
+	}
+	return nil, domains.NotFound("user")
+}
+
+// RestoreUser undoes DeleteUser.
+func (s *service) RestoreUser(ctx context.Context, id string) (*domains.User, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domains.Invalid("id", "invalid id")
+	}
+	u, err := s.userrepo.Restore(ctx, oid)
+	if err != nil || u != nil {
+		return u, err
+	}
+	if existing, err := s.userrepo.GetByID(ctx, oid); err != nil {
+		return nil, err
+	} else if existing != nil && existing.Erased() {
+		return nil, domains.Conflict("already_erased", "an erased account cannot be restored")
+	} else if existing != nil {
+		return nil, domains.Conflict("user_not_deleted", "user is not deleted")
+	}
+	return nil, domains.NotFound("user")
+}
+
+// ChangeStatus moves a user to another status on behalf of actorID, who must give a reason.
+func (s *service) ChangeStatus(ctx context.Context, id, actorID string, in domains.ChangeStatusRequest) (*domains.User, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domains.Invalid("id", "invalid id")
+	}
+	if err := domains.Validate(in); err != nil {
+		return nil, err
+	}
+	u, err := s.userrepo.GetByID(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, domains.NotFound("user")
+	}
+	from := u.AccountStatus()
+	if !domains.CanTransition(from, in.Status) {
+		return nil, domains.Conflict("invalid_status_transition", fmt.Sprintf("account cannot move from %s to %s", from, in.Status))
+	}
+
+	out, err := s.userrepo.SetStatus(ctx, domains.StatusChange{
+		UserID:  oid,
+		From:    from,
+		To:      in.Status,
+		Reason:  strings.TrimSpace(in.Reason),
+		ActorID: actorID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if out == nil {
+		return nil, domains.Conflict("status_changed", "account status was changed by someone else; reload and try again")
+	}
+	return out, nil
+}
+
+func (s *service) StatusHistory(ctx context.Context, id string) ([]domains.StatusChange, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domains.Invalid("id", "invalid id")
+	}
+	if u, err := s.userrepo.GetByID(ctx, oid); err != nil {
+		return nil, err
+	} else if u == nil {
+		return nil, domains.NotFound("user")
+	}
+	return s.userrepo.ListStatusChanges(ctx, oid)
+}
+
+// ReleaseDeletedEmails frees the emails of users deleted longer ago than the retention period.
+func (s *service) ReleaseDeletedEmails(ctx context.Context) (int64, error) {
+	retention := time.Duration(config.Get().Users.DeletedEmailRetentionDays) * 24 * time.Hour
+	return s.userrepo.ReleaseDeletedEmails(ctx, time.Now().UTC().Add(-retention))
+}
+
+// ReencryptUsers moves every user's personal data onto the active encryption key, a batch
+// at a time, and returns how many users were updated.
+func (s *service) ReencryptUsers(ctx context.Context) (int, error) {
+	batch := config.Get().Encryption.ReencryptBatchSize
+	if batch <= 0 {
+		batch = 200
+	}
+	total := 0
+	for {
+		n, err := s.userrepo.ReencryptUsers(ctx, batch)
+		total += n
+		if err != nil || n < batch {
+			return total, err
+		}
+	}
+}
+
+// MigrateEmails normalizes every stored email and, when apply is set, saves the changes
+// and builds the case-insensitive unique index. Accounts whose emails collide once case is
+// ignored are reported and left untouched; the index waits until they are resolved.
+func (s *service) MigrateEmails(ctx context.Context, apply bool) (*domains.EmailMigrationReport, error) {
+	records, err := s.userrepo.ListEmails(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domains.EmailMigrationReport{Checked: len(records)}
+	groups := map[string][]domains.EmailRecord{}
+	var keys []string
+	for _, r := range records {
+		key := domains.EmailIdentity(r.Email)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], r)
+	}
+
+	for _, key := range keys {
+		group := groups[key]
+		if len(group) > 1 {
+			report.Collisions = append(report.Collisions, group)
+			continue
+		}
+		normalized, err := domains.NormalizeEmail(group[0].Email)
+		if err != nil {
+			report.Invalid = append(report.Invalid, group[0])
+			continue
+		}
+		if normalized == group[0].Email {
+			continue
+		}
+		if apply {
+			if err := s.userrepo.SetEmail(ctx, group[0].UserID, normalized); err != nil {
+				return report, err
+			}
+		}
+		report.Normalized++
+	}
+
+	if apply && len(report.Collisions) == 0 {
+		if err := s.userrepo.EnsureEmailIndex(ctx); err != nil {
+			return report, err
+		}
+		report.IndexCreated = true
+	}
+	return report, nil
+}
+
+// normalizeAliases rewrites a handle and phone number into their stored form. Nil or empty
+// values are left alone.
+func normalizeAliases(handle, phone *string) error {
+	if handle != nil && *handle != "" {
+		h, err := domains.NormalizeHandle(*handle)
+		if err != nil {
+			return err
+		}
+		*handle = h
+	}
+	if phone != nil && *phone != "" {
+		p, err := domains.NormalizePhone(*phone)
+		if err != nil {
+			return err
+		}
+		*phone = p
+	}
+	return nil
+}
+
+func (s *service) TransferBalance(ctx context.Context, req domains.TransferRequest) (*domains.Transfer, error) {
+	byAlias := req.ToUserID == "" && req.To != ""
+	fromID, toID, amount := req.FromUserID, req.ToUserID, req.Amount
+	if !byAlias && fromID == toID {
+		return nil, domains.Invalid("toUserId", "cannot transfer to the same user")
 	}
 
 	if amount <= 0.00 {
-		return fmt.Errorf("amount must be greater than zero")
+		return nil, domains.Invalid("amount", "amount must be greater than zero")
 	}
 
 	foid, err := primitive.ObjectIDFromHex(fromID)
 	if err != nil {
-		return fmt.Errorf("invalid from user ID: %v", err)
+		return nil, domains.Invalid("fromUserId", "invalid from user ID")
 	}
 
-	toid, err := primitive.ObjectIDFromHex(toID)
+	var toid primitive.ObjectID
+	if !byAlias {
+		toid, err = primitive.ObjectIDFromHex(toID)
+		if err != nil {
+			return nil, domains.Invalid("toUserId", "invalid to user ID")
+		}
+	}
+
+	from, err := s.userrepo.GetByID(ctx, foid)
 	if err != nil {
-		return fmt.Errorf("invalid to user ID: %v", err)
+		return nil, err
+	}
+	if from == nil {
+		return nil, domains.NotFound("sender")
+	}
+	if from.Deleted() {
+		return nil, domains.Conflict("account_deleted", "account has been deleted")
+	}
+
+	in := domains.Transfer{
+		FromUserID: foid,
+		ToUserID:   toid,
+		Amount:     amount,
+	}
+	if err := s.convert(ctx, req, &in); err != nil {
+		return nil, err
+	}
+
+	// Reject obvious violations early; windowed totals are checked again inside the transaction.
+	limits, err := transferLimits(from)
+	if err != nil {
+		return nil, err
+	}
+	if err := limits.Check(domains.TransferUsage{}, in.LimitAmount(), time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := applyFee(from, &in); err != nil {
+		return nil, err
+	}
+
+	if byAlias {
+		// The alias is resolved only once nothing on the sender's side stands in the way,
+		// so a transfer that fails fails the same whether or not the alias belongs to anyone.
+		if err := checkSpendable(from, in); err != nil {
+			return nil, err
+		}
+		_, to, err := resolveRecipient(ctx, s.userrepo, req.To)
+		if err != nil {
+			return nil, err
+		}
+		if to.ID == foid {
+			return nil, domains.Invalid("to", "cannot transfer to the same user")
+		}
+		in.ToUserID = to.ID
 	}
 
-	return s.userrepo.TransferWithTransaction(ctx, foid, toid, amount)
+	return s.userrepo.TransferWithTransaction(ctx, in, limits)
+}
+
+// checkSpendable rejects a transfer the sender could not make to anyone.
+func checkSpendable(from *domains.User, in domains.Transfer) error {
+	if status := from.AccountStatus(); status != domains.StatusActive {
+		return domains.Conflict("account_"+status, "account is "+status)
+	}
+	if from.BalanceIn(in.SourceCurrency()) < in.Amount+in.Fee {
+		return domains.InsufficientFunds("insufficient balance")
+	}
+	return nil
+}
+
+// convert sets the currencies of in. A cross-currency transfer takes its rate from the
+// sender's quote, and one outside the default currency is valued in it for limit checks.
+func (s *service) convert(ctx context.Context, req domains.TransferRequest, in *domains.Transfer) error {
+	from, to := domains.NormalizeCurrency(req.Currency), domains.NormalizeCurrency(req.Currency)
+	if req.ToCurrency != "" {
+		to = domains.NormalizeCurrency(req.ToCurrency)
+	}
+	if !supportedCurrency(from) || !supportedCurrency(to) {
+		return domains.Invalid("currency", "unsupported currency")
+	}
+	if from == domains.DefaultCurrency && to == domains.DefaultCurrency {
+		return nil
+	}
+	in.Currency, in.ToCurrency = from, to
+	now := time.Now().UTC()
+
+	if from != to {
+		qid, err := primitive.ObjectIDFromHex(req.QuoteID)
+		if err != nil {
+			return domains.Invalid("quoteId", "a quote is required to transfer between currencies")
+		}
+		quote, err := s.fxrepo.GetQuote(ctx, qid)
+		if err != nil {
+			return err
+		}
+		if quote == nil || quote.UserID != in.FromUserID {
+			return domains.NotFound("quote")
+		}
+		if quote.FromCurrency != from || quote.ToCurrency != to || quote.Amount != in.Amount {
+			return domains.Invalid("quoteId", "quote does not match the transfer")
+		}
+		if quote.UsedAt != nil || !now.Before(quote.ExpiresAt) {
+			return domains.Conflict("quote_unavailable", "quote has expired or was already used")
+		}
+		in.Rate, in.ToAmount, in.QuoteID = quote.Rate, quote.ToAmount, &quote.ID
+	}
+
+	if from != domains.DefaultCurrency {
+		rate, err := s.fxrepo.RateAt(ctx, from, domains.DefaultCurrency, now)
+		if err != nil {
+			return err
+		}
+		if rate == nil {
+			return domains.Conflict("rate_unavailable", "no rate available for "+from+"/"+domains.DefaultCurrency)
+		}
+		in.BaseAmount = rate.Convert(in.Amount)
+	}
+	return nil
+}
+
+// CheckTransferTiers reports tier limits that could not be applied: tiers are configured
+// but the default tier, which users of any unknown tier fall back to, is not among them.
+func CheckTransferTiers() error {
+	cfg := config.Get().Transfer
+	if len(cfg.Tiers) == 0 {
+		return nil
+	}
+	if _, err := limitTiers().For(cfg.DefaultTier, cfg.DefaultTier); err != nil {
+		return fmt.Errorf("defaultTier %q has no limits in tiers", cfg.DefaultTier)
+	}
+	return nil
+}
+
+// limitTiers are the configured tier limits.
+func limitTiers() domains.LimitTiers {
+	cfg := config.Get().Transfer
+	tiers := make(domains.LimitTiers, len(cfg.Tiers))
+	for name, t := range cfg.Tiers {
+		tiers[strings.ToLower(name)] = domains.TransferLimits{
+			MaxPerTransaction:   t.MaxPerTransaction,
+			DailyOutgoing:       t.DailyOutgoing,
+			MonthlyOutgoing:     t.MonthlyOutgoing,
+			MaxTransfersPerHour: t.MaxTransfersPerHour,
+		}
+	}
+	return tiers
+}
+
+// transferLimits resolves the limits of the user's tier with any per-user overrides
+// applied. With no tiers configured at all, only the overrides apply.
+func transferLimits(u *domains.User) (domains.TransferLimits, error) {
+	var limits domains.TransferLimits
+	if tiers := limitTiers(); len(tiers) > 0 {
+		var err error
+		if limits, err = tiers.For(u.Tier, config.Get().Transfer.DefaultTier); err != nil {
+			return limits, err
+		}
+	}
+	return limits.Apply(u.LimitOverrides), nil
 }
@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/wansanjou/backend-exercise-user-api/config"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/utils"
+)
+
+const defaultImportBatchSize = 500
+
+// conflictFields names the field a conflict from creating a user is about.
+var conflictFields = map[string]string{
+	"email_taken":  "email",
+	"handle_taken": "handle",
+}
+
+// ImportUsers creates a user for every valid row of r: CSV with a header row naming the
+// columns, or NDJSON with one sign-up object per line. Each row is checked on its own and a
+// bad row never stops the rest. Rows are inserted in batches whose passwords are hashed in
+// parallel. A dry run checks every row, including against existing accounts, but writes
+// nothing. The report is returned with any error, covering the rows read before it.
+func (s *service) ImportUsers(ctx context.Context, format string, r io.Reader, dryRun bool) (*domains.UserImportReport, error) {
+	if !domains.ValidBulkFormat(format) {
+		return nil, domains.Invalid("format", "format must be csv or ndjson")
+	}
+	rows, err := newUserRowReader(format, r)
+	if err != nil {
+		return nil, err
+	}
+	cfg := config.Get().Users
+	batchSize := cfg.ImportBatchSize
+	if batchSize < 1 {
+		batchSize = defaultImportBatchSize
+	}
+	workers := cfg.ImportWorkers
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	report := &domains.UserImportReport{DryRun: dryRun, Errors: []domains.ImportRowError{}}
+	seen := map[string]int{} // import keys of earlier rows, by line
+	batch := make([]importRow, 0, batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		var err error
+		if dryRun {
+			err = s.checkExisting(ctx, batch, report)
+		} else {
+			err = s.insertBatch(ctx, batch, workers, report)
+		}
+		batch = batch[:0]
+		return err
+	}
+
+	for {
+		row, err := rows.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return report, err
+		}
+		report.Rows++
+		if row.err == nil {
+			row.user, row.err = newUser(row.req)
+		}
+		if row.err == nil {
+			row.err = claimInFile(seen, row)
+		}
+		if row.err != nil {
+			failRow(report, row.line, row.err)
+			continue
+		}
+		batch = append(batch, row)
+		if len(batch) == batchSize {
+			if err := flush(); err != nil {
+				return report, err
+			}
+		}
+	}
+	return report, flush()
+}
+
+// importKeys identify the account a row would create: its email, ignoring case, and its
+// handle. No two accounts may share one.
+func importKeys(u domains.User) map[string]string {
+	keys := map[string]string{"email": "email:" + domains.EmailIdentity(u.Email)}
+	if u.Handle != "" {
+		keys["handle"] = "handle:" + u.Handle
+	}
+	return keys
+}
+
+// claimInFile fails a row whose email or handle an earlier row of the file already has.
+func claimInFile(seen map[string]int, row importRow) error {
+	keys := importKeys(row.user)
+	var fields []domains.FieldError
+	for _, field := range []string{"email", "handle"} {
+		if first, ok := seen[keys[field]]; ok && keys[field] != "" {
+			fields = append(fields, domains.FieldError{
+				Field:   field,
+				Code:    "duplicate_in_file",
+				Message: fmt.Sprintf("%s is already used on line %d", field, first),
+			})
+		}
+	}
+	if len(fields) > 0 {
+		return domains.InvalidFields(fields)
+	}
+	for _, key := range keys {
+		seen[key] = row.line
+	}
+	return nil
+}
+
+// checkExisting fails the rows of a dry run whose email or handle already belongs to an
+// account, and counts the rest as created.
+func (s *service) checkExisting(ctx context.Context, batch []importRow, report *domains.UserImportReport) error {
+	var emails, handles []string
+	for _, row := range batch {
+		emails = append(emails, row.user.Email)
+		if row.user.Handle != "" {
+			handles = append(handles, row.user.Handle)
+		}
+	}
+	existing, err := s.userrepo.FindExisting(ctx, emails, handles)
+	if err != nil {
+		return err
+	}
+	taken := map[string]bool{}
+	for _, u := range existing {
+		for _, key := range importKeys(u) {
+			taken[key] = true
+		}
+	}
+
+	for _, row := range batch {
+		keys := importKeys(row.user)
+		switch {
+		case taken[keys["email"]]:
+			failRow(report, row.line, domains.Conflict("email_taken", "email is already registered"))
+		case keys["handle"] != "" && taken[keys["handle"]]:
+			failRow(report, row.line, domains.Conflict("handle_taken", "handle is already taken"))
+		default:
+			report.Created++
+		}
+	}
+	return nil
+}
+
+func (s *service) insertBatch(ctx context.Context, batch []importRow, workers int, report *domains.UserImportReport) error {
+	if err := hashPasswords(ctx, batch, workers); err != nil {
+		return err
+	}
+	rows := make([]importRow, 0, len(batch))
+	users := make([]domains.User, 0, len(batch))
+	for _, row := range batch {
+		if row.err != nil {
+			failRow(report, row.line, row.err)
+			continue
+		}
+		rows = append(rows, row)
+		users = append(users, row.user)
+	}
+	if len(users) == 0 {
+		return nil
+	}
+
+	failed, err := s.userrepo.InsertMany(ctx, users)
+	if err != nil {
+		return err
+	}
+	for i, row := range rows {
+		if i < len(failed) && failed[i] != nil {
+			failRow(report, row.line, failed[i])
+			continue
+		}
+		report.Created++
+	}
+	return nil
+}
+
+// hashPasswords hashes the password of every row on up to workers goroutines. A row whose
+// password cannot be hashed gets the error.
+func hashPasswords(ctx context.Context, rows []importRow, workers int) error {
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				hash, err := utils.HashPassword(rows[i].req.Password)
+				if err != nil {
+					rows[i].err = domains.Invalid("password", "password cannot be used")
+					continue
+				}
+				rows[i].user.Password = hash
+			}
+		}()
+	}
+
+	var err error
+feed:
+	for i := range rows {
+		select {
+		case next <- i:
+		case <-ctx.Done():
+			err = ctx.Err()
+			break feed
+		}
+	}
+	close(next)
+	wg.Wait()
+	return err
+}
+
+// failRow records why the row on line was not created.
+func failRow(report *domains.UserImportReport, line int, err error) {
+	report.Failed++
+	report.Errors = append(report.Errors, domains.ImportRowError{Line: line, Errors: rowFields(err)})
+}
+
+func rowFields(err error) []domains.FieldError {
+	var e *domains.Error
+	switch {
+	case errors.As(err, &e) && len(e.Fields) > 0:
+		return e.Fields
+	case errors.As(err, &e):
+		return []domains.FieldError{{Field: conflictFields[e.Code], Code: e.Code, Message: e.Message}}
+	default:
+		return []domains.FieldError{{Code: "insert_failed", Message: "user could not be created"}}
+	}
+}
+
+// ExportUsers writes every user matching q to w as CSV or NDJSON, one user at a time, with
+// only the given fields. It returns how many users were written.
+func (s *service) ExportUsers(ctx context.Context, q domains.FindAllUsers, format string, fields []string, w io.Writer) (int, error) {
+	if !domains.ValidBulkFormat(format) {
+		return 0, domains.Invalid("format", "format must be csv or ndjson")
+	}
+	fields, err := domains.ParseUserExportFields(strings.Join(fields, ","))
+	if err != nil {
+		return 0, err
+	}
+	if err := checkUserFilters(q); err != nil {
+		return 0, err
+	}
+	attrs, err := attributeFilters(q.Attr)
+	if err != nil {
+		return 0, err
+	}
+
+	out := newUserRowWriter(format, fields, w)
+	if err := out.Begin(); err != nil {
+		return 0, err
+	}
+	n := 0
+	err = s.userrepo.StreamUsers(ctx, domains.UserQuery{FindAllUsers: q, Attributes: attrs}, func(u domains.User) error {
+		n++
+		return out.User(u)
+	})
+	if err != nil {
+		return n, err
+	}
+	return n, out.End()
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/utils"
+)
+
+// importRow is one row of a bulk import. err is set once the row has failed.
+type importRow struct {
+	line int
+	req  domains.CreateUserRequest
+	user domains.User
+	err  error
+}
+
+// userRowReader reads an import one row at a time. Next returns io.EOF after the last row;
+// any other error means the file itself could not be read.
+type userRowReader interface {
+	Next() (importRow, error)
+}
+
+func newUserRowReader(format string, r io.Reader) (userRowReader, error) {
+	if format == domains.BulkNDJSON {
+		return &ndjsonUserRows{r: bufio.NewReader(r)}, nil
+	}
+	return newCSVUserRows(r)
+}
+
+// importColumns are the CSV columns an import may have, required ones first.
+var importColumns = []string{"name", "email", "password", "handle", "phone"}
+
+const requiredImportColumns = 3
+
+type csvUserRows struct {
+	r    *csv.Reader
+	cols map[string]int
+}
+
+func newCSVUserRows(r io.Reader) (*csvUserRows, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, domains.Invalid("file", "file is empty")
+	}
+	if err != nil {
+		return nil, domains.Invalid("file", "header row is not valid CSV")
+	}
+
+	cols := map[string]int{}
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		if !slices.Contains(importColumns, h) {
+			return nil, domains.Invalid("file", "unknown column "+h)
+		}
+		if _, dup := cols[h]; dup {
+			return nil, domains.Invalid("file", "column "+h+" appears twice")
+		}
+		cols[h] = i
+	}
+	for _, col := range importColumns[:requiredImportColumns] {
+		if _, ok := cols[col]; !ok {
+			return nil, domains.Invalid("file", "missing column "+col)
+		}
+	}
+	cr.FieldsPerRecord = len(header)
+	return &csvUserRows{r: cr, cols: cols}, nil
+}
+
+func (c *csvUserRows) Next() (importRow, error) {
+	rec, err := c.r.Read()
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		msg := "row is not valid CSV"
+		if errors.Is(err, csv.ErrFieldCount) {
+			msg = "row has the wrong number of columns"
+		}
+		return importRow{line: parseErr.StartLine, err: domains.Invalid("row", msg)}, nil
+	}
+	if err != nil {
+		return importRow{}, err
+	}
+
+	line, _ := c.r.FieldPos(0)
+	get := func(col string) string {
+		if i, ok := c.cols[col]; ok {
+			return rec[i]
+		}
+		return ""
+	}
+	return importRow{line: line, req: domains.CreateUserRequest{
+		Name:     get("name"),
+		Email:    get("email"),
+		Password: get("password"),
+		Handle:   get("handle"),
+		Phone:    get("phone"),
+	}}, nil
+}
+
+// ndjsonUserRows reads one sign-up object per line. Blank lines are skipped but counted.
+type ndjsonUserRows struct {
+	r    *bufio.Reader
+	line int
+}
+
+func (n *ndjsonUserRows) Next() (importRow, error) {
+	for {
+		b, err := n.r.ReadBytes('\n')
+		if err != nil && (err != io.EOF || len(b) == 0) {
+			return importRow{}, err
+		}
+		n.line++
+		if b = bytes.TrimSpace(b); len(b) == 0 {
+			continue
+		}
+
+		row := importRow{line: n.line}
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&row.req); err != nil {
+			row.err = rowDecodeError(err)
+		} else if dec.More() {
+			row.err = domains.Invalid("row", "row must be a single JSON object")
+		}
+		return row, nil
+	}
+}
+
+func rowDecodeError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	field, unknown := utils.UnknownJSONField(err)
+	switch {
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return domains.InvalidFields([]domains.FieldError{{
+			Field:   typeErr.Field,
+			Code:    "invalid_type",
+			Message: typeErr.Field + " has the wrong type",
+		}})
+	case unknown:
+		return domains.InvalidFields([]domains.FieldError{{
+			Field:   field,
+			Code:    "unknown_field",
+			Message: field + " is not an accepted field",
+		}})
+	default:
+		return domains.Invalid("row", "row is not a valid JSON object")
+	}
+}
+
+// userRowWriter renders an export as it is read, one user at a time.
+type userRowWriter interface {
+	Begin() error
+	User(u domains.User) error
+	End() error
+}
+
+func newUserRowWriter(format string, fields []string, w io.Writer) userRowWriter {
+	if format == domains.BulkNDJSON {
+		return &ndjsonUsers{enc: json.NewEncoder(w), fields: fields}
+	}
+	return &csvUsers{w: csv.NewWriter(w), fields: fields}
+}
+
+type csvUsers struct {
+	w      *csv.Writer
+	fields []string
+	rec    []string
+}
+
+func (c *csvUsers) Begin() error {
+	c.rec = make([]string, len(c.fields))
+	return c.w.Write(c.fields)
+}
+
+func (c *csvUsers) User(u domains.User) error {
+	for i, f := range c.fields {
+		c.rec[i] = csvValue(u.ExportValue(f))
+	}
+	return c.w.Write(c.rec)
+}
+
+func (c *csvUsers) End() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func csvValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return money(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.UTC().Format(time.RFC3339)
+	default:
+		return ""
+	}
+}
+
+// ndjsonUsers writes one JSON object per user, keyed by field name.
+type ndjsonUsers struct {
+	enc    *json.Encoder
+	fields []string
+}
+
+func (n *ndjsonUsers) Begin() error {
+	return nil
+}
+
+func (n *ndjsonUsers) User(u domains.User) error {
+	obj := make(map[string]interface{}, len(n.fields))
+	for _, f := range n.fields {
+		obj[f] = u.ExportValue(f)
+	}
+	return n.enc.Encode(obj)
+}
+
+func (n *ndjsonUsers) End() error {
+	return nil
+}
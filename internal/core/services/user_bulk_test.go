@@ -0,0 +1,115 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports/mocks"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestUserService_ImportUsers_CSV(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+	file := "name,email,password,handle\n" +
+		"Somchai Jaidee,somchai@Example.com,password123,somchai\n" +
+		"No Password,nopass@example.com,,\n" +
+		"Somchai Again,SOMCHAI@example.com,password123,\n" +
+		"Malee Suk,malee@example.com,password123,malee\n" +
+		"Taken Handle,taken@example.com,password123,taken\n"
+
+	mockRepo.On("InsertMany", mock.Anything, mock.MatchedBy(func(users []domains.User) bool {
+		return len(users) == 3 && users[0].Email == "somchai@example.com" && users[0].Password != "password123" &&
+			users[0].Status == domains.StatusActive && users[1].Handle == "malee"
+	})).Return([]error{nil, nil, domains.Conflict("handle_taken", "handle is already taken")}, nil)
+
+	report, err := userService.ImportUsers(context.Background(), domains.BulkCSV, strings.NewReader(file), false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, report.Rows)
+	assert.Equal(t, 2, report.Created)
+	assert.Equal(t, 3, report.Failed)
+	if assert.Len(t, report.Errors, 3) {
+		assert.Equal(t, 3, report.Errors[0].Line)
+		assert.Equal(t, "password", report.Errors[0].Errors[0].Field)
+		assert.Equal(t, 4, report.Errors[1].Line)
+		assert.Equal(t, "duplicate_in_file", report.Errors[1].Errors[0].Code)
+		assert.Equal(t, 6, report.Errors[2].Line)
+		assert.Equal(t, domains.FieldError{Field: "handle", Code: "handle_taken", Message: "handle is already taken"}, report.Errors[2].Errors[0])
+	}
+}
+
+func TestUserService_ImportUsers_DryRunNDJSON(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+	file := `{"name":"Somchai Jaidee","email":"somchai@example.com","password":"password123"}` + "\n" +
+		"\n" +
+		`{"name":"Malee Suk","email":"malee@example.com","password":"password123","role":"admin"}` + "\n" +
+		`{"name":"Existing User","email":"Existing@example.com","password":"password123"}`
+
+	mockRepo.On("FindExisting", mock.Anything, []string{"somchai@example.com", "Existing@example.com"}, []string(nil)).
+		Return([]domains.User{{Email: "existing@example.com"}}, nil)
+
+	report, err := userService.ImportUsers(context.Background(), domains.BulkNDJSON, strings.NewReader(file), true)
+
+	assert.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 3, report.Rows)
+	assert.Equal(t, 1, report.Created)
+	if assert.Len(t, report.Errors, 2) {
+		assert.Equal(t, domains.ImportRowError{Line: 3, Errors: []domains.FieldError{{
+			Field: "role", Code: "unknown_field", Message: "role is not an accepted field",
+		}}}, report.Errors[0])
+		assert.Equal(t, 4, report.Errors[1].Line)
+		assert.Equal(t, "email_taken", report.Errors[1].Errors[0].Code)
+	}
+	mockRepo.AssertNotCalled(t, "InsertMany", mock.Anything, mock.Anything)
+}
+
+func TestUserService_ImportUsers_BadHeader(t *testing.T) {
+	userService := services.NewUserService(mocks.NewUserRepository(t), mocks.NewFXRepository(t))
+	ctx := context.Background()
+
+	_, err := userService.ImportUsers(ctx, domains.BulkCSV, strings.NewReader("name,email,role\n"), false)
+	assert.ErrorIs(t, err, domains.ErrValidation)
+
+	_, err = userService.ImportUsers(ctx, domains.BulkCSV, strings.NewReader("name,email\n"), false)
+	assert.ErrorIs(t, err, domains.ErrValidation, "password column is required")
+
+	_, err = userService.ImportUsers(ctx, "xlsx", strings.NewReader(""), false)
+	assert.ErrorIs(t, err, domains.ErrValidation)
+}
+
+func TestUserService_ExportUsers_CSV(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+	id := primitive.NewObjectID()
+	created := time.Date(2026, 9, 1, 8, 0, 0, 0, time.UTC)
+
+	mockRepo.On("StreamUsers", mock.Anything, domains.UserQuery{FindAllUsers: domains.FindAllUsers{Status: domains.StatusFrozen}}, mock.Anything).
+		Run(func(args mock.Arguments) {
+			fn := args.Get(2).(func(domains.User) error)
+			_ = fn(domains.User{ID: id, Name: "Somchai, Jr.", Email: "somchai@example.com", Password: "hash",
+				Balance: 12.5, Status: domains.StatusFrozen, CreatedAt: created})
+		}).Return(nil)
+
+	fields, err := domains.ParseUserExportFields("id, name,balance,status,created_at,name")
+	assert.NoError(t, err)
+	var buf bytes.Buffer
+	n, err := userService.ExportUsers(context.Background(), domains.FindAllUsers{Status: domains.StatusFrozen}, domains.BulkCSV, fields, &buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, "id,name,balance,status,created_at\n"+
+		id.Hex()+`,"Somchai, Jr.",12.50,frozen,2026-09-01T08:00:00Z`+"\n", buf.String())
+
+	_, err = domains.ParseUserExportFields("id,password")
+	assert.ErrorIs(t, err, domains.ErrValidation)
+}
@@ -17,11 +17,11 @@ import (
 
 func TestUserService_CreateUser(t *testing.T) {
 	mockRepo := mocks.NewUserRepository(t)
-	userService := services.NewUserService(mockRepo)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
 
 	ctx := context.Background()
 
-	inputUser := domains.User{
+	inputUser := domains.CreateUserRequest{
 		Name:     "John Doe",
 		Email:    "john@example.com",
 		Password: "hashedpassword123",
@@ -50,11 +50,11 @@ func TestUserService_CreateUser(t *testing.T) {
 
 func TestUserService_CreateUser_Error(t *testing.T) {
 	mockRepo := mocks.NewUserRepository(t)
-	userService := services.NewUserService(mockRepo)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
 
 	ctx := context.Background()
 
-	inputUser := domains.User{
+	inputUser := domains.CreateUserRequest{
 		Name:     "John Doe",
 		Email:    "john@example.com",
 		Password: "hashedpassword123",
@@ -71,9 +71,61 @@ func TestUserService_CreateUser_Error(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestUserService_CreateUser_InvalidFields(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+
+	result, err := userService.CreateUser(context.Background(), domains.CreateUserRequest{
+		Name:     "   ",
+		Email:    "john@example",
+		Password: "short",
+	})
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, domains.ErrValidation)
+	var derr *domains.Error
+	if assert.ErrorAs(t, err, &derr) {
+		codes := map[string]string{}
+		for _, f := range derr.Fields {
+			codes[f.Field] = f.Code
+		}
+		assert.Equal(t, map[string]string{
+			"name":     "invalid_name",
+			"email":    "invalid_email",
+			"password": "too_short",
+		}, codes)
+	}
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestValidate_TransferRequest(t *testing.T) {
+	err := domains.Validate(domains.TransferRequest{
+		FromUserID: primitive.NewObjectID().Hex(),
+		ToUserID:   "not-an-id",
+		Amount:     10.005,
+		Currency:   "US",
+	})
+
+	var derr *domains.Error
+	if assert.ErrorAs(t, err, &derr) {
+		assert.Equal(t, []domains.FieldError{
+			{Field: "toUserId", Code: "invalid_id", Message: "toUserId must be a 24-character hex id"},
+			{Field: "amount", Code: "invalid_amount", Message: "amount must be greater than zero with at most two decimal places"},
+			{Field: "currency", Code: "invalid_currency", Message: "currency must be a three-letter currency code"},
+		}, derr.Fields)
+	}
+
+	err = domains.Validate(domains.TransferRequest{
+		FromUserID: primitive.NewObjectID().Hex(),
+		To:         "@somchai",
+		Amount:     10.5,
+	})
+	assert.NoError(t, err)
+}
+
 func TestUserService_GetUserByID(t *testing.T) {
 	mockRepo := mocks.NewUserRepository(t)
-	userService := services.NewUserService(mockRepo)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
 
 	ctx := context.Background()
 
@@ -101,7 +153,7 @@ func TestUserService_GetUserByID(t *testing.T) {
 
 func TestUserService_GetUserByID_NotFound(t *testing.T) {
 	mockRepo := mocks.NewUserRepository(t)
-	userService := services.NewUserService(mockRepo)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
 
 	ctx := context.Background()
 
@@ -119,18 +171,30 @@ func TestUserService_GetUserByID_NotFound(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestUserService_GetUserByID_Missing(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+
+	userID := primitive.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, userID).Return(nil, nil)
+
+	result, err := userService.GetUserByID(context.Background(), userID.Hex())
+
+	assert.ErrorIs(t, err, domains.ErrNotFound)
+	assert.Nil(t, result)
+}
+
 func TestUserService_GetUsers(t *testing.T) {
 	mockRepo := mocks.NewUserRepository(t)
-	userService := services.NewUserService(mockRepo)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
 
 	ctx := context.Background()
 
 	findParams := domains.FindAllUsers{
-		Page:  1,
 		Limit: 10,
 	}
 
-	expectedUsers := []domains.User{
+	expectedPage := &domains.UserPage{Users: []domains.User{
 		{
 			ID:        primitive.NewObjectID(),
 			Name:      "John Doe",
@@ -145,34 +209,35 @@ func TestUserService_GetUsers(t *testing.T) {
 			Password:  "hashedpassword456",
 			CreatedAt: time.Now(),
 		},
-	}
+	}}
 
-	mockRepo.On("GetUsers", mock.Anything, findParams).
-		Return(expectedUsers, nil)
+	mockRepo.On("GetUsers", mock.Anything, domains.UserQuery{
+		FindAllUsers: findParams,
+		SortBy:       domains.UserSort{Field: "created_at"},
+	}).Return(expectedPage, nil)
 
 	result, err := userService.GetUsers(ctx, findParams)
 
 	assert.NoError(t, err)
-	assert.Equal(t, expectedUsers, result)
-	assert.Len(t, result, 2)
-	assert.Equal(t, "John Doe", result[0].Name)
-	assert.Equal(t, "john@example.com", result[0].Email)
+	assert.Equal(t, expectedPage, result)
+	assert.Len(t, result.Users, 2)
+	assert.Equal(t, "John Doe", result.Users[0].Name)
+	assert.Equal(t, "john@example.com", result.Users[0].Email)
 
 	mockRepo.AssertExpectations(t)
 }
 
 func TestUserService_GetUsers_Error(t *testing.T) {
 	mockRepo := mocks.NewUserRepository(t)
-	userService := services.NewUserService(mockRepo)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
 
 	ctx := context.Background()
 
 	findParams := domains.FindAllUsers{
-		Page:  1,
 		Limit: 10,
 	}
 
-	mockRepo.On("GetUsers", mock.Anything, findParams).
+	mockRepo.On("GetUsers", mock.Anything, mock.AnythingOfType("domains.UserQuery")).
 		Return(nil, assert.AnError)
 
 	result, err := userService.GetUsers(ctx, findParams)
@@ -183,26 +248,75 @@ func TestUserService_GetUsers_Error(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestUserService_GetUsers_Cursor(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+
+	last := domains.User{ID: primitive.NewObjectID(), Name: "Mali", CreatedAt: time.Now()}
+	sort := domains.UserSort{Field: "name", Descending: true}
+	cursor := domains.CursorAfter(last, sort)
+
+	mockRepo.On("GetUsers", mock.Anything, mock.MatchedBy(func(q domains.UserQuery) bool {
+		return q.SortBy == sort && q.After != nil && *q.After == cursor && q.Limit == 10
+	})).Return(&domains.UserPage{}, nil)
+
+	_, err := userService.GetUsers(context.Background(), domains.FindAllUsers{Sort: "-name", Cursor: cursor.Encode()})
+	assert.NoError(t, err)
+
+	_, err = userService.GetUsers(context.Background(), domains.FindAllUsers{Sort: "name", Cursor: cursor.Encode()})
+	assert.ErrorIs(t, err, domains.ErrValidation, "cursor from another sort order")
+
+	_, err = userService.GetUsers(context.Background(), domains.FindAllUsers{Cursor: "not-a-cursor"})
+	assert.ErrorIs(t, err, domains.ErrValidation)
+
+	_, err = userService.GetUsers(context.Background(), domains.FindAllUsers{Sort: "password"})
+	assert.ErrorIs(t, err, domains.ErrValidation)
+}
+
+func TestUserCursor_CreatedAtRoundTrip(t *testing.T) {
+	created := time.Date(2024, 3, 1, 10, 30, 0, 123456789, time.UTC)
+	sort := domains.UserSort{Field: "created_at"}
+	c := domains.CursorAfter(domains.User{ID: primitive.NewObjectID(), CreatedAt: created}, sort)
+
+	decoded, err := domains.DecodeUserCursor(c.Encode(), sort)
+
+	assert.NoError(t, err)
+	assert.Equal(t, created, decoded.SortValue())
+}
+
 func TestTransfer_Success(t *testing.T) {
 	mockRepo := mocks.NewUserRepository(t)
-	userService := services.NewUserService(mockRepo)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
 
 	ctx := context.Background()
 	fromID := primitive.NewObjectID()
 	toID := primitive.NewObjectID()
 	amount := 50.0
 
-	mockRepo.On("TransferWithTransaction", ctx, fromID, toID, amount).Return(nil)
+	expected := &domains.Transfer{
+		ID:         primitive.NewObjectID(),
+		FromUserID: fromID,
+		ToUserID:   toID,
+		Amount:     amount,
+	}
+
+	mockRepo.On("GetByID", ctx, fromID).Return(&domains.User{ID: fromID, Balance: 100}, nil)
+	mockRepo.On("TransferWithTransaction", ctx, domains.Transfer{
+		FromUserID: fromID,
+		ToUserID:   toID,
+		Amount:     amount,
+	}, domains.TransferLimits{}).Return(expected, nil)
 
-	err := userService.TransferBalance(ctx, fromID.Hex(), toID.Hex(), amount)
+	result, err := userService.TransferBalance(ctx, domains.TransferRequest{FromUserID: fromID.Hex(), ToUserID: toID.Hex(), Amount: amount})
 
 	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestTransfer_Error(t *testing.T) {
 	mockRepo := mocks.NewUserRepository(t)
-	userService := services.NewUserService(mockRepo)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
 
 	ctx := context.Background()
 	fromID := primitive.NewObjectID()
@@ -211,12 +325,465 @@ func TestTransfer_Error(t *testing.T) {
 
 	expectedErr := fmt.Errorf("transaction failed")
 
-	mockRepo.On("TransferWithTransaction", ctx, fromID, toID, amount).Return(expectedErr)
+	mockRepo.On("GetByID", ctx, fromID).Return(&domains.User{ID: fromID, Balance: 100}, nil)
+	mockRepo.On("TransferWithTransaction", ctx, mock.AnythingOfType("domains.Transfer"), domains.TransferLimits{}).
+		Return(nil, expectedErr)
 
-	err := userService.TransferBalance(ctx, fromID.Hex(), toID.Hex(), amount)
+	result, err := userService.TransferBalance(ctx, domains.TransferRequest{FromUserID: fromID.Hex(), ToUserID: toID.Hex(), Amount: amount})
 
 	assert.Error(t, err)
+	assert.Nil(t, result)
 	assert.Equal(t, expectedErr.Error(), err.Error())
 
 	mockRepo.AssertExpectations(t)
 }
+
+func TestTransfer_UserOverridesArePassedToRepository(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+
+	ctx := context.Background()
+	fromID := primitive.NewObjectID()
+	toID := primitive.NewObjectID()
+	daily := 200.0
+	perHour := 3
+
+	mockRepo.On("GetByID", ctx, fromID).Return(&domains.User{
+		ID: fromID,
+		LimitOverrides: &domains.LimitOverrides{
+			DailyOutgoing:       &daily,
+			MaxTransfersPerHour: &perHour,
+		},
+	}, nil)
+	mockRepo.On("TransferWithTransaction", ctx, mock.AnythingOfType("domains.Transfer"), domains.TransferLimits{
+		DailyOutgoing:       200,
+		MaxTransfersPerHour: 3,
+	}).Return(&domains.Transfer{}, nil)
+
+	_, err := userService.TransferBalance(ctx, domains.TransferRequest{FromUserID: fromID.Hex(), ToUserID: toID.Hex(), Amount: 50})
+
+	assert.NoError(t, err)
+}
+
+func TestTransfer_ExceedsMaxPerTransaction(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+
+	ctx := context.Background()
+	fromID := primitive.NewObjectID()
+	toID := primitive.NewObjectID()
+	maxAmount := 10.0
+
+	mockRepo.On("GetByID", ctx, fromID).Return(&domains.User{
+		ID:             fromID,
+		LimitOverrides: &domains.LimitOverrides{MaxPerTransaction: &maxAmount},
+	}, nil)
+
+	result, err := userService.TransferBalance(ctx, domains.TransferRequest{FromUserID: fromID.Hex(), ToUserID: toID.Hex(), Amount: 50})
+
+	var limitErr *domains.LimitExceededError
+	assert.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, domains.LimitMaxPerTransaction, limitErr.Limit)
+	assert.True(t, limitErr.ResetsAt.IsZero())
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "TransferWithTransaction", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTransferLimits_CheckReportsReset(t *testing.T) {
+	now := time.Date(2026, 3, 14, 15, 30, 0, 0, time.UTC)
+	limits := domains.TransferLimits{DailyOutgoing: 100, MonthlyOutgoing: 1000, MaxTransfersPerHour: 2}
+
+	err := limits.Check(domains.TransferUsage{HourlyCount: 2}, 1, now)
+	var limitErr *domains.LimitExceededError
+	assert.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, domains.LimitMaxTransfersPerHour, limitErr.Limit)
+	assert.Equal(t, time.Date(2026, 3, 14, 16, 0, 0, 0, time.UTC), limitErr.ResetsAt)
+
+	err = limits.Check(domains.TransferUsage{DailyTotal: 90}, 20, now)
+	assert.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, domains.LimitDailyOutgoing, limitErr.Limit)
+	assert.Equal(t, time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC), limitErr.ResetsAt)
+
+	err = limits.Check(domains.TransferUsage{DailyTotal: 10, MonthlyTotal: 995}, 20, now)
+	assert.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, domains.LimitMonthlyOutgoing, limitErr.Limit)
+	assert.Equal(t, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), limitErr.ResetsAt)
+
+	assert.NoError(t, limits.Check(domains.TransferUsage{HourlyCount: 1, DailyTotal: 50, MonthlyTotal: 500}, 50, now))
+}
+
+func TestLimitTiers_For(t *testing.T) {
+	standard := domains.TransferLimits{MaxPerTransaction: 500, DailyOutgoing: 1000}
+	premium := domains.TransferLimits{MaxPerTransaction: 5000}
+	tiers := domains.LimitTiers{"standard": standard, "premium": premium}
+
+	limits, err := tiers.For("Premium", "standard")
+	assert.NoError(t, err)
+	assert.Equal(t, premium, limits)
+
+	limits, err = tiers.For("", "standard")
+	assert.NoError(t, err)
+	assert.Equal(t, standard, limits)
+
+	limits, err = tiers.For("premuim", "standard")
+	assert.NoError(t, err)
+	assert.Equal(t, standard, limits)
+
+	_, err = tiers.For("premuim", "basic")
+	assert.ErrorIs(t, err, domains.ErrUnavailable)
+}
+
+func TestTransfer_InvalidSenderIsValidationError(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+
+	_, err := userService.TransferBalance(context.Background(), domains.TransferRequest{
+		FromUserID: "not-an-id", ToUserID: primitive.NewObjectID().Hex(), Amount: 10,
+	})
+
+	var derr *domains.Error
+	assert.ErrorIs(t, err, domains.ErrValidation)
+	assert.ErrorAs(t, err, &derr)
+	assert.Equal(t, []domains.FieldError{{Field: "fromUserId", Code: "invalid", Message: "invalid from user ID"}}, derr.Fields)
+}
+
+func TestUserService_UpdateUser(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+
+	id := primitive.NewObjectID()
+	name, handle := "  Jane Doe ", "@Jane_D"
+	updated := &domains.User{ID: id, Name: "Jane Doe", Handle: "jane_d"}
+	mockRepo.On("Update", mock.Anything, id, mock.MatchedBy(func(in domains.UpdateUserRequest) bool {
+		return *in.Name == "Jane Doe" && *in.Handle == "jane_d" && in.Phone == nil
+	}), (*int64)(nil)).Return(updated, nil)
+
+	result, err := userService.UpdateUser(context.Background(), id.Hex(), domains.UpdateUserRequest{Name: &name, Handle: &handle}, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, updated, result)
+}
+
+func TestUserService_UpdateUser_DeletedIsNotFound(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+
+	id := primitive.NewObjectID()
+	name := "Jane"
+	mockRepo.On("Update", mock.Anything, id, mock.Anything, mock.Anything).Return(nil, nil)
+
+	result, err := userService.UpdateUser(context.Background(), id.Hex(), domains.UpdateUserRequest{Name: &name}, nil)
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, domains.ErrNotFound)
+}
+
+func TestUserService_UpdateUser_StaleVersion(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+
+	id := primitive.NewObjectID()
+	name := "Jane"
+	expected := int64(3)
+	mockRepo.On("Update", mock.Anything, id, mock.Anything, &expected).Return(nil, nil)
+	mockRepo.On("GetByID", mock.Anything, id).Return(&domains.User{ID: id, Version: 4}, nil)
+
+	result, err := userService.UpdateUser(context.Background(), id.Hex(), domains.UpdateUserRequest{Name: &name}, &expected)
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, domains.ErrStale)
+	var derr *domains.Error
+	if assert.ErrorAs(t, err, &derr) {
+		assert.Equal(t, "version_mismatch", derr.Code)
+	}
+}
+
+func TestUserService_DeleteUser(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+
+	id := primitive.NewObjectID()
+	deletedAt := time.Now()
+	mockRepo.On("SoftDelete", mock.Anything, id, mock.AnythingOfType("time.Time")).
+		Return(&domains.User{ID: id, DeletedAt: &deletedAt}, nil)
+
+	result, err := userService.DeleteUser(context.Background(), id.Hex())
+
+	assert.NoError(t, err)
+	assert.True(t, result.Deleted())
+}
+
+func TestUserService_DeleteUser_AlreadyDeleted(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+
+	id := primitive.NewObjectID()
+	deletedAt := time.Now()
+	mockRepo.On("SoftDelete", mock.Anything, id, mock.Anything).Return(nil, nil)
+	mockRepo.On("GetByID", mock.Anything, id).Return(&domains.User{ID: id, DeletedAt: &deletedAt}, nil)
+
+	result, err := userService.DeleteUser(context.Background(), id.Hex())
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, domains.ErrConflict)
+}
+
+func TestUserService_RestoreUser_NotDeleted(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+
+	id := primitive.NewObjectID()
+	mockRepo.On("Restore", mock.Anything, id).Return(nil, nil)
+	mockRepo.On("GetByID", mock.Anything, id).Return(&domains.User{ID: id}, nil)
+
+	result, err := userService.RestoreUser(context.Background(), id.Hex())
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, domains.ErrConflict)
+	assert.Equal(t, "user is not deleted", err.Error())
+}
+
+func TestTransfer_DeletedSenderIsRejected(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+
+	fromID, toID := primitive.NewObjectID(), primitive.NewObjectID()
+	deletedAt := time.Now()
+	mockRepo.On("GetByID", mock.Anything, fromID).Return(&domains.User{ID: fromID, Balance: 100, DeletedAt: &deletedAt}, nil)
+
+	result, err := userService.TransferBalance(context.Background(), domains.TransferRequest{
+		FromUserID: fromID.Hex(),
+		ToUserID:   toID.Hex(),
+		Amount:     10,
+	})
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, domains.ErrConflict)
+	mockRepo.AssertNotCalled(t, "TransferWithTransaction", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUserService_GetAccount(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+
+	id := primitive.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, id).Return(&domains.User{
+		ID:            id,
+		Name:          "John Doe",
+		Email:         "john@example.com",
+		EmailVerified: true,
+		Phone:         "+66812345678",
+		Balance:       250,
+		Role:          domains.RoleUser,
+	}, nil)
+
+	own, err := userService.GetAccount(context.Background(), id.Hex(), domains.VisibilityOwner)
+	assert.NoError(t, err)
+	assert.Equal(t, "John Doe", own.Name)
+	assert.Equal(t, 250.0, *own.Balance)
+	assert.True(t, *own.EmailVerified)
+	assert.Equal(t, "+66812345678", own.Phone)
+	assert.NotNil(t, own.Limits)
+	assert.Empty(t, own.Role, "role is staff-only")
+
+	public, err := userService.GetAccount(context.Background(), id.Hex(), domains.VisibilityPublic)
+	assert.NoError(t, err)
+	assert.Equal(t, domains.UserView{ID: id, Name: "John Doe"}, *public)
+}
+
+func TestUserService_GetAccount_DeletedHiddenFromNonStaff(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+
+	id := primitive.NewObjectID()
+	deletedAt := time.Now()
+	mockRepo.On("GetByID", mock.Anything, id).Return(&domains.User{ID: id, DeletedAt: &deletedAt}, nil)
+
+	_, err := userService.GetAccount(context.Background(), id.Hex(), domains.VisibilityOwner)
+	assert.ErrorIs(t, err, domains.ErrNotFound)
+
+	staff, err := userService.GetAccount(context.Background(), id.Hex(), domains.VisibilityStaff)
+	assert.NoError(t, err)
+	assert.Equal(t, &deletedAt, staff.DeletedAt)
+}
+
+func TestVisibilityFor(t *testing.T) {
+	id := primitive.NewObjectID().Hex()
+	assert.Equal(t, domains.VisibilityOwner, domains.VisibilityFor(&domains.JWTClaims{ID: id, Role: domains.RoleUser}, id))
+	assert.Equal(t, domains.VisibilityPublic, domains.VisibilityFor(&domains.JWTClaims{ID: "other", Role: domains.RoleUser}, id))
+	assert.Equal(t, domains.VisibilityStaff, domains.VisibilityFor(&domains.JWTClaims{ID: "other", Role: domains.RoleSupport}, id))
+}
+
+func TestUserService_GetUsers_Filters(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+	ctx := context.Background()
+
+	lo, hi := 500.0, 100.0
+	_, err := userService.GetUsers(ctx, domains.FindAllUsers{MinBalance: &lo, MaxBalance: &hi})
+	assert.ErrorIs(t, err, domains.ErrValidation)
+
+	_, err = userService.GetUsers(ctx, domains.FindAllUsers{Status: "paused"})
+	assert.ErrorIs(t, err, domains.ErrValidation)
+
+	_, err = userService.GetUsers(ctx, domains.FindAllUsers{Sort: domains.SortRelevance})
+	assert.ErrorIs(t, err, domains.ErrValidation, "relevance without a search")
+
+	// Text search is off unless configured.
+	_, err = userService.GetUsers(ctx, domains.FindAllUsers{Search: "somchai"})
+	assert.ErrorIs(t, err, domains.ErrValidation)
+
+	mockRepo.On("GetUsers", mock.Anything, mock.MatchedBy(func(q domains.UserQuery) bool {
+		return q.Name == "Jo.*(" && q.Status == domains.StatusFrozen
+	})).Return(&domains.UserPage{}, nil)
+	_, err = userService.GetUsers(ctx, domains.FindAllUsers{Name: "Jo.*(", Status: domains.StatusFrozen})
+	assert.NoError(t, err)
+}
+
+func TestUserCursor_RelevanceRoundTrip(t *testing.T) {
+	id := primitive.NewObjectID()
+	sort, err := domains.ParseUserSort(domains.SortRelevance)
+	assert.NoError(t, err)
+
+	decoded, err := domains.DecodeUserCursor(domains.RelevanceCursor(id, 1.0833333333333333).Encode(), sort)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0833333333333333, decoded.SortValue())
+	assert.Equal(t, id, decoded.ID)
+
+	_, err = domains.ParseUserSort("-" + domains.SortRelevance)
+	assert.ErrorIs(t, err, domains.ErrValidation)
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	cases := map[string]string{
+		"  Bob@Example.COM ": "Bob@example.com",
+		"ana@Bücher.de":      "ana@xn--bcher-kva.de",
+	}
+	for in, want := range cases {
+		got, err := domains.NormalizeEmail(in)
+		assert.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	_, err := domains.NormalizeEmail("bob@")
+	assert.ErrorIs(t, err, domains.ErrValidation)
+	assert.Equal(t, domains.EmailIdentity("BOB@example.com"), domains.EmailIdentity("bob@EXAMPLE.com"))
+}
+
+func TestUserService_CreateUser_NormalizesEmail(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(u domains.User) bool {
+		return u.Email == "Bob@example.com"
+	})).Return(nil, domains.Conflict("email_taken", "email is already registered"))
+
+	_, err := userService.CreateUser(context.Background(), domains.CreateUserRequest{
+		Name:     "Bob",
+		Email:    "Bob@EXAMPLE.com",
+		Password: "password123",
+	})
+
+	assert.ErrorIs(t, err, domains.ErrConflict)
+}
+
+func TestUserService_MigrateEmails(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+	ctx := context.Background()
+
+	bob1, bob2, ana, cat := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	mockRepo.On("ListEmails", ctx).Return([]domains.EmailRecord{
+		{UserID: bob1, Email: "bob@x.com"},
+		{UserID: ana, Email: "Ana@X.COM"},
+		{UserID: bob2, Email: "Bob@X.com"},
+		{UserID: cat, Email: "cat@x.com"},
+	}, nil)
+	mockRepo.On("SetEmail", ctx, ana, "Ana@x.com").Return(nil)
+
+	report, err := userService.MigrateEmails(ctx, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, report.Checked)
+	assert.Equal(t, 1, report.Normalized)
+	assert.Equal(t, [][]domains.EmailRecord{{
+		{UserID: bob1, Email: "bob@x.com"},
+		{UserID: bob2, Email: "Bob@X.com"},
+	}}, report.Collisions)
+	assert.False(t, report.IndexCreated, "index waits until collisions are resolved")
+	mockRepo.AssertNotCalled(t, "EnsureEmailIndex", mock.Anything)
+}
+
+func TestUserService_MigrateEmails_DryRunBuildsNothing(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+	ctx := context.Background()
+
+	mockRepo.On("ListEmails", ctx).Return([]domains.EmailRecord{{UserID: primitive.NewObjectID(), Email: "Ana@X.COM"}}, nil)
+
+	report, err := userService.MigrateEmails(ctx, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Normalized)
+	assert.False(t, report.IndexCreated)
+}
+
+func TestCanTransition(t *testing.T) {
+	assert.True(t, domains.CanTransition(domains.StatusActive, domains.StatusSuspended))
+	assert.True(t, domains.CanTransition(domains.StatusFrozen, domains.StatusActive))
+	assert.False(t, domains.CanTransition(domains.StatusClosed, domains.StatusActive), "closed is final")
+	assert.False(t, domains.CanTransition(domains.StatusActive, domains.StatusActive))
+	assert.Equal(t, domains.StatusFrozen, domains.User{Frozen: true}.AccountStatus(), "legacy frozen flag")
+	assert.Equal(t, domains.StatusActive, domains.User{}.AccountStatus())
+}
+
+func TestUserService_ChangeStatus_RecordsActor(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+	id := primitive.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, id).Return(&domains.User{ID: id, Status: domains.StatusActive}, nil)
+	mockRepo.On("SetStatus", mock.Anything, mock.MatchedBy(func(c domains.StatusChange) bool {
+		return c.UserID == id && c.From == domains.StatusActive && c.To == domains.StatusSuspended &&
+			c.ActorID == "admin-1" && c.Reason == "chargeback review"
+	})).Return(&domains.User{ID: id, Status: domains.StatusSuspended}, nil)
+
+	u, err := userService.ChangeStatus(context.Background(), id.Hex(), "admin-1", domains.ChangeStatusRequest{
+		Status: domains.StatusSuspended,
+		Reason: " chargeback review ",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domains.StatusSuspended, u.Status)
+}
+
+func TestUserService_ChangeStatus_Rejected(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+	ctx := context.Background()
+	closed, raced := primitive.NewObjectID(), primitive.NewObjectID()
+	mockRepo.On("GetByID", mock.Anything, closed).Return(&domains.User{ID: closed, Status: domains.StatusClosed}, nil)
+	mockRepo.On("GetByID", mock.Anything, raced).Return(&domains.User{ID: raced, Status: domains.StatusActive}, nil)
+	mockRepo.On("SetStatus", mock.Anything, mock.Anything).Return(nil, nil)
+
+	_, err := userService.ChangeStatus(ctx, closed.Hex(), "admin-1", domains.ChangeStatusRequest{Status: domains.StatusActive, Reason: "reopen"})
+	assert.ErrorIs(t, err, domains.ErrConflict)
+
+	_, err = userService.ChangeStatus(ctx, raced.Hex(), "admin-1", domains.ChangeStatusRequest{Status: domains.StatusFrozen})
+	assert.ErrorIs(t, err, domains.ErrValidation, "reason is required")
+
+	_, err = userService.ChangeStatus(ctx, raced.Hex(), "admin-1", domains.ChangeStatusRequest{Status: domains.StatusFrozen, Reason: "fraud"})
+	assert.ErrorIs(t, err, domains.ErrConflict, "changed concurrently")
+}
+
+func TestUserService_ReencryptUsers_RunsBatchesUntilDone(t *testing.T) {
+	mockRepo := mocks.NewUserRepository(t)
+	userService := services.NewUserService(mockRepo, mocks.NewFXRepository(t))
+	mockRepo.On("ReencryptUsers", mock.Anything, 200).Return(200, nil).Twice()
+	mockRepo.On("ReencryptUsers", mock.Anything, 200).Return(37, nil).Once()
+
+	updated, err := userService.ReencryptUsers(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 437, updated)
+	mockRepo.AssertNumberOfCalls(t, "ReencryptUsers", 3)
+}
@@ -26,14 +26,14 @@ func (h *authhandler) AuthRoutes(rg *gin.RouterGroup) {
 func (h *authhandler) LoginHandler(c *gin.Context) {
 	var req domains.LoginRequest
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+	if err := bindJSON(c, &req); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
 	resp, err := h.authsvc.Login(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+)
+
+// claims returns the JWT claims set by middleware.AuthenMiddleware.
+func claims(c *gin.Context) *domains.JWTClaims {
+	if v, ok := c.Get("user"); ok {
+		if cl, ok := v.(*domains.JWTClaims); ok {
+			return cl
+		}
+	}
+	return &domains.JWTClaims{}
+}
+
+func isStaff(c *gin.Context) bool {
+	role := claims(c).Role
+	return role == domains.RoleAdmin || role == domains.RoleSupport
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/middleware"
+	"github.com/wansanjou/backend-exercise-user-api/utils"
+)
+
+// bindJSON decodes the request body into req, rejecting fields the endpoint does not
+// accept, then checks req's validate tags. Decoder internals are never echoed back.
+func bindJSON(c *gin.Context, req any) error {
+	if c.Request.Body == nil {
+		return domains.Invalid("body", "request body is required")
+	}
+	dec := json.NewDecoder(c.Request.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(req); err != nil {
+		return decodeError(err)
+	}
+	if dec.More() {
+		return domains.Invalid("body", "request body must be a single JSON value")
+	}
+	return domains.Validate(req)
+}
+
+// failDownload reports an error from writing a file download. If nothing has been sent yet
+// the error goes out as usual instead of as the attachment; otherwise the status line has
+// gone out and all we can do is cut the response short.
+func failDownload(c *gin.Context, err error) {
+	if !c.Writer.Written() {
+		c.Writer.Header().Del("Content-Disposition")
+	}
+	middleware.Fail(c, err)
+}
+
+func decodeError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	field, unknown := utils.UnknownJSONField(err)
+	switch {
+	case errors.Is(err, io.EOF):
+		return domains.Invalid("body", "request body is required")
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return domains.InvalidFields([]domains.FieldError{{
+			Field:   typeErr.Field,
+			Code:    "invalid_type",
+			Message: typeErr.Field + " must be " + jsonKind(typeErr.Type),
+		}})
+	case unknown:
+		return domains.InvalidFields([]domains.FieldError{{
+			Field:   field,
+			Code:    "unknown_field",
+			Message: field + " is not an accepted field",
+		}})
+	default:
+		return domains.Invalid("body", "request body is not valid JSON")
+	}
+}
+
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+)
+
+// etag is the strong entity tag of a document at version.
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ifMatch reads the If-Match header as the document version a write expects. It returns
+// nil when there is no header or it is "*". A weak or unrecognised tag can never match,
+// so it is reported as stale.
+func ifMatch(c *gin.Context) (*int64, error) {
+	h := strings.TrimSpace(c.GetHeader("If-Match"))
+	if h == "" || h == "*" {
+		return nil, nil
+	}
+	if len(h) < 3 || h[0] != '"' || h[len(h)-1] != '"' {
+		return nil, domains.Stale("If-Match must be a single ETag returned by this API")
+	}
+	v, err := strconv.ParseInt(h[1:len(h)-1], 10, 64)
+	if err != nil {
+		return nil, domains.Stale("If-Match must be a single ETag returned by this API")
+	}
+	return &v, nil
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports"
+	"github.com/wansanjou/backend-exercise-user-api/middleware"
+)
+
+type fxhdl struct {
+	fxsvc ports.FXService
+}
+
+func NewFXHandler(fxsvc ports.FXService) *fxhdl {
+	return &fxhdl{
+		fxsvc: fxsvc,
+	}
+}
+
+func (h *fxhdl) FXRoutes(rg *gin.RouterGroup) {
+	fx := rg.Group("/fx")
+	fx.Use(middleware.AuthenMiddleware())
+	fx.GET("/rates", h.ListRates)
+	fx.POST("/quotes", h.CreateQuote)
+
+	admin := fx.Group("")
+	admin.Use(middleware.RequireRole(domains.RoleAdmin))
+	admin.POST("/rates", h.ImportRates)
+}
+
+func (h *fxhdl) ListRates(c *gin.Context) {
+	rates, err := h.fxsvc.ListRates(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rates)
+}
+
+func (h *fxhdl) ImportRates(c *gin.Context) {
+	var req []domains.FXRateRequest
+	if err := bindJSON(c, &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	rates := make([]domains.FXRate, len(req))
+	for i, r := range req {
+		rates[i] = r.FXRate()
+		if rates[i].Source == "" {
+			rates[i].Source = "admin:" + claims(c).ID
+		}
+	}
+
+	added, err := h.fxsvc.ImportRates(c, rates)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"received": len(req), "added": added})
+}
+
+func (h *fxhdl) CreateQuote(c *gin.Context) {
+	var req domains.CreateFXQuoteRequest
+	if err := bindJSON(c, &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	caller := claims(c)
+	if req.UserID == "" {
+		req.UserID = caller.ID
+	}
+	if caller.ID != req.UserID && caller.Role != domains.RoleAdmin {
+		_ = c.Error(domains.Forbidden("cannot quote for another user's account"))
+		return
+	}
+
+	quote, err := h.fxsvc.CreateQuote(c, req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, quote)
+}
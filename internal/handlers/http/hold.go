@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports"
+	"github.com/wansanjou/backend-exercise-user-api/middleware"
+)
+
+type holdhdl struct {
+	holdsvc ports.HoldService
+}
+
+func NewHoldHandler(holdsvc ports.HoldService) *holdhdl {
+	return &holdhdl{
+		holdsvc: holdsvc,
+	}
+}
+
+func (h *holdhdl) HoldRoutes(rg *gin.RouterGroup) {
+	holds := rg.Group("/holds")
+	holds.Use(middleware.AuthenMiddleware())
+	holds.POST("/", h.CreateHold)
+	holds.GET("/:id", h.GetHold)
+	holds.POST("/:id/capture", h.CaptureHold)
+	holds.POST("/:id/void", h.VoidHold)
+}
+
+func (h *holdhdl) CreateHold(c *gin.Context) {
+	var req domains.CreateHoldRequest
+	if err := bindJSON(c, &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if claims(c).ID != req.FromUserID && !isStaff(c) {
+		_ = c.Error(domains.Forbidden("cannot hold funds from another user's account"))
+		return
+	}
+
+	hold, err := h.holdsvc.CreateHold(c, req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, hold)
+}
+
+func (h *holdhdl) GetHold(c *gin.Context) {
+	hold, ok := h.owned(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, hold)
+}
+
+func (h *holdhdl) CaptureHold(c *gin.Context) {
+	var req domains.CaptureHoldRequest
+	if c.Request.ContentLength > 0 {
+		if err := bindJSON(c, &req); err != nil {
+			_ = c.Error(err)
+			return
+		}
+	}
+
+	held, ok := h.owned(c)
+	if !ok {
+		return
+	}
+
+	hold, err := h.holdsvc.CaptureHold(c, held.ID.Hex(), req.Amount)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hold)
+}
+
+func (h *holdhdl) VoidHold(c *gin.Context) {
+	held, ok := h.owned(c)
+	if !ok {
+		return
+	}
+
+	hold, err := h.holdsvc.VoidHold(c, held.ID.Hex())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hold)
+}
+
+// owned loads the hold in the path and answers 404 unless the caller placed it or is staff.
+func (h *holdhdl) owned(c *gin.Context) (*domains.Hold, bool) {
+	hold, err := h.holdsvc.GetHold(c, c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return nil, false
+	}
+	if hold.FromUserID.Hex() != claims(c).ID && !isStaff(c) {
+		_ = c.Error(domains.NotFound("hold"))
+		return nil, false
+	}
+	return hold, true
+}
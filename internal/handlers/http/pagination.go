@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// page is the envelope of a cursor-paginated listing. NextCursor is null on the last page.
+type page struct {
+	Data       interface{} `json:"data"`
+	NextCursor *string     `json:"next_cursor"`
+	Total      *int64      `json:"total,omitempty"`
+}
+
+// setPageLinks sets an RFC 8288 Link header pointing at the first page and, if there is
+// one, the next page. Other query parameters are kept as they are.
+func setPageLinks(c *gin.Context, next string) {
+	links := []string{pageLink(c, "", "first")}
+	if next != "" {
+		links = append(links, pageLink(c, next, "next"))
+	}
+	c.Header("Link", strings.Join(links, ", "))
+}
+
+func pageLink(c *gin.Context, cursor, rel string) string {
+	u := *c.Request.URL
+	q := u.Query()
+	q.Del("cursor")
+	if cursor != "" {
+		q.Set("cursor", cursor)
+	}
+	u.RawQuery = q.Encode()
+	u.Scheme, u.Host = "", ""
+	return fmt.Sprintf(`<%s>; rel="%s"`, u.String(), rel)
+}
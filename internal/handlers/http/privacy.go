@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports"
+	"github.com/wansanjou/backend-exercise-user-api/middleware"
+)
+
+type privacyhdl struct {
+	privsvc ports.PrivacyService
+}
+
+func NewPrivacyHandler(privsvc ports.PrivacyService) *privacyhdl {
+	return &privacyhdl{
+		privsvc: privsvc,
+	}
+}
+
+func (h *privacyhdl) PrivacyRoutes(rg *gin.RouterGroup) {
+	users := rg.Group("/users")
+	users.Use(middleware.AuthenMiddleware())
+	users.GET("/me/export", h.ExportMyData)
+	users.POST("/me/erasure", h.RequestErasure)
+	users.GET("/me/erasure", h.ListMyErasureRequests)
+
+	admin := users.Group("")
+	admin.Use(middleware.RequireRole(domains.RoleAdmin))
+	admin.POST("/:id/erasure", h.Erase)
+	admin.GET("/:id/erasure", h.ListErasureRequests)
+}
+
+// ExportMyData downloads everything held about the signed-in user as a zip archive.
+func (h *privacyhdl) ExportMyData(c *gin.Context) {
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="my-data-%s.zip"`, time.Now().UTC().Format("20060102")))
+	c.Status(http.StatusOK)
+	if err := h.privsvc.ExportData(c, claims(c).ID, c.Writer); err != nil {
+		failDownload(c, err)
+	}
+}
+
+// RequestErasure records the signed-in user's request to have their data erased. An admin
+// carries it out.
+func (h *privacyhdl) RequestErasure(c *gin.Context) {
+	req, err := h.privsvc.RequestErasure(c, claims(c).ID, claims(c).ID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, req)
+}
+
+func (h *privacyhdl) ListMyErasureRequests(c *gin.Context) {
+	h.listErasureRequests(c, claims(c).ID)
+}
+
+// Erase pseudonymizes a user's personal data, completing their erasure request.
+func (h *privacyhdl) Erase(c *gin.Context) {
+	req, err := h.privsvc.Erase(c, c.Param("id"), claims(c).ID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, req)
+}
+
+func (h *privacyhdl) ListErasureRequests(c *gin.Context) {
+	h.listErasureRequests(c, c.Param("id"))
+}
+
+func (h *privacyhdl) listErasureRequests(c *gin.Context, userID string) {
+	requests, err := h.privsvc.ListErasureRequests(c, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports"
+	"github.com/wansanjou/backend-exercise-user-api/middleware"
+)
+
+type recipienthdl struct {
+	recipientsvc ports.RecipientService
+	lookups      *middleware.Limiter
+}
+
+// NewLookupLimiter limits how many aliases each user may resolve per hour, whether by
+// looking them up or by paying them, so that neither can be used to enumerate users.
+func NewLookupLimiter(perHour int) *middleware.Limiter {
+	if perHour <= 0 {
+		perHour = 30
+	}
+	return middleware.NewLimiter(perHour, time.Hour)
+}
+
+func NewRecipientHandler(recipientsvc ports.RecipientService, lookups *middleware.Limiter) *recipienthdl {
+	return &recipienthdl{
+		recipientsvc: recipientsvc,
+		lookups:      lookups,
+	}
+}
+
+func (h *recipienthdl) RecipientRoutes(rg *gin.RouterGroup) {
+	recipients := rg.Group("/recipients")
+	recipients.Use(middleware.AuthenMiddleware())
+	recipients.GET("/lookup",
+		h.lookups.Handler(func(c *gin.Context) string { return claims(c).ID }),
+		h.LookupRecipient)
+}
+
+func (h *recipienthdl) LookupRecipient(c *gin.Context) {
+	alias := c.Query("alias")
+	if alias == "" {
+		_ = c.Error(domains.Invalid("alias", "alias is required"))
+		return
+	}
+
+	preview, err := h.recipientsvc.LookupRecipient(c, alias)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports"
+	"github.com/wansanjou/backend-exercise-user-api/middleware"
+)
+
+type schedulehdl struct {
+	schedsvc ports.ScheduledTransferService
+}
+
+func NewScheduledTransferHandler(schedsvc ports.ScheduledTransferService) *schedulehdl {
+	return &schedulehdl{
+		schedsvc: schedsvc,
+	}
+}
+
+func (h *schedulehdl) ScheduledTransferRoutes(rg *gin.RouterGroup) {
+	schedules := rg.Group("/scheduled-transfers")
+	schedules.Use(middleware.AuthenMiddleware())
+	schedules.POST("/", h.CreateScheduledTransfer)
+	schedules.GET("/", h.ListScheduledTransfers)
+	schedules.GET("/:id", h.GetScheduledTransfer)
+	schedules.GET("/:id/runs", h.ListRuns)
+	schedules.POST("/:id/pause", h.PauseScheduledTransfer)
+	schedules.POST("/:id/resume", h.ResumeScheduledTransfer)
+	schedules.DELETE("/:id", h.CancelScheduledTransfer)
+}
+
+func (h *schedulehdl) CreateScheduledTransfer(c *gin.Context) {
+	var req domains.CreateScheduledTransferRequest
+	if err := bindJSON(c, &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	caller := claims(c)
+	if caller.ID != req.FromUserID && caller.Role != domains.RoleAdmin {
+		_ = c.Error(domains.Forbidden("cannot schedule transfers from another user's account"))
+		return
+	}
+
+	st, err := h.schedsvc.CreateScheduledTransfer(c, req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, st)
+}
+
+func (h *schedulehdl) ListScheduledTransfers(c *gin.Context) {
+	userID := claims(c).ID
+	if q := c.Query("userId"); q != "" && isStaff(c) {
+		userID = q
+	}
+
+	schedules, err := h.schedsvc.ListScheduledTransfers(c, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schedules)
+}
+
+func (h *schedulehdl) GetScheduledTransfer(c *gin.Context) {
+	st, ok := h.owned(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, st)
+}
+
+func (h *schedulehdl) ListRuns(c *gin.Context) {
+	st, ok := h.owned(c)
+	if !ok {
+		return
+	}
+
+	runs, err := h.schedsvc.ListRuns(c, st.ID.Hex())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, runs)
+}
+
+func (h *schedulehdl) PauseScheduledTransfer(c *gin.Context) {
+	h.changeStatus(c, h.schedsvc.PauseScheduledTransfer)
+}
+
+func (h *schedulehdl) ResumeScheduledTransfer(c *gin.Context) {
+	h.changeStatus(c, h.schedsvc.ResumeScheduledTransfer)
+}
+
+func (h *schedulehdl) CancelScheduledTransfer(c *gin.Context) {
+	h.changeStatus(c, h.schedsvc.CancelScheduledTransfer)
+}
+
+func (h *schedulehdl) changeStatus(c *gin.Context, fn func(context.Context, string) (*domains.ScheduledTransfer, error)) {
+	st, ok := h.owned(c)
+	if !ok {
+		return
+	}
+
+	updated, err := fn(c, st.ID.Hex())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// owned loads the schedule in the path and answers 404 unless the caller owns it or is staff.
+func (h *schedulehdl) owned(c *gin.Context) (*domains.ScheduledTransfer, bool) {
+	st, err := h.schedsvc.GetScheduledTransfer(c, c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return nil, false
+	}
+	if st.FromUserID.Hex() != claims(c).ID && !isStaff(c) {
+		_ = c.Error(domains.NotFound("scheduled transfer"))
+		return nil, false
+	}
+	return st, true
+}
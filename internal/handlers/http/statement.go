@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports"
+	"github.com/wansanjou/backend-exercise-user-api/middleware"
+)
+
+var statementContentTypes = map[string]string{
+	domains.StatementCSV:    "text/csv; charset=utf-8",
+	domains.StatementNDJSON: "application/x-ndjson",
+	domains.StatementOFX:    "application/x-ofx",
+}
+
+type statementhdl struct {
+	stmtsvc ports.StatementService
+}
+
+func NewStatementHandler(stmtsvc ports.StatementService) *statementhdl {
+	return &statementhdl{
+		stmtsvc: stmtsvc,
+	}
+}
+
+func (h *statementhdl) StatementRoutes(rg *gin.RouterGroup) {
+	statements := rg.Group("/users/:id/statements")
+	statements.Use(middleware.AuthenMiddleware(), h.ownerOrStaff)
+	statements.GET("", h.GetStatement)
+	statements.GET("/monthly", h.ListMonthlyStatements)
+	statements.GET("/monthly/:statementId", h.DownloadMonthlyStatement)
+}
+
+// ownerOrStaff lets users read only their own statements; finance and support staff can read anyone's.
+func (h *statementhdl) ownerOrStaff(c *gin.Context) {
+	if claims(c).ID != c.Param("id") && !isStaff(c) {
+		middleware.Fail(c, domains.NotFound("user"))
+		return
+	}
+	c.Next()
+}
+
+func (h *statementhdl) GetStatement(c *gin.Context) {
+	format := c.DefaultQuery("format", domains.StatementCSV)
+	if !domains.ValidStatementFormat(format) {
+		_ = c.Error(domains.Invalid("format", "format must be csv, ndjson or ofx"))
+		return
+	}
+
+	now := time.Now().UTC()
+	from, err := statementTime(c.Query("from"), false, time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		_ = c.Error(domains.Invalid("from", "invalid from: "+err.Error()))
+		return
+	}
+	to, err := statementTime(c.Query("to"), true, now)
+	if err != nil {
+		_ = c.Error(domains.Invalid("to", "invalid to: "+err.Error()))
+		return
+	}
+
+	st, err := h.stmtsvc.PrepareStatement(c, c.Param("id"), from, to)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("Content-Type", statementContentTypes[format])
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="statement-%s-%s-%s.%s"`,
+		c.Param("id"), from.Format("20060102"), to.Format("20060102"), format))
+	c.Status(http.StatusOK)
+	if err := h.stmtsvc.WriteStatement(c, st, format, c.Writer); err != nil {
+		failDownload(c, err)
+	}
+}
+
+func (h *statementhdl) ListMonthlyStatements(c *gin.Context) {
+	statements, err := h.stmtsvc.ListStatements(c, c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statements)
+}
+
+func (h *statementhdl) DownloadMonthlyStatement(c *gin.Context) {
+	stored, file, err := h.stmtsvc.OpenStatement(c, c.Param("id"), c.Param("statementId"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	defer file.Close()
+
+	c.Header("Content-Type", statementContentTypes[stored.Format])
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="statement-%s-%s.%s"`,
+		stored.UserID.Hex(), stored.Period, stored.Format))
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, file); err != nil {
+		failDownload(c, err)
+	}
+}
+
+// statementTime parses an RFC 3339 timestamp or a plain date. A plain date used as the end
+// of a period covers that whole day.
+func statementTime(v string, end bool, fallback time.Time) (time.Time, error) {
+	if v == "" {
+		return fallback, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t.UTC(), nil
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return time.Time{}, errors.New("use YYYY-MM-DD or RFC 3339")
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
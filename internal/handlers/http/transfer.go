@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports"
+	"github.com/wansanjou/backend-exercise-user-api/middleware"
+)
+
+type transferhdl struct {
+	transfersvc ports.TransferService
+}
+
+func NewTransferHandler(transfersvc ports.TransferService) *transferhdl {
+	return &transferhdl{
+		transfersvc: transfersvc,
+	}
+}
+
+func (h *transferhdl) TransferRoutes(rg *gin.RouterGroup) {
+	transfers := rg.Group("/transfers")
+	transfers.Use(middleware.AuthenMiddleware())
+	transfers.POST("/quote", h.QuoteTransfer)
+	transfers.POST("/batch", h.CreateBatch)
+	transfers.GET("/batch/:id", h.GetBatch)
+
+	staff := transfers.Group("")
+	staff.Use(middleware.RequireRole(domains.RoleAdmin, domains.RoleSupport))
+	staff.GET("/:id", h.GetTransfer)
+	staff.POST("/:id/reverse", h.ReverseTransfer)
+}
+
+func (h *transferhdl) GetTransfer(c *gin.Context) {
+	transfer, err := h.transfersvc.GetTransfer(c, c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transfer)
+}
+
+func (h *transferhdl) ReverseTransfer(c *gin.Context) {
+	var req domains.ReverseTransferRequest
+	if err := bindJSON(c, &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	reversal, err := h.transfersvc.ReverseTransfer(c, c.Param("id"), claims(c).ID, req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, reversal)
+}
+
+func (h *transferhdl) QuoteTransfer(c *gin.Context) {
+	var req domains.QuoteTransferRequest
+	if err := bindJSON(c, &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	caller := claims(c)
+	if caller.ID != req.FromUserID && caller.Role != domains.RoleAdmin {
+		_ = c.Error(domains.Forbidden("cannot quote transfers from another user's account"))
+		return
+	}
+
+	quote, err := h.transfersvc.QuoteTransfer(c, req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
+}
+
+func (h *transferhdl) CreateBatch(c *gin.Context) {
+	var req domains.BatchTransferRequest
+	if err := bindJSON(c, &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	caller := claims(c)
+	if caller.ID != req.FromUserID && caller.Role != domains.RoleAdmin {
+		_ = c.Error(domains.Forbidden("cannot send a batch from another user's account"))
+		return
+	}
+
+	batch, err := h.transfersvc.CreateBatch(c, req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	status := http.StatusCreated
+	if batch.Status != domains.BatchCompleted {
+		status = http.StatusMultiStatus
+	}
+	c.JSON(status, batch)
+}
+
+func (h *transferhdl) GetBatch(c *gin.Context) {
+	batch, err := h.transfersvc.GetBatch(c, c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if claims(c).ID != batch.FromUserID.Hex() && !isStaff(c) {
+		_ = c.Error(domains.NotFound("batch"))
+		return
+	}
+
+	c.JSON(http.StatusOK, batch)
+}
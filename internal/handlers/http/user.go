@@ -1,23 +1,30 @@
 package handlers
 
 import (
-	"log"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
 	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports"
+	"github.com/wansanjou/backend-exercise-user-api/logging"
 	"github.com/wansanjou/backend-exercise-user-api/middleware"
 )
 
 type userhdl struct {
-	usersvc ports.UserService
+	usersvc     ports.UserService
+	lookups     *middleware.Limiter
+	attrWriters domains.AttributeWriters
 }
 
-func NewUserHandler(usersvc ports.UserService) *userhdl {
+// NewUserHandler returns the user handler. Transfers to an alias count against lookups,
+// the limiter shared with the recipient lookup, and custom attributes may only be written
+// by the roles attrWriters allows.
+func NewUserHandler(usersvc ports.UserService, lookups *middleware.Limiter, attrWriters domains.AttributeWriters) *userhdl {
 	return &userhdl{
-		usersvc: usersvc,
+		usersvc:     usersvc,
+		lookups:     lookups,
+		attrWriters: attrWriters,
 	}
 }
 
@@ -29,73 +36,275 @@ func (h *userhdl) UserRoutes(rg *gin.RouterGroup) {
 	protectedUsers := rg.Group("/users")
 	protectedUsers.Use(middleware.AuthenMiddleware())
 	protectedUsers.GET("/", h.GetUsers)
+	protectedUsers.GET("/me", h.GetMe)
 	protectedUsers.GET("/:id", h.GetUserByID)
+	protectedUsers.GET("/:id/balances", h.GetUserBalances)
+	protectedUsers.PATCH("/me", h.UpdateMe)
+	protectedUsers.PATCH("/:id", h.UpdateUser)
+	protectedUsers.DELETE("/:id", h.DeleteUser)
+	protectedUsers.PUT("/:id/aliases", h.UpdateAliases)
 	protectedUsers.POST("/transfer", h.TransferUser)
+
+	staffUsers := protectedUsers.Group("")
+	staffUsers.Use(middleware.RequireRole(domains.RoleAdmin, domains.RoleSupport))
+	staffUsers.POST("/:id/verify", h.VerifyAliases)
+	staffUsers.GET("/export", h.ExportUsers)
+
+	adminUsers := protectedUsers.Group("")
+	adminUsers.Use(middleware.RequireRole(domains.RoleAdmin))
+	adminUsers.POST("/:id/restore", h.RestoreUser)
+	adminUsers.POST("/import", h.ImportUsers)
+	adminUsers.POST("/:id/status", h.ChangeStatus)
+	adminUsers.GET("/:id/status-history", h.StatusHistory)
 }
 
 func (h *userhdl) CreateUser(c *gin.Context) {
-	var req domains.User
+	var req domains.CreateUserRequest
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := bindJSON(c, &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if err := h.attrWriters.CheckWrite(domains.RoleUser, req.Attributes); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
 	user, err := h.usersvc.CreateUser(c, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Name":      user.Name,
-		"Email":     user.Email,
-		"CreatedAt": user.CreatedAt,
-	})
+	c.JSON(http.StatusOK, user.View().Redact(domains.VisibilityOwner))
 }
 
 func (h *userhdl) GetUserByID(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "id is required"})
+		_ = c.Error(domains.Invalid("id", "id is required"))
 		return
 	}
 
-	user, err := h.usersvc.GetUserByID(c, id)
+	h.getAccount(c, id)
+}
+
+// GetMe shows the caller their own account, including balances and limits.
+func (h *userhdl) GetMe(c *gin.Context) {
+	h.getAccount(c, claims(c).ID)
+}
+
+func (h *userhdl) getAccount(c *gin.Context, id string) {
+	account, err := h.usersvc.GetAccount(c, id, domains.VisibilityFor(claims(c), id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Name":      user.Name,
-		"Email":     user.Email,
-		"CreatedAt": user.CreatedAt,
-	})
+	c.Header("ETag", etag(account.Version))
+	c.JSON(http.StatusOK, account)
+}
+
+// UpdateMe changes the caller's own profile.
+func (h *userhdl) UpdateMe(c *gin.Context) {
+	h.updateUser(c, claims(c).ID)
+}
+
+func (h *userhdl) UpdateUser(c *gin.Context) {
+	if claims(c).ID != c.Param("id") && claims(c).Role != domains.RoleAdmin {
+		_ = c.Error(domains.Forbidden("cannot change another user's profile"))
+		return
+	}
+	h.updateUser(c, c.Param("id"))
+}
+
+// updateUser applies a profile change. With If-Match it only does so if the user is still
+// at that ETag, answering 412 otherwise.
+func (h *userhdl) updateUser(c *gin.Context, id string) {
+	version, err := ifMatch(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	var req domains.UpdateUserRequest
+	if err := bindJSON(c, &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if err := h.attrWriters.CheckWrite(claims(c).Role, req.Attributes); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	user, err := h.usersvc.UpdateUser(c, id, req, version)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("ETag", etag(user.Version))
+	c.JSON(http.StatusOK, userView(c, user))
+}
+
+// DeleteUser soft-deletes an account. Users may delete their own; admins may delete anyone's.
+func (h *userhdl) DeleteUser(c *gin.Context) {
+	if claims(c).ID != c.Param("id") && claims(c).Role != domains.RoleAdmin {
+		_ = c.Error(domains.Forbidden("cannot delete another user's account"))
+		return
+	}
+
+	if _, err := h.usersvc.DeleteUser(c, c.Param("id")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *userhdl) RestoreUser(c *gin.Context) {
+	user, err := h.usersvc.RestoreUser(c, c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, userView(c, user))
+}
+
+// ChangeStatus moves an account to another status. The admin making the change is recorded
+// with their reason.
+func (h *userhdl) ChangeStatus(c *gin.Context) {
+	var req domains.ChangeStatusRequest
+	if err := bindJSON(c, &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	user, err := h.usersvc.ChangeStatus(c, c.Param("id"), claims(c).ID, req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("ETag", etag(user.Version))
+	c.JSON(http.StatusOK, userView(c, user))
+}
+
+func (h *userhdl) StatusHistory(c *gin.Context) {
+	changes, err := h.usersvc.StatusHistory(c, c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
+
+// userView shows u with only the fields the caller may see.
+func userView(c *gin.Context, u *domains.User) domains.UserView {
+	return u.View().Redact(domains.VisibilityFor(claims(c), u.ID.Hex()))
+}
+
+// GetUserBalances lists a user's available balance in every currency they hold.
+func (h *userhdl) GetUserBalances(c *gin.Context) {
+	if claims(c).ID != c.Param("id") && !isStaff(c) {
+		_ = c.Error(domains.NotFound("user"))
+		return
+	}
+
+	user, err := h.usersvc.GetUserByID(c, c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"userId": user.ID, "balances": user.Balances()})
+}
+
+func (h *userhdl) UpdateAliases(c *gin.Context) {
+	if claims(c).ID != c.Param("id") {
+		_ = c.Error(domains.Forbidden("cannot change another user's aliases"))
+		return
+	}
+
+	var req domains.UpdateAliasesRequest
+	if err := bindJSON(c, &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	user, err := h.usersvc.UpdateAliases(c, c.Param("id"), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, aliasesResponse(user))
+}
+
+func (h *userhdl) VerifyAliases(c *gin.Context) {
+	var req domains.VerifyAliasesRequest
+	if err := bindJSON(c, &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	user, err := h.usersvc.VerifyAliases(c, c.Param("id"), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, aliasesResponse(user))
+}
+
+func aliasesResponse(u *domains.User) gin.H {
+	return gin.H{
+		"email":         u.Email,
+		"emailVerified": u.EmailVerified,
+		"handle":        u.Handle,
+		"phone":         u.Phone,
+		"phoneVerified": u.PhoneVerified,
+	}
 }
 
 func (h *userhdl) GetUsers(c *gin.Context) {
 	var req domains.FindAllUsers
 
 	if err := c.ShouldBindQuery(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(domains.Invalid("query", "invalid query parameters"))
 		return
 	}
+	req.Attr = c.QueryMap("attr")
+	if !isStaff(c) {
+		if req.Status != "" || req.MinBalance != nil || req.MaxBalance != nil || len(req.Attr) > 0 {
+			_ = c.Error(domains.Forbidden("only staff may filter users by status, balance or attributes"))
+			return
+		}
+		// Emails are not public, so they cannot be looked for either.
+		if req.Email != "" || strings.TrimPrefix(req.Sort, "-") == "email" {
+			_ = c.Error(domains.Forbidden("only staff may filter or sort users by email"))
+			return
+		}
+		req.IncludeDeleted = false
+	}
 
 	users, err := h.usersvc.GetUsers(c, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
-	var res []gin.H
-	for _, user := range users {
-		res = append(res, gin.H{
-			"Name":      user.Name,
-			"Email":     user.Email,
-			"CreatedAt": user.CreatedAt,
-		})
+	res := page{Total: users.Total}
+	views := make([]domains.UserView, 0, len(users.Users))
+	for i := range users.Users {
+		views = append(views, userView(c, &users.Users[i]))
+	}
+	res.Data = views
+	if users.NextCursor != "" {
+		res.NextCursor = &users.NextCursor
 	}
+	setPageLinks(c, users.NextCursor)
 
 	c.JSON(http.StatusOK, res)
 }
@@ -103,33 +312,36 @@ func (h *userhdl) GetUsers(c *gin.Context) {
 func (h *userhdl) TransferUser(c *gin.Context) {
 	var req domains.TransferRequest
 	// เปลี่ยนจาก ShouldBindQuery เป็น ShouldBindJSON
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format: " + err.Error()})
+	if err := bindJSON(c, &req); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	// เพิ่ม logging เพื่อ debug
-	log.Printf("Transfer request: %+v", req)
+	logging.FromContext(c).Debug("transfer request", "request", req)
 
-	err := h.usersvc.TransferBalance(c, req.FromUserID, req.ToUserID, req.Amount)
-	if err != nil {
-		// เพิ่ม logging error เพื่อ debug
-		log.Printf("Transfer error: %v", err)
-
-		// แยก error types เพื่อ return status code ที่เหมาะสม
-		if strings.Contains(err.Error(), "insufficient balance") ||
-			strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Transfer failed: " + err.Error()})
+	if claims(c).ID != req.FromUserID && !isStaff(c) {
+		_ = c.Error(domains.Forbidden("cannot transfer from another user's account"))
+		return
+	}
+
+	if req.ToUserID == "" && req.To != "" {
+		if err := h.lookups.Allow(c, claims(c).ID); err != nil {
+			_ = c.Error(err)
+			return
 		}
+	}
+
+	transfer, err := h.usersvc.TransferBalance(c, req)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Transfer completed successfully",
-		"from":    req.FromUserID,
-		"to":      req.ToUserID,
-		"amount":  req.Amount,
+		"message":    "Transfer completed successfully",
+		"transferId": transfer.ID.Hex(),
+		"from":       req.FromUserID,
+		"to":         transfer.ToUserID.Hex(),
+		"amount":     req.Amount,
 	})
 }
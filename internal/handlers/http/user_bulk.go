@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+)
+
+var bulkContentTypes = map[string]string{
+	domains.BulkCSV:    "text/csv; charset=utf-8",
+	domains.BulkNDJSON: "application/x-ndjson",
+}
+
+// ImportUsers creates users from a CSV or NDJSON body, read as it arrives. The format is
+// ?format= or else taken from the Content-Type. With ?dryRun=true nothing is created.
+func (h *userhdl) ImportUsers(c *gin.Context) {
+	format := c.Query("format")
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(c.ContentType())
+		for f, ct := range bulkContentTypes {
+			if t, _, _ := mime.ParseMediaType(ct); t == mediaType {
+				format = f
+			}
+		}
+	}
+	if c.Request.Body == nil {
+		_ = c.Error(domains.Invalid("body", "request body is required"))
+		return
+	}
+
+	report, err := h.usersvc.ImportUsers(c, format, c.Request.Body, c.Query("dryRun") == "true")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ExportUsers streams every user matching the GET /users filters as CSV or NDJSON, with the
+// fields listed in ?fields=.
+func (h *userhdl) ExportUsers(c *gin.Context) {
+	var req domains.FindAllUsers
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(domains.Invalid("query", "invalid query parameters"))
+		return
+	}
+	req.Attr = c.QueryMap("attr")
+	format := c.DefaultQuery("format", domains.BulkCSV)
+	if !domains.ValidBulkFormat(format) {
+		_ = c.Error(domains.Invalid("format", "format must be csv or ndjson"))
+		return
+	}
+	fields, err := domains.ParseUserExportFields(c.Query("fields"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("Content-Type", bulkContentTypes[format])
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="users-%s.%s"`,
+		time.Now().UTC().Format("20060102"), format))
+	c.Status(http.StatusOK)
+	if _, err := h.usersvc.ExportUsers(c, req, format, fields, c.Writer); err != nil {
+		failDownload(c, err)
+	}
+}
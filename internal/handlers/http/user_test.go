@@ -0,0 +1,78 @@
+package handlers_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports/mocks"
+	handlers "github.com/wansanjou/backend-exercise-user-api/internal/handlers/http"
+	"github.com/wansanjou/backend-exercise-user-api/logging"
+	"github.com/wansanjou/backend-exercise-user-api/middleware"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// signedIn stands in for AuthenMiddleware, signing every request in as id with role.
+func signedIn(id, role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("user", &domains.JWTClaims{ID: id, Role: role})
+		c.Next()
+	}
+}
+
+func TestTransferUser_FromAnotherAccount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := mocks.NewUserService(t)
+	h := handlers.NewUserHandler(svc, handlers.NewLookupLimiter(0), nil)
+	from, caller := primitive.NewObjectID().Hex(), primitive.NewObjectID().Hex()
+	body := `{"fromUserId": "` + from + `", "toUserId": "` + primitive.NewObjectID().Hex() + `", "amount": 10}`
+	send := func(role string) int {
+		r := gin.New()
+		r.Use(middleware.ErrorHandler(), signedIn(caller, role))
+		r.POST("/transfer", h.TransferUser)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/transfer", strings.NewReader(body)))
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusForbidden, send(domains.RoleUser), "TransferBalance must not be called")
+
+	svc.On("TransferBalance", mock.Anything, mock.Anything).Return(&domains.Transfer{}, nil).Once()
+	assert.Equal(t, http.StatusOK, send(domains.RoleSupport), "staff may move money on a user's behalf")
+}
+
+func TestTransferUser_LogsNoSecrets(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, logging.Options{Level: "debug", Format: "text", RedactKeys: []string{"amount"}})
+	assert.NoError(t, err)
+	prev := slog.Default()
+	slog.SetDefault(logger)
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	gin.SetMode(gin.TestMode)
+	svc := mocks.NewUserService(t)
+	h := handlers.NewUserHandler(svc, handlers.NewLookupLimiter(0), nil)
+	from := primitive.NewObjectID().Hex()
+	r := gin.New()
+	r.Use(middleware.LoggingMiddleware(), middleware.ErrorHandler(), signedIn(from, domains.RoleUser))
+	r.POST("/transfer", h.TransferUser)
+
+	svc.On("TransferBalance", mock.Anything, mock.Anything).Return(nil, errors.New("lookup of somchai@example.com failed")).Once()
+	body := `{"fromUserId": "` + from + `", "to": "somchai@example.com", "amount": 1234.56}`
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/transfer", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	out := buf.String()
+	assert.NotContains(t, out, "somchai@example.com")
+	assert.NotContains(t, out, "1234.56")
+	assert.Contains(t, out, "transfer request")
+	assert.Contains(t, out, "unexpected error")
+}
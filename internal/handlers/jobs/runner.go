@@ -0,0 +1,52 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/wansanjou/backend-exercise-user-api/logging"
+)
+
+// Job is a unit of background work that the Runner calls every Interval.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+type Runner struct {
+	jobs []Job
+}
+
+func NewRunner(jobs ...Job) *Runner {
+	return &Runner{jobs: jobs}
+}
+
+// Start runs each job on its own ticker until ctx is cancelled. A job that is still
+// running when its next tick fires is not started again until it returns. Jobs get a
+// logger that names them in ctx.
+func (r *Runner) Start(ctx context.Context, wg *sync.WaitGroup) {
+	for _, job := range r.jobs {
+		wg.Add(1)
+		go func(job Job) {
+			defer wg.Done()
+			ticker := time.NewTicker(job.Interval)
+			defer ticker.Stop()
+			logger := logging.FromContext(ctx).With("job", job.Name)
+			ctx := logging.WithLogger(ctx, logger)
+
+			for {
+				select {
+				case <-ctx.Done():
+					logger.Info("stopping background job")
+					return
+				case <-ticker.C:
+					if err := job.Run(ctx); err != nil {
+						logger.Error("job failed", "error", err)
+					}
+				}
+			}
+		}(job)
+	}
+}
@@ -0,0 +1,372 @@
+package repositories
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// piiFields are the user fields kept encrypted at rest, by path.
+var piiFields = []string{"name", "email", "deleted_email", "phone", "profile.address", "profile.date_of_birth"}
+
+// blindIndexes are the encrypted fields users are looked up by, and the field each one's
+// blind index is kept in. An email keeps its index when it is set aside on deletion.
+var blindIndexes = map[string]struct{ field, kind string }{
+	"name":          {"name_bidx", "name"},
+	"email":         {"email_bidx", "email"},
+	"deleted_email": {"deleted_email_bidx", "email"},
+	"phone":         {"phone_bidx", "phone"},
+}
+
+// searchKeys are the plaintext search keys of encrypted fields, which are not stored while
+// the fields are encrypted.
+var searchKeys = map[string]string{"name": "name_key", "email": "email_key"}
+
+func isSearchKey(key string) bool {
+	for _, k := range searchKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+var tUser = reflect.TypeOf(domains.User{})
+
+// PIICipher keeps the personal fields of users encrypted. It seals values with envelope
+// encryption: each value gets a fresh data key, stored wrapped next to the ciphertext
+// together with the ID of the key that wrapped it:
+//
+//	{kid: "2026-10", dek: <wrapped data key>, ct: <nonce, then AES-GCM ciphertext>}
+//
+// A nil *PIICipher stores them in plaintext.
+type PIICipher struct {
+	keys ports.KeyProvider
+}
+
+// NewPIICipher returns a cipher using keys, or nil if keys is nil.
+func NewPIICipher(keys ports.KeyProvider) *PIICipher {
+	if keys == nil {
+		return nil
+	}
+	return &PIICipher{keys: keys}
+}
+
+// Registry returns the registry the Mongo client must be created with for the user
+// repository to use c: it seals users as they are written and opens them as they are read,
+// so repositories work with plaintext users either way. Documents written before
+// encryption was turned on are read as they are until the re-encryption job gets to them.
+// A nil cipher needs no registry and gets nil.
+func (c *PIICipher) Registry() *bsoncodec.Registry {
+	if c == nil {
+		return nil
+	}
+	reg := bson.NewRegistry()
+	reg.RegisterTypeEncoder(tUser, userCodec{c})
+	reg.RegisterTypeDecoder(tUser, userCodec{c})
+	return reg
+}
+
+func (f *PIICipher) seal(v interface{}) (bson.D, error) {
+	plain, err := bson.Marshal(bson.D{{Key: "v", Value: v}})
+	if err != nil {
+		return nil, err
+	}
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plain)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	kid := f.keys.ActiveKeyID()
+	wrapped, err := f.keys.WrapKey(kid, dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return bson.D{
+		{Key: "kid", Value: kid},
+		{Key: "dek", Value: wrapped},
+		{Key: "ct", Value: aead.Seal(nonce, nonce, plain, nil)},
+	}, nil
+}
+
+func (f *PIICipher) open(s bson.D) (interface{}, error) {
+	kid, _ := lookup(s, "kid")
+	dek, _ := lookup(s, "dek")
+	ct, _ := lookup(s, "ct")
+	keyID, _ := kid.(string)
+	wrapped, _ := dek.(primitive.Binary)
+	data, _ := ct.(primitive.Binary)
+
+	dataKey, err := f.keys.UnwrapKey(keyID, wrapped.Data)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(data.Data) < aead.NonceSize() {
+		return nil, errors.New("sealed value is too short")
+	}
+	n := aead.NonceSize()
+	plain, err := aead.Open(nil, data.Data[:n], data.Data[n:], nil)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.D
+	if err := bson.Unmarshal(plain, &doc); err != nil {
+		return nil, err
+	}
+	v, _ := lookup(doc, "v")
+	return v, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// blindIndex hashes value the way it is looked up: emails ignoring case, and names ignoring
+// case and surrounding space.
+func (f *PIICipher) blindIndex(kind, value string) string {
+	switch kind {
+	case "email":
+		value = domains.EmailIdentity(value)
+	case "name":
+		value = domains.SearchKey(value)
+	}
+	return f.keys.BlindIndex(kind, value)
+}
+
+// asSealed returns v as a sealed value, if it is one.
+func asSealed(v interface{}) (bson.D, bool) {
+	d, ok := v.(bson.D)
+	if !ok {
+		return nil, false
+	}
+	_, hasKID := lookup(d, "kid")
+	_, hasCT := lookup(d, "ct")
+	return d, hasKID && hasCT
+}
+
+// sealUser encrypts the personal fields of a user document and adds their blind indexes.
+// The plaintext search keys are dropped.
+func (f *PIICipher) sealUser(doc bson.D) (bson.D, error) {
+	for _, path := range piiFields {
+		v, ok := lookup(doc, path)
+		if _, sealed := asSealed(v); !ok || sealed || v == nil || v == "" {
+			continue
+		}
+		s, err := f.seal(v)
+		if err != nil {
+			return nil, err
+		}
+		doc = put(doc, path, s)
+		if bi, ok := blindIndexes[path]; ok {
+			str, _ := v.(string)
+			doc = put(doc, bi.field, f.blindIndex(bi.kind, str))
+		}
+	}
+	return slices.DeleteFunc(doc, func(e bson.E) bool { return isSearchKey(e.Key) }), nil
+}
+
+// openUser decrypts the sealed fields of a user document. Fields still in plaintext are
+// left as they are.
+func (f *PIICipher) openUser(doc bson.D) (bson.D, error) {
+	for _, path := range piiFields {
+		v, _ := lookup(doc, path)
+		s, sealed := asSealed(v)
+		if !sealed {
+			continue
+		}
+		plain, err := f.open(s)
+		if err != nil {
+			return nil, err
+		}
+		doc = put(doc, path, plain)
+	}
+	return doc, nil
+}
+
+// sealChanges encrypts the personal fields of an update's $set and keeps their blind
+// indexes in step, setting or unsetting them along with the field. The plaintext search key
+// of a new name or email is unset.
+func (f *PIICipher) sealChanges(set, unset bson.D) (bson.D, bson.D, error) {
+	out := make(bson.D, 0, len(set))
+	for _, e := range set {
+		if isSearchKey(e.Key) {
+			continue
+		}
+		if !slices.Contains(piiFields, e.Key) {
+			out = append(out, e)
+			continue
+		}
+		s, err := f.seal(e.Value)
+		if err != nil {
+			return nil, nil, err
+		}
+		out = append(out, bson.E{Key: e.Key, Value: s})
+		if bi, ok := blindIndexes[e.Key]; ok {
+			str, _ := e.Value.(string)
+			out = append(out, bson.E{Key: bi.field, Value: f.blindIndex(bi.kind, str)})
+		}
+		if key, ok := searchKeys[e.Key]; ok {
+			unset = append(unset, bson.E{Key: key, Value: ""})
+		}
+	}
+	for _, e := range unset {
+		if bi, ok := blindIndexes[e.Key]; ok {
+			unset = append(unset, bson.E{Key: bi.field, Value: ""})
+		}
+	}
+	return out, unset, nil
+}
+
+// staleUsers matches users with a personal field that is in plaintext or sealed under a
+// key other than the active one.
+func (f *PIICipher) staleUsers() bson.D {
+	or := bson.A{}
+	for _, path := range piiFields {
+		or = append(or, bson.D{
+			{Key: path, Value: bson.D{{Key: "$nin", Value: bson.A{nil, ""}}}},
+			{Key: path + ".kid", Value: bson.D{{Key: "$ne", Value: f.keys.ActiveKeyID()}}},
+		})
+	}
+	return bson.D{{Key: "$or", Value: or}}
+}
+
+// reseal returns the $set and $unset that bring a user document's personal fields under the
+// active key, opening any sealed under an older one.
+func (f *PIICipher) reseal(doc bson.D) (set, unset bson.D, err error) {
+	set = bson.D{}
+	for _, path := range piiFields {
+		v, ok := lookup(doc, path)
+		if !ok || v == nil || v == "" {
+			continue
+		}
+		if s, sealed := asSealed(v); sealed {
+			if kid, _ := lookup(s, "kid"); kid == f.keys.ActiveKeyID() {
+				continue
+			}
+			if v, err = f.open(s); err != nil {
+				return nil, nil, err
+			}
+		}
+		set = append(set, bson.E{Key: path, Value: v})
+	}
+	return f.sealChanges(set, nil)
+}
+
+// lookup returns the value at a dotted path of doc.
+func lookup(doc bson.D, path string) (interface{}, bool) {
+	key, rest, nested := strings.Cut(path, ".")
+	for _, e := range doc {
+		if e.Key != key {
+			continue
+		}
+		if !nested {
+			return e.Value, true
+		}
+		sub, ok := e.Value.(bson.D)
+		if !ok {
+			return nil, false
+		}
+		return lookup(sub, rest)
+	}
+	return nil, false
+}
+
+// put sets the value at a dotted path of doc, whose parents must already exist.
+func put(doc bson.D, path string, v interface{}) bson.D {
+	key, rest, nested := strings.Cut(path, ".")
+	for i, e := range doc {
+		if e.Key != key {
+			continue
+		}
+		if nested {
+			if sub, ok := e.Value.(bson.D); ok {
+				doc[i].Value = put(sub, rest, v)
+			}
+			return doc
+		}
+		doc[i].Value = v
+		return doc
+	}
+	if nested {
+		return doc
+	}
+	return append(doc, bson.E{Key: key, Value: v})
+}
+
+// userCodec writes users with their personal fields sealed and reads them back opened.
+type userCodec struct {
+	f *PIICipher
+}
+
+func (c userCodec) EncodeValue(_ bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+	if !val.IsValid() || val.Type() != tUser {
+		return bsoncodec.ValueEncoderError{Name: "userCodec.EncodeValue", Types: []reflect.Type{tUser}, Received: val}
+	}
+	raw, err := bson.Marshal(val.Interface())
+	if err != nil {
+		return err
+	}
+	var doc bson.D
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return err
+	}
+	if doc, err = c.f.sealUser(doc); err != nil {
+		return err
+	}
+	if raw, err = bson.Marshal(doc); err != nil {
+		return err
+	}
+	return bsonrw.Copier{}.CopyDocumentFromBytes(vw, raw)
+}
+
+func (c userCodec) DecodeValue(_ bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	if !val.CanSet() || val.Type() != tUser {
+		return bsoncodec.ValueDecoderError{Name: "userCodec.DecodeValue", Types: []reflect.Type{tUser}, Received: val}
+	}
+	raw, err := bsonrw.Copier{}.CopyDocumentToBytes(vr)
+	if err != nil {
+		return err
+	}
+	var doc bson.D
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return err
+	}
+	if doc, err = c.f.openUser(doc); err != nil {
+		return err
+	}
+	if raw, err = bson.Marshal(doc); err != nil {
+		return err
+	}
+	var u domains.User
+	if err := bson.Unmarshal(raw, &u); err != nil {
+		return err
+	}
+	val.Set(reflect.ValueOf(u))
+	return nil
+}
@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type fxRepository struct {
+	mc *mongo.Client
+	db string
+}
+
+func NewFXRepository(mc *mongo.Client, db string) ports.FXRepository {
+	_, err := mc.Database(db).Collection(fxRatesCollection).Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "base", Value: 1}, {Key: "quote", Value: 1}, {Key: "valid_from", Value: -1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		panic(err)
+	}
+	return &fxRepository{mc, db}
+}
+
+// UpsertRates stores rates keyed by pair and start time, so loading the same file twice
+// corrects rates instead of duplicating them. It returns how many rates were new.
+func (f *fxRepository) UpsertRates(ctx context.Context, rates []domains.FXRate) (int, error) {
+	if len(rates) == 0 {
+		return 0, nil
+	}
+	now := time.Now().UTC()
+	models := make([]mongo.WriteModel, 0, len(rates))
+	for _, r := range rates {
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.D{{Key: "base", Value: r.Base}, {Key: "quote", Value: r.Quote}, {Key: "valid_from", Value: r.ValidFrom}}).
+			SetUpdate(bson.D{
+				{Key: "$set", Value: bson.D{
+					{Key: "rate", Value: r.Rate},
+					{Key: "valid_until", Value: r.ValidUntil},
+					{Key: "source", Value: r.Source},
+				}},
+				{Key: "$setOnInsert", Value: bson.D{{Key: "created_at", Value: now}}},
+			}).
+			SetUpsert(true))
+	}
+	res, err := f.mc.Database(f.db).Collection(fxRatesCollection).BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, err
+	}
+	return int(res.UpsertedCount), nil
+}
+
+// RateAt returns the rate for base to quote in effect at t, reading the reverse pair when
+// only that one is published.
+func (f *fxRepository) RateAt(ctx context.Context, base, quote string, t time.Time) (*domains.FXRate, error) {
+	rate, err := f.rateAt(ctx, base, quote, t)
+	if err != nil || rate != nil {
+		return rate, err
+	}
+	rate, err = f.rateAt(ctx, quote, base, t)
+	if err != nil || rate == nil {
+		return nil, err
+	}
+	inv := rate.Invert()
+	return &inv, nil
+}
+
+func (f *fxRepository) rateAt(ctx context.Context, base, quote string, t time.Time) (*domains.FXRate, error) {
+	filter := append(bson.D{{Key: "base", Value: base}, {Key: "quote", Value: quote}}, effectiveAt(t)...)
+	opts := options.FindOne().SetSort(bson.D{{Key: "valid_from", Value: -1}})
+
+	var rate domains.FXRate
+	if err := f.mc.Database(f.db).Collection(fxRatesCollection).FindOne(ctx, filter, opts).Decode(&rate); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// ListRates returns the rate in effect at the given time for every published pair.
+func (f *fxRepository) ListRates(ctx context.Context, at time.Time) ([]domains.FXRate, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: effectiveAt(at)}},
+		{{Key: "$sort", Value: bson.D{{Key: "valid_from", Value: -1}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "base", Value: "$base"}, {Key: "quote", Value: "$quote"}}},
+			{Key: "rate", Value: bson.D{{Key: "$first", Value: "$$ROOT"}}},
+		}}},
+		{{Key: "$replaceWith", Value: "$rate"}},
+		{{Key: "$sort", Value: bson.D{{Key: "base", Value: 1}, {Key: "quote", Value: 1}}}},
+	}
+	cursor, err := f.mc.Database(f.db).Collection(fxRatesCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	rates := []domains.FXRate{}
+	if err := cursor.All(ctx, &rates); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+func (f *fxRepository) CreateQuote(ctx context.Context, in domains.FXQuote) (*domains.FXQuote, error) {
+	result, err := f.mc.Database(f.db).Collection(fxQuotesCollection).InsertOne(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	in.ID, _ = result.InsertedID.(primitive.ObjectID)
+	return &in, nil
+}
+
+func (f *fxRepository) GetQuote(ctx context.Context, id primitive.ObjectID) (*domains.FXQuote, error) {
+	var quote domains.FXQuote
+	if err := f.mc.Database(f.db).Collection(fxQuotesCollection).FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&quote); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &quote, nil
+}
+
+// effectiveAt matches rates that have started by t and not yet run out.
+func effectiveAt(t time.Time) bson.D {
+	return bson.D{
+		{Key: "valid_from", Value: bson.D{{Key: "$lte", Value: t}}},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "valid_until", Value: nil}},
+			bson.D{{Key: "valid_until", Value: bson.D{{Key: "$gt", Value: t}}}},
+		}},
+	}
+}
@@ -0,0 +1,222 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type holdRepository struct {
+	mc  *mongo.Client
+	db  string
+	col string
+}
+
+func NewHoldRepository(mc *mongo.Client, db string) ports.HoldRepository {
+	col := holdsCollection
+	_, err := mc.Database(db).Collection(col).Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "expires_at", Value: 1}}},
+		// Active holds count towards their payer's transfer limits.
+		{Keys: bson.D{{Key: "from_user_id", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return &holdRepository{mc, db, col}
+}
+
+func (h *holdRepository) Create(ctx context.Context, in domains.Hold, limits domains.TransferLimits) (*domains.Hold, error) {
+	db := h.mc.Database(h.db)
+	now := time.Now().UTC()
+	in.Status = domains.HoldActive
+	in.CreatedAt = now
+	in.UpdatedAt = now
+
+	err := withTransaction(ctx, h.mc, func(sc mongo.SessionContext) error {
+		if err := reserve(sc, db, in.FromUserID, in.Amount); err != nil {
+			return err
+		}
+
+		usage, err := transferUsage(sc, db, in.FromUserID, now)
+		if err != nil {
+			return err
+		}
+		if err := limits.Check(usage, in.Amount, now); err != nil {
+			return err
+		}
+
+		n, err := db.Collection(usersCollection).CountDocuments(sc, bson.D{{Key: "_id", Value: in.ToUserID}})
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return domains.NotFound("recipient")
+		}
+
+		result, err := db.Collection(h.col).InsertOne(sc, in)
+		if err != nil {
+			return err
+		}
+		in.ID, _ = result.InsertedID.(primitive.ObjectID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &in, nil
+}
+
+func (h *holdRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*domains.Hold, error) {
+	return h.findOne(ctx, bson.D{{Key: "_id", Value: id}})
+}
+
+// Capture settles a hold by paying payment.Amount of it to the payee, charging
+// payment.Fee on top. The hold stops counting towards the payer's limits and the payment
+// is checked against them like any other transfer.
+func (h *holdRepository) Capture(ctx context.Context, id primitive.ObjectID, payment domains.Transfer, limits domains.TransferLimits) (*domains.Hold, error) {
+	db := h.mc.Database(h.db)
+
+	var out *domains.Hold
+	err := withTransaction(ctx, h.mc, func(sc mongo.SessionContext) error {
+		now := time.Now().UTC()
+		hold, err := h.findOne(sc, bson.D{{Key: "_id", Value: id}})
+		if err != nil {
+			return err
+		}
+		if hold == nil {
+			return domains.NotFound("hold")
+		}
+		if hold.Status != domains.HoldActive {
+			return domains.Conflict("hold_not_active", "hold is "+hold.Status)
+		}
+		if !hold.ExpiresAt.After(now) {
+			return domains.Conflict("hold_expired", "hold has expired")
+		}
+		if payment.Amount > hold.Amount {
+			return domains.Invalid("amount", "capture amount exceeds held amount")
+		}
+
+		// The whole hold goes back to Balance and the capture is paid out of it, so it
+		// passes through the same checks as any transfer.
+		if err := release(sc, db, hold.FromUserID, hold.Amount, hold.Amount); err != nil {
+			return err
+		}
+		transferID := primitive.NewObjectID()
+		out, err = h.settle(sc, hold.ID, bson.D{
+			{Key: "status", Value: domains.HoldCaptured},
+			{Key: "captured_amount", Value: payment.Amount},
+			{Key: "transfer_id", Value: transferID},
+			{Key: "updated_at", Value: now},
+		})
+		if err != nil {
+			return err
+		}
+		payment.ID = transferID
+		payment.FromUserID, payment.ToUserID = hold.FromUserID, hold.ToUserID
+		payment.HoldID = &hold.ID
+		payment.CreatedAt = now
+		_, err = transfer(sc, db, payment, limits)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (h *holdRepository) Void(ctx context.Context, id primitive.ObjectID) (*domains.Hold, error) {
+	var out *domains.Hold
+	err := withTransaction(ctx, h.mc, func(sc mongo.SessionContext) error {
+		hold, err := h.findOne(sc, bson.D{{Key: "_id", Value: id}})
+		if err != nil {
+			return err
+		}
+		if hold == nil {
+			return domains.NotFound("hold")
+		}
+		if hold.Status != domains.HoldActive {
+			return domains.Conflict("hold_not_active", "hold is "+hold.Status)
+		}
+		out, err = h.releaseHold(sc, *hold, domains.HoldVoided)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (h *holdRepository) ReleaseExpired(ctx context.Context, now time.Time) (int64, error) {
+	var released int64
+	for {
+		hold, err := h.findOne(ctx, bson.D{
+			{Key: "status", Value: domains.HoldActive},
+			{Key: "expires_at", Value: bson.D{{Key: "$lte", Value: now}}},
+		})
+		if err != nil {
+			return released, err
+		}
+		if hold == nil {
+			return released, nil
+		}
+
+		err = withTransaction(ctx, h.mc, func(sc mongo.SessionContext) error {
+			_, err := h.releaseHold(sc, *hold, domains.HoldExpired)
+			return err
+		})
+		if err != nil {
+			return released, err
+		}
+		released++
+	}
+}
+
+// releaseHold returns the full held amount to the payer and closes the hold with status.
+func (h *holdRepository) releaseHold(sc mongo.SessionContext, hold domains.Hold, status string) (*domains.Hold, error) {
+	db := h.mc.Database(h.db)
+	if err := release(sc, db, hold.FromUserID, hold.Amount, hold.Amount); err != nil {
+		return nil, err
+	}
+	return h.settle(sc, hold.ID, bson.D{
+		{Key: "status", Value: status},
+		{Key: "updated_at", Value: time.Now().UTC()},
+	})
+}
+
+// settle moves an active hold to its final state. Matching on the active status makes a
+// concurrent capture/void/expiry of the same hold fail instead of settling it twice.
+func (h *holdRepository) settle(ctx context.Context, id primitive.ObjectID, set bson.D) (*domains.Hold, error) {
+	out := domains.Hold{}
+	col := h.mc.Database(h.db).Collection(h.col)
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := col.FindOneAndUpdate(ctx,
+		bson.D{{Key: "_id", Value: id}, {Key: "status", Value: domains.HoldActive}},
+		bson.D{{Key: "$set", Value: set}},
+		opts,
+	).Decode(&out)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domains.Conflict("hold_not_active", "hold is no longer active")
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (h *holdRepository) findOne(ctx context.Context, filter bson.D) (*domains.Hold, error) {
+	out := domains.Hold{}
+	col := h.mc.Database(h.db).Collection(h.col)
+	if err := col.FindOne(ctx, filter).Decode(&out); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,249 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// errErasureRaced aborts an erasure transaction that lost a race with another write.
+var errErasureRaced = errors.New("erasure raced with another change")
+
+type privacyRepository struct {
+	mc *mongo.Client
+	db string
+}
+
+func NewPrivacyRepository(mc *mongo.Client, db string) ports.PrivacyRepository {
+	// A user has at most one erasure request open at a time.
+	_, err := mc.Database(db).Collection(erasuresCollection).Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true).
+			SetPartialFilterExpression(bson.D{{Key: "status", Value: domains.ErasurePending}}),
+	})
+	if err != nil {
+		panic(err)
+	}
+	return &privacyRepository{mc, db}
+}
+
+// involving matches documents where userID sent or received the money.
+func involving(userID primitive.ObjectID) bson.D {
+	return bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: "from_user_id", Value: userID}},
+		bson.D{{Key: "to_user_id", Value: userID}},
+	}}}
+}
+
+func (p *privacyRepository) StreamTransfers(ctx context.Context, userID primitive.ObjectID, fn func(domains.Transfer) error) error {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).SetBatchSize(500)
+	cursor, err := p.mc.Database(p.db).Collection(transfersCollection).Find(ctx, involving(userID), opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var t domains.Transfer
+		if err := cursor.Decode(&t); err != nil {
+			return err
+		}
+		if err := fn(t); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func (p *privacyRepository) ListHolds(ctx context.Context, userID primitive.ObjectID) ([]domains.Hold, error) {
+	out := []domains.Hold{}
+	return out, p.findAll(ctx, holdsCollection, involving(userID), &out)
+}
+
+func (p *privacyRepository) ListSchedules(ctx context.Context, userID primitive.ObjectID) ([]domains.ScheduledTransfer, error) {
+	out := []domains.ScheduledTransfer{}
+	return out, p.findAll(ctx, schedulesCollection, involving(userID), &out)
+}
+
+func (p *privacyRepository) ListFXQuotes(ctx context.Context, userID primitive.ObjectID) ([]domains.FXQuote, error) {
+	out := []domains.FXQuote{}
+	return out, p.findAll(ctx, fxQuotesCollection, bson.D{{Key: "user_id", Value: userID}}, &out)
+}
+
+func (p *privacyRepository) ListErasureRequests(ctx context.Context, userID primitive.ObjectID) ([]domains.ErasureRequest, error) {
+	out := []domains.ErasureRequest{}
+	return out, p.findAll(ctx, erasuresCollection, bson.D{{Key: "user_id", Value: userID}}, &out)
+}
+
+func (p *privacyRepository) findAll(ctx context.Context, col string, filter bson.D, out interface{}) error {
+	cursor, err := p.mc.Database(p.db).Collection(col).Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	return cursor.All(ctx, out)
+}
+
+func (p *privacyRepository) CreateErasureRequest(ctx context.Context, in domains.ErasureRequest) (*domains.ErasureRequest, error) {
+	in.Status = domains.ErasurePending
+	in.RequestedAt = time.Now().UTC()
+	res, err := p.mc.Database(p.db).Collection(erasuresCollection).InsertOne(ctx, in)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, domains.Conflict("erasure_pending", "an erasure request is already pending")
+	}
+	if err != nil {
+		return nil, err
+	}
+	in.ID = res.InsertedID.(primitive.ObjectID)
+	return &in, nil
+}
+
+func (p *privacyRepository) PendingErasure(ctx context.Context, userID primitive.ObjectID) (*domains.ErasureRequest, error) {
+	var out domains.ErasureRequest
+	err := p.mc.Database(p.db).Collection(erasuresCollection).FindOne(ctx,
+		bson.D{{Key: "user_id", Value: userID}, {Key: "status", Value: domains.ErasurePending}},
+	).Decode(&out)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Erase carries out a pending erasure request in one transaction. The user's name, email,
+// aliases and password are replaced, their profile and custom attributes are dropped, and
+// the account is closed; free text written about them is redacted; their open schedules
+// are cancelled. Transfers keep their amounts,
+// dates and user IDs, which only point at the pseudonymized record from now on. It returns
+// nil if the request is no longer pending, or the user has money left or is already erased.
+func (p *privacyRepository) Erase(ctx context.Context, req domains.ErasureRequest, actorID string) (*domains.ErasureRequest, error) {
+	var out *domains.ErasureRequest
+	err := withTransaction(ctx, p.mc, func(sc mongo.SessionContext) error {
+		out = nil
+		db := p.mc.Database(p.db)
+		now := time.Now().UTC()
+
+		var u domains.User
+		err := db.Collection(usersCollection).FindOne(sc, bson.D{
+			{Key: "_id", Value: req.UserID},
+			notErased,
+			{Key: "balance", Value: 0},
+			{Key: "held_balance", Value: 0},
+		}).Decode(&u)
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// Redact old reasons first, so the closing change below is the only one left readable.
+		if _, err := db.Collection(statusChangesCollection).UpdateMany(sc,
+			bson.D{{Key: "user_id", Value: req.UserID}},
+			bson.D{{Key: "$set", Value: bson.D{{Key: "reason", Value: domains.ErasedText}}}},
+		); err != nil {
+			return err
+		}
+		if from := u.AccountStatus(); from != domains.StatusClosed {
+			closed, err := changeStatus(sc, db, domains.StatusChange{
+				UserID:  req.UserID,
+				From:    from,
+				To:      domains.StatusClosed,
+				Reason:  "personal data erased on request",
+				ActorID: actorID,
+			})
+			if err != nil {
+				return err
+			}
+			if closed == nil {
+				return errErasureRaced
+			}
+		}
+
+		deletedAt := now
+		if u.DeletedAt != nil {
+			deletedAt = *u.DeletedAt
+		}
+		email := domains.ErasedEmail(req.UserID)
+		_, err = db.Collection(usersCollection).UpdateOne(sc, bson.D{{Key: "_id", Value: req.UserID}}, bson.D{
+			{Key: "$set", Value: bson.D{
+				{Key: "name", Value: domains.ErasedName},
+				{Key: "name_key", Value: domains.SearchKey(domains.ErasedName)},
+				{Key: "email", Value: email},
+				{Key: "email_key", Value: domains.SearchKey(email)},
+				{Key: "password", Value: ""},
+				{Key: "status_reason", Value: domains.ErasedText},
+				{Key: "deleted_at", Value: deletedAt},
+				{Key: "erased_at", Value: now},
+			}},
+			{Key: "$unset", Value: bson.D{
+				{Key: "handle", Value: ""},
+				{Key: "phone", Value: ""},
+				{Key: "email_verified", Value: ""},
+				{Key: "phone_verified", Value: ""},
+				{Key: "deleted_email", Value: ""},
+				{Key: "name_bidx", Value: ""},
+				{Key: "email_bidx", Value: ""},
+				{Key: "deleted_email_bidx", Value: ""},
+				{Key: "phone_bidx", Value: ""},
+				{Key: "frozen_reason", Value: ""},
+				{Key: "profile", Value: ""},
+				{Key: "attributes", Value: ""},
+			}},
+			{Key: "$inc", Value: bson.D{bumpVersion}},
+		})
+		if err != nil {
+			return err
+		}
+
+		if _, err := db.Collection(transfersCollection).UpdateMany(sc,
+			append(involving(req.UserID), bson.E{Key: "reason", Value: bson.D{{Key: "$nin", Value: bson.A{"", nil}}}}),
+			bson.D{{Key: "$set", Value: bson.D{{Key: "reason", Value: domains.ErasedText}}}},
+		); err != nil {
+			return err
+		}
+		if _, err := db.Collection(schedulesCollection).UpdateMany(sc,
+			append(involving(req.UserID), bson.E{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{domains.ScheduleActive, domains.SchedulePaused}}}}),
+			bson.D{{Key: "$set", Value: bson.D{
+				{Key: "status", Value: domains.ScheduleCancelled},
+				{Key: "updated_at", Value: now},
+			}}},
+		); err != nil {
+			return err
+		}
+
+		var done domains.ErasureRequest
+		err = db.Collection(erasuresCollection).FindOneAndUpdate(sc,
+			bson.D{{Key: "_id", Value: req.ID}, {Key: "status", Value: domains.ErasurePending}},
+			bson.D{{Key: "$set", Value: bson.D{
+				{Key: "status", Value: domains.ErasureCompleted},
+				{Key: "completed_by", Value: actorID},
+				{Key: "completed_at", Value: now},
+			}}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&done)
+		if err == mongo.ErrNoDocuments {
+			return errErasureRaced
+		}
+		if err != nil {
+			return err
+		}
+		out = &done
+		return nil
+	})
+	if errors.Is(err, errErasureRaced) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,285 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type reconciliationRepository struct {
+	mc  *mongo.Client
+	db  string
+	col string
+}
+
+func NewReconciliationRepository(mc *mongo.Client, db string) ports.ReconciliationRepository {
+	col := reconRunsCollection
+	ensureLedgerIndexes(mc.Database(db))
+	_, err := mc.Database(db).Collection(mismatchesCollection).Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "run_id", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "detected_at", Value: -1}}},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return &reconciliationRepository{mc, db, col}
+}
+
+func (r *reconciliationRepository) CreateRun(ctx context.Context, run domains.ReconciliationRun) (*domains.ReconciliationRun, error) {
+	col := r.mc.Database(r.db).Collection(r.col)
+	result, err := col.InsertOne(ctx, run)
+	if err != nil {
+		return nil, err
+	}
+	run.ID, _ = result.InsertedID.(primitive.ObjectID)
+	return &run, nil
+}
+
+func (r *reconciliationRepository) FinishRun(ctx context.Context, run domains.ReconciliationRun) error {
+	col := r.mc.Database(r.db).Collection(r.col)
+	_, err := col.UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: run.ID}},
+		bson.D{{Key: "$set", Value: bson.D{
+			{Key: "status", Value: run.Status},
+			{Key: "checked", Value: run.Checked},
+			{Key: "mismatched", Value: run.Mismatched},
+			{Key: "frozen", Value: run.Frozen},
+			{Key: "error", Value: run.Error},
+			{Key: "finished_at", Value: run.FinishedAt},
+		}}},
+	)
+	return err
+}
+
+// BalancesAfter reads a page of users and their ledger totals inside one transaction, so
+// the stored balances and the transfers they are compared with come from the same snapshot
+// and a transfer committing mid-read cannot show up as drift.
+func (r *reconciliationRepository) BalancesAfter(ctx context.Context, after primitive.ObjectID, limit int) ([]domains.AccountBalance, error) {
+	db := r.mc.Database(r.db)
+
+	var out []domains.AccountBalance
+	err := withTransaction(ctx, r.mc, func(sc mongo.SessionContext) error {
+		out = nil
+		opts := options.Find().
+			SetSort(bson.D{{Key: "_id", Value: 1}}).
+			SetLimit(int64(limit)).
+			SetProjection(bson.D{
+				{Key: "balance", Value: 1}, {Key: "held_balance", Value: 1}, {Key: "wallets", Value: 1},
+				{Key: "opening", Value: 1}, {Key: "frozen", Value: 1},
+			})
+		cursor, err := db.Collection(usersCollection).Find(sc, bson.D{{Key: "_id", Value: bson.D{{Key: "$gt", Value: after}}}}, opts)
+		if err != nil {
+			return err
+		}
+		var users []domains.User
+		if err := cursor.All(sc, &users); err != nil {
+			return err
+		}
+		if len(users) == 0 {
+			return nil
+		}
+
+		ids := make([]primitive.ObjectID, len(users))
+		for i, u := range users {
+			ids[i] = u.ID
+		}
+		totals, err := ledgerTotals(sc, db, ids)
+		if err != nil {
+			return err
+		}
+
+		for _, u := range users {
+			out = append(out, domains.AccountBalance{
+				UserID:      u.ID,
+				Balances:    u.Balances(),
+				HeldBalance: u.HeldBalance,
+				Frozen:      u.Frozen,
+				Opening:     u.OpeningBalances(),
+				Ledger:      totals[u.ID],
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ensureLedgerIndexes indexes transfers by every account they touch, for reading an account's history.
+func ensureLedgerIndexes(db *mongo.Database) {
+	_, err := db.Collection(transfersCollection).Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "to_user_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "fee_account_id", Value: 1}, {Key: "created_at", Value: 1}}, Options: options.Index().SetSparse(true)},
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// ledgerTotals sums the transfers and open holds of each user in ids, by currency.
+func ledgerTotals(ctx context.Context, db *mongo.Database, ids []primitive.ObjectID) (map[primitive.ObjectID]map[string]domains.LedgerTotals, error) {
+	out, err := transferTotals(ctx, db, ids, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := db.Collection(holdsCollection).Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "from_user_id", Value: bson.D{{Key: "$in", Value: ids}}}, {Key: "status", Value: domains.HoldActive}}}},
+		{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$from_user_id"}, {Key: "amount", Value: bson.D{{Key: "$sum", Value: "$amount"}}}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var held []struct {
+		ID     primitive.ObjectID `bson:"_id"`
+		Amount float64            `bson:"amount"`
+	}
+	if err := cursor.All(ctx, &held); err != nil {
+		return nil, err
+	}
+	for _, r := range held {
+		// Holds are always in the default currency.
+		addTotals(out, r.ID, domains.DefaultCurrency, func(t *domains.LedgerTotals) { t.Held = r.Amount })
+	}
+	return out, nil
+}
+
+type totalRow struct {
+	ID struct {
+		User     primitive.ObjectID `bson:"user"`
+		Currency string             `bson:"currency"`
+	} `bson:"_id"`
+	Amount float64 `bson:"amount"`
+	Fee    float64 `bson:"fee"`
+}
+
+func addTotals(totals map[primitive.ObjectID]map[string]domains.LedgerTotals, id primitive.ObjectID, currency string, fn func(*domains.LedgerTotals)) {
+	if totals[id] == nil {
+		totals[id] = map[string]domains.LedgerTotals{}
+	}
+	t := totals[id][currency]
+	fn(&t)
+	totals[id][currency] = t
+}
+
+// transferTotals sums what each user in ids sent, received and paid or collected in fees,
+// by currency, counting only transfers created before the given time unless it is zero.
+func transferTotals(ctx context.Context, db *mongo.Database, ids []primitive.ObjectID, before time.Time) (map[primitive.ObjectID]map[string]domains.LedgerTotals, error) {
+	in := bson.D{{Key: "$in", Value: ids}}
+	// Transfers from before wallets existed have no currency and were in the default one.
+	source := bson.D{{Key: "$ifNull", Value: bson.A{"$currency", domains.DefaultCurrency}}}
+	dest := bson.D{{Key: "$ifNull", Value: bson.A{"$to_currency", source}}}
+	group := func(key string, currency interface{}, fields ...bson.E) bson.D {
+		id := bson.D{{Key: "user", Value: "$" + key}, {Key: "currency", Value: currency}}
+		return bson.D{{Key: "$group", Value: append(bson.D{{Key: "_id", Value: id}}, fields...)}}
+	}
+	sum := func(name, field string) bson.E {
+		return bson.E{Key: name, Value: bson.D{{Key: "$sum", Value: "$" + field}}}
+	}
+
+	match := bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: "from_user_id", Value: in}},
+		bson.D{{Key: "to_user_id", Value: in}},
+		bson.D{{Key: "fee_account_id", Value: in}},
+	}}}
+	if !before.IsZero() {
+		match = append(match, bson.E{Key: "created_at", Value: bson.D{{Key: "$lt", Value: before}}})
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$facet", Value: bson.D{
+			{Key: "sent", Value: bson.A{
+				bson.D{{Key: "$match", Value: bson.D{{Key: "from_user_id", Value: in}}}},
+				group("from_user_id", source, sum("amount", "amount"), sum("fee", "fee")),
+			}},
+			{Key: "received", Value: bson.A{
+				bson.D{{Key: "$match", Value: bson.D{{Key: "to_user_id", Value: in}}}},
+				group("to_user_id", dest, bson.E{Key: "amount", Value: bson.D{{Key: "$sum", Value: bson.D{
+					{Key: "$ifNull", Value: bson.A{"$to_amount", "$amount"}},
+				}}}}),
+			}},
+			{Key: "collected", Value: bson.A{
+				bson.D{{Key: "$match", Value: bson.D{{Key: "fee_account_id", Value: in}}}},
+				group("fee_account_id", source, sum("fee", "fee")),
+			}},
+		}}},
+	}
+
+	var facets []struct {
+		Sent      []totalRow `bson:"sent"`
+		Received  []totalRow `bson:"received"`
+		Collected []totalRow `bson:"collected"`
+	}
+	cursor, err := db.Collection(transfersCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(ctx, &facets); err != nil {
+		return nil, err
+	}
+
+	out := make(map[primitive.ObjectID]map[string]domains.LedgerTotals, len(ids))
+	if len(facets) > 0 {
+		for _, r := range facets[0].Sent {
+			addTotals(out, r.ID.User, r.ID.Currency, func(t *domains.LedgerTotals) { t.Sent, t.FeesPaid = r.Amount, r.Fee })
+		}
+		for _, r := range facets[0].Received {
+			addTotals(out, r.ID.User, r.ID.Currency, func(t *domains.LedgerTotals) { t.Received = r.Amount })
+		}
+		for _, r := range facets[0].Collected {
+			addTotals(out, r.ID.User, r.ID.Currency, func(t *domains.LedgerTotals) { t.FeesCollected = r.Fee })
+		}
+	}
+	return out, nil
+}
+
+func (r *reconciliationRepository) RecordMismatches(ctx context.Context, ms []domains.BalanceMismatch) error {
+	docs := make([]interface{}, len(ms))
+	for i, m := range ms {
+		docs[i] = m
+	}
+	_, err := r.mc.Database(r.db).Collection(mismatchesCollection).InsertMany(ctx, docs)
+	return err
+}
+
+// Freeze moves the user to frozen, recording the job as the actor, and returns them. It
+// returns nil for users who are missing, already frozen or closed, whose accounts are left
+// as they are.
+func (r *reconciliationRepository) Freeze(ctx context.Context, userID primitive.ObjectID, reason string) (*domains.User, error) {
+	var out *domains.User
+	err := withTransaction(ctx, r.mc, func(sc mongo.SessionContext) error {
+		out = nil
+		db := r.mc.Database(r.db)
+		var u domains.User
+		err := db.Collection(usersCollection).FindOne(sc, bson.D{{Key: "_id", Value: userID}}).Decode(&u)
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		from := u.AccountStatus()
+		if !domains.CanTransition(from, domains.StatusFrozen) {
+			return nil
+		}
+		out, err = changeStatus(sc, db, domains.StatusChange{
+			UserID:  userID,
+			From:    from,
+			To:      domains.StatusFrozen,
+			Reason:  reason,
+			ActorID: domains.ReconciliationActor,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,251 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type scheduleRepository struct {
+	mc  *mongo.Client
+	db  string
+	col string
+}
+
+func NewScheduledTransferRepository(mc *mongo.Client, db string) ports.ScheduledTransferRepository {
+	col := schedulesCollection
+	_, err := mc.Database(db).Collection(col).Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_run_at", Value: 1}}},
+		{Keys: bson.D{{Key: "from_user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		panic(err)
+	}
+	_, err = mc.Database(db).Collection(runsCollection).Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "schedule_id", Value: 1}, {Key: "scheduled_for", Value: 1}, {Key: "attempt", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		panic(err)
+	}
+	return &scheduleRepository{mc, db, col}
+}
+
+func (s *scheduleRepository) Create(ctx context.Context, in domains.ScheduledTransfer) (*domains.ScheduledTransfer, error) {
+	now := time.Now().UTC()
+	in.CreatedAt = now
+	in.UpdatedAt = now
+	col := s.mc.Database(s.db).Collection(s.col)
+	result, err := col.InsertOne(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	in.ID, _ = result.InsertedID.(primitive.ObjectID)
+	return &in, nil
+}
+
+func (s *scheduleRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*domains.ScheduledTransfer, error) {
+	out := domains.ScheduledTransfer{}
+	col := s.mc.Database(s.db).Collection(s.col)
+	if err := col.FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&out); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (s *scheduleRepository) ListByUser(ctx context.Context, userID primitive.ObjectID) ([]domains.ScheduledTransfer, error) {
+	var out []domains.ScheduledTransfer
+	col := s.mc.Database(s.db).Collection(s.col)
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := col.Find(ctx, bson.D{{Key: "from_user_id", Value: userID}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *scheduleRepository) ListRuns(ctx context.Context, scheduleID primitive.ObjectID) ([]domains.ScheduledTransferRun, error) {
+	var out []domains.ScheduledTransferRun
+	col := s.mc.Database(s.db).Collection(runsCollection)
+	opts := options.Find().SetSort(bson.D{{Key: "started_at", Value: -1}}).SetLimit(100)
+	cursor, err := col.Find(ctx, bson.D{{Key: "schedule_id", Value: scheduleID}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *scheduleRepository) SetStatus(ctx context.Context, id primitive.ObjectID, from []string, status string, next *time.Time) (*domains.ScheduledTransfer, error) {
+	set := bson.D{
+		{Key: "status", Value: status},
+		{Key: "occurrence", Value: next},
+		{Key: "next_run_at", Value: next},
+		{Key: "attempt", Value: 0},
+		{Key: "updated_at", Value: time.Now().UTC()},
+	}
+	out := domains.ScheduledTransfer{}
+	col := s.mc.Database(s.db).Collection(s.col)
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := col.FindOneAndUpdate(ctx,
+		bson.D{{Key: "_id", Value: id}, {Key: "status", Value: bson.D{{Key: "$in", Value: from}}}},
+		bson.D{{Key: "$set", Value: set}},
+		opts,
+	).Decode(&out)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domains.Conflict("invalid_status_transition", "scheduled transfer cannot be "+status+" from its current status")
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (s *scheduleRepository) ClaimDue(ctx context.Context, owner string, now time.Time, lease time.Duration) (*domains.ScheduledTransfer, error) {
+	filter := bson.D{
+		{Key: "status", Value: domains.ScheduleActive},
+		{Key: "next_run_at", Value: bson.D{{Key: "$lte", Value: now}}},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "lease_until", Value: nil}},
+			bson.D{{Key: "lease_until", Value: bson.D{{Key: "$lte", Value: now}}}},
+		}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "lease_owner", Value: owner},
+		{Key: "lease_until", Value: now.Add(lease)},
+	}}}
+
+	out := domains.ScheduledTransfer{}
+	col := s.mc.Database(s.db).Collection(s.col)
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_run_at", Value: 1}}).
+		SetReturnDocument(options.After)
+	if err := col.FindOneAndUpdate(ctx, filter, update, opts).Decode(&out); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (s *scheduleRepository) StartRun(ctx context.Context, run domains.ScheduledTransferRun) (*domains.ScheduledTransferRun, bool, error) {
+	col := s.mc.Database(s.db).Collection(runsCollection)
+	result, err := col.InsertOne(ctx, run)
+	if err == nil {
+		run.ID, _ = result.InsertedID.(primitive.ObjectID)
+		return &run, true, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return nil, false, err
+	}
+
+	existing := domains.ScheduledTransferRun{}
+	err = col.FindOne(ctx, bson.D{
+		{Key: "schedule_id", Value: run.ScheduleID},
+		{Key: "scheduled_for", Value: run.ScheduledFor},
+		{Key: "attempt", Value: run.Attempt},
+	}).Decode(&existing)
+	if err != nil {
+		return nil, false, err
+	}
+	return &existing, false, nil
+}
+
+func (s *scheduleRepository) ExecuteRun(ctx context.Context, runID primitive.ObjectID, in domains.Transfer, limits domains.TransferLimits) (*domains.Transfer, error) {
+	db := s.mc.Database(s.db)
+	in.CreatedAt = time.Now().UTC()
+
+	var out *domains.Transfer
+	err := withTransaction(ctx, s.mc, func(sc mongo.SessionContext) error {
+		var err error
+		out, err = transfer(sc, db, in, limits)
+		if err != nil {
+			return err
+		}
+
+		res, err := db.Collection(runsCollection).UpdateOne(sc,
+			bson.D{{Key: "_id", Value: runID}, {Key: "status", Value: domains.RunRunning}},
+			bson.D{{Key: "$set", Value: bson.D{
+				{Key: "status", Value: domains.RunSucceeded},
+				{Key: "transfer_id", Value: out.ID},
+				{Key: "finished_at", Value: time.Now().UTC()},
+			}}},
+		)
+		if err != nil {
+			return err
+		}
+		if res.MatchedCount == 0 {
+			return errors.New("run is no longer running")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *scheduleRepository) FailRun(ctx context.Context, runID primitive.ObjectID, reason string) error {
+	col := s.mc.Database(s.db).Collection(runsCollection)
+	_, err := col.UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: runID}, {Key: "status", Value: domains.RunRunning}},
+		bson.D{{Key: "$set", Value: bson.D{
+			{Key: "status", Value: domains.RunFailed},
+			{Key: "error", Value: reason},
+			{Key: "finished_at", Value: time.Now().UTC()},
+		}}},
+	)
+	return err
+}
+
+func (s *scheduleRepository) Advance(ctx context.Context, prev, next domains.ScheduledTransfer) error {
+	col := s.mc.Database(s.db).Collection(s.col)
+	_, err := col.UpdateOne(ctx,
+		bson.D{
+			{Key: "_id", Value: prev.ID},
+			{Key: "status", Value: domains.ScheduleActive},
+			{Key: "occurrence", Value: prev.Occurrence},
+			{Key: "attempt", Value: prev.Attempt},
+		},
+		bson.D{
+			{Key: "$set", Value: bson.D{
+				{Key: "status", Value: next.Status},
+				{Key: "occurrence", Value: next.Occurrence},
+				{Key: "next_run_at", Value: next.NextRunAt},
+				{Key: "attempt", Value: next.Attempt},
+				{Key: "run_count", Value: next.RunCount},
+				{Key: "last_run_at", Value: next.LastRunAt},
+				{Key: "updated_at", Value: time.Now().UTC()},
+			}},
+			{Key: "$unset", Value: bson.D{{Key: "lease_owner", Value: ""}, {Key: "lease_until", Value: ""}}},
+		},
+	)
+	return err
+}
+
+func (s *scheduleRepository) ReleaseLease(ctx context.Context, id primitive.ObjectID, owner string) error {
+	col := s.mc.Database(s.db).Collection(s.col)
+	_, err := col.UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: id}, {Key: "lease_owner", Value: owner}},
+		bson.D{{Key: "$unset", Value: bson.D{{Key: "lease_owner", Value: ""}, {Key: "lease_until", Value: ""}}}},
+	)
+	return err
+}
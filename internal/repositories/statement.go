@@ -0,0 +1,238 @@
+package repositories
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports"
+	"github.com/wansanjou/backend-exercise-user-api/logging"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type statementRepository struct {
+	mc  *mongo.Client
+	db  string
+	col string
+}
+
+func NewStatementRepository(mc *mongo.Client, db string) ports.StatementRepository {
+	col := statementsCollection
+	ensureLedgerIndexes(mc.Database(db))
+	_, err := mc.Database(db).Collection(col).Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "period", Value: 1}, {Key: "format", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		panic(err)
+	}
+	return &statementRepository{mc, db, col}
+}
+
+func (s *statementRepository) BalanceBefore(ctx context.Context, userID primitive.ObjectID, t time.Time) (float64, error) {
+	db := s.mc.Database(s.db)
+	var u domains.User
+	err := db.Collection(usersCollection).FindOne(ctx, bson.D{{Key: "_id", Value: userID}},
+		options.FindOne().SetProjection(bson.D{{Key: "opening", Value: 1}})).Decode(&u)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return 0, err
+	}
+	totals, err := transferTotals(ctx, db, []primitive.ObjectID{userID}, t)
+	if err != nil {
+		return 0, err
+	}
+	return u.OpeningBalances()[domains.DefaultCurrency] + totals[userID][domains.DefaultCurrency].Net(), nil
+}
+
+func (s *statementRepository) StreamTransfers(ctx context.Context, userID primitive.ObjectID, from, to time.Time, fn func(domains.Transfer) error) error {
+	filter := bson.D{
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "from_user_id", Value: userID}},
+			bson.D{{Key: "to_user_id", Value: userID}},
+			bson.D{{Key: "fee_account_id", Value: userID}},
+		}},
+		{Key: "created_at", Value: bson.D{{Key: "$gte", Value: from}, {Key: "$lt", Value: to}}},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).SetBatchSize(500)
+	cursor, err := s.mc.Database(s.db).Collection(transfersCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var t domains.Transfer
+		if err := cursor.Decode(&t); err != nil {
+			return err
+		}
+		if err := fn(t); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func (s *statementRepository) UserIDsAfter(ctx context.Context, after primitive.ObjectID, limit int) ([]primitive.ObjectID, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := s.mc.Database(s.db).Collection(usersCollection).Find(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$gt", Value: after}}}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var users []domains.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, len(users))
+	for i, u := range users {
+		ids[i] = u.ID
+	}
+	return ids, nil
+}
+
+func (s *statementRepository) PeriodDone(ctx context.Context, period string) (bool, error) {
+	n, err := s.mc.Database(s.db).Collection(periodsCollection).CountDocuments(ctx, bson.D{{Key: "_id", Value: period}})
+	return n > 0, err
+}
+
+func (s *statementRepository) MarkPeriodDone(ctx context.Context, period string) error {
+	_, err := s.mc.Database(s.db).Collection(periodsCollection).UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: period}},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "completed_at", Value: time.Now().UTC()}}}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// CreateStored claims a user's statement for a period and format. It reports false with the
+// existing record when another run has already claimed it.
+func (s *statementRepository) CreateStored(ctx context.Context, in domains.StoredStatement) (*domains.StoredStatement, bool, error) {
+	col := s.mc.Database(s.db).Collection(s.col)
+	result, err := col.InsertOne(ctx, in)
+	if err == nil {
+		in.ID, _ = result.InsertedID.(primitive.ObjectID)
+		return &in, true, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return nil, false, err
+	}
+
+	existing, err := s.findOne(ctx, bson.D{
+		{Key: "user_id", Value: in.UserID},
+		{Key: "period", Value: in.Period},
+		{Key: "format", Value: in.Format},
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return existing, false, nil
+}
+
+func (s *statementRepository) UploadStored(ctx context.Context, id primitive.ObjectID, filename string, write func(w io.Writer) error) error {
+	bucket, err := s.bucket()
+	if err != nil {
+		return err
+	}
+	stream, err := bucket.OpenUploadStreamWithID(id, filename)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := stream.SetWriteDeadline(deadline); err != nil {
+			return err
+		}
+	}
+	if err := write(stream); err != nil {
+		if aerr := stream.Abort(); aerr != nil {
+			logging.FromContext(ctx).Warn("aborting statement upload left chunks behind", "statementId", id.Hex(), "error", aerr)
+		}
+		return err
+	}
+	return stream.Close()
+}
+
+func (s *statementRepository) CompleteStored(ctx context.Context, in domains.StoredStatement) error {
+	_, err := s.mc.Database(s.db).Collection(s.col).UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: in.ID}},
+		bson.D{{Key: "$set", Value: bson.D{
+			{Key: "status", Value: domains.StoredReady},
+			{Key: "opening_balance", Value: in.OpeningBalance},
+			{Key: "closing_balance", Value: in.ClosingBalance},
+			{Key: "entries", Value: in.Entries},
+		}}},
+	)
+	return err
+}
+
+// DeleteStored drops a statement and any file uploaded for it so the next run can regenerate it.
+func (s *statementRepository) DeleteStored(ctx context.Context, id primitive.ObjectID) error {
+	bucket, err := s.bucket()
+	if err != nil {
+		return err
+	}
+	if err := bucket.DeleteContext(ctx, id); err != nil && err != gridfs.ErrFileNotFound {
+		return err
+	}
+	_, err = s.mc.Database(s.db).Collection(s.col).DeleteOne(ctx, bson.D{{Key: "_id", Value: id}})
+	return err
+}
+
+func (s *statementRepository) ListStored(ctx context.Context, userID primitive.ObjectID) ([]domains.StoredStatement, error) {
+	var out []domains.StoredStatement
+	opts := options.Find().SetSort(bson.D{{Key: "period", Value: -1}, {Key: "format", Value: 1}})
+	cursor, err := s.mc.Database(s.db).Collection(s.col).Find(ctx, bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "status", Value: domains.StoredReady},
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *statementRepository) GetStored(ctx context.Context, id primitive.ObjectID) (*domains.StoredStatement, error) {
+	return s.findOne(ctx, bson.D{{Key: "_id", Value: id}})
+}
+
+func (s *statementRepository) OpenStored(ctx context.Context, id primitive.ObjectID) (io.ReadCloser, error) {
+	bucket, err := s.bucket()
+	if err != nil {
+		return nil, err
+	}
+	stream, err := bucket.OpenDownloadStream(id)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := stream.SetReadDeadline(deadline); err != nil {
+			return nil, err
+		}
+	}
+	return stream, nil
+}
+
+func (s *statementRepository) bucket() (*gridfs.Bucket, error) {
+	return gridfs.NewBucket(s.mc.Database(s.db), options.GridFSBucket().SetName(statementFilesBucket))
+}
+
+func (s *statementRepository) findOne(ctx context.Context, filter bson.D) (*domains.StoredStatement, error) {
+	out := domains.StoredStatement{}
+	if err := s.mc.Database(s.db).Collection(s.col).FindOne(ctx, filter).Decode(&out); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &out, nil
+}
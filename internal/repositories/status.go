@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SetStatus moves a user from change.From to change.To and records the change. It returns
+// nil if the user is no longer in change.From.
+func (u *userRepository) SetStatus(ctx context.Context, change domains.StatusChange) (*domains.User, error) {
+	var out *domains.User
+	err := withTransaction(ctx, u.mc, func(sc mongo.SessionContext) error {
+		var err error
+		out, err = changeStatus(sc, u.mc.Database(u.db), change)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (u *userRepository) ListStatusChanges(ctx context.Context, userID primitive.ObjectID) ([]domains.StatusChange, error) {
+	col := u.mc.Database(u.db).Collection(statusChangesCollection)
+	cursor, err := col.Find(ctx, bson.D{{Key: "user_id", Value: userID}}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	out := []domains.StatusChange{}
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// changeStatus applies and records a status change. It must run inside a transaction so
+// the record and the change are written together.
+func changeStatus(ctx context.Context, db *mongo.Database, change domains.StatusChange) (*domains.User, error) {
+	now := time.Now().UTC()
+	filter := bson.D{{Key: "_id", Value: change.UserID}, {Key: "status", Value: change.From}}
+	if change.From == domains.StatusActive {
+		// Accounts from before statuses existed have none; they count as active.
+		filter[1] = bson.E{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{domains.StatusActive, nil}}}}
+	}
+	set := bson.D{
+		{Key: "status", Value: change.To},
+		{Key: "status_reason", Value: change.Reason},
+		{Key: "frozen", Value: change.To == domains.StatusFrozen},
+	}
+	if change.To == domains.StatusFrozen {
+		set = append(set, bson.E{Key: "frozen_reason", Value: change.Reason}, bson.E{Key: "frozen_at", Value: now})
+	}
+
+	var out domains.User
+	err := db.Collection(usersCollection).FindOneAndUpdate(ctx, filter,
+		withVersion(bson.D{{Key: "$set", Value: set}}),
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&out)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	change.CreatedAt = now
+	if _, err := db.Collection(statusChangesCollection).InsertOne(ctx, change); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
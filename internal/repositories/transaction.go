@@ -0,0 +1,290 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	usersCollection      = "users"
+	transfersCollection  = "transfers"
+	holdsCollection      = "holds"
+	batchesCollection    = "transfer_batches"
+	schedulesCollection  = "scheduled_transfers"
+	runsCollection       = "scheduled_transfer_runs"
+	reconRunsCollection  = "reconciliation_runs"
+	mismatchesCollection = "balance_mismatches"
+	statementsCollection = "monthly_statements"
+	statementFilesBucket = "statement_files"
+	periodsCollection    = "statement_periods"
+	fxRatesCollection    = "fx_rates"
+	fxQuotesCollection   = "fx_quotes"
+
+	statusChangesCollection = "user_status_changes"
+	erasuresCollection      = "erasure_requests"
+)
+
+func withTransaction(ctx context.Context, mc *mongo.Client, fn func(sc mongo.SessionContext) error) error {
+	sess, err := mc.StartSession()
+	if err != nil {
+		return err
+	}
+	defer sess.EndSession(ctx)
+
+	_, err = sess.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	return err
+}
+
+// transfer moves in.Amount between two users, converting it at the quoted rate when the
+// currencies differ, charges in.Fee to the sender on behalf of the house account, and
+// records it. It must run inside a transaction.
+func transfer(ctx context.Context, db *mongo.Database, in domains.Transfer, limits domains.TransferLimits) (*domains.Transfer, error) {
+	if err := debit(ctx, db, in.FromUserID, in.SourceCurrency(), in.Amount+in.Fee); err != nil {
+		return nil, err
+	}
+
+	usage, err := transferUsage(ctx, db, in.FromUserID, in.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := limits.Check(usage, in.LimitAmount(), in.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	if err := credit(ctx, db, in.ToUserID, in.DestCurrency(), in.Credited()); err != nil {
+		return nil, err
+	}
+	if in.Fee > 0 {
+		if in.FeeAccountID == nil {
+			return nil, domains.NoFeeAccount()
+		}
+		if err := credit(ctx, db, *in.FeeAccountID, in.SourceCurrency(), in.Fee); err != nil {
+			return nil, err
+		}
+	}
+
+	out, err := insertTransfer(ctx, db, in)
+	if err != nil {
+		return nil, err
+	}
+	if in.QuoteID != nil {
+		if err := useQuote(ctx, db, *in.QuoteID, out.ID, in.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// useQuote marks an FX quote as spent by transferID, failing if it expired or was already used.
+func useQuote(ctx context.Context, db *mongo.Database, quoteID, transferID primitive.ObjectID, now time.Time) error {
+	res, err := db.Collection(fxQuotesCollection).UpdateOne(ctx,
+		bson.D{
+			{Key: "_id", Value: quoteID},
+			{Key: "used_at", Value: nil},
+			{Key: "expires_at", Value: bson.D{{Key: "$gt", Value: now}}},
+		},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "used_at", Value: now}, {Key: "transfer_id", Value: transferID}}}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return domains.Conflict("quote_unavailable", "quote has expired or was already used")
+	}
+	return nil
+}
+
+// debit takes amount from a user's balance, failing if the balance would go negative or
+// the account is frozen. Because it writes the sender's document first, two concurrent
+// transactions for the same sender conflict here and one of them is retried after the other commits.
+func debit(ctx context.Context, db *mongo.Database, userID primitive.ObjectID, currency string, amount float64) error {
+	field := domains.BalanceField(currency)
+	res, err := db.Collection(usersCollection).UpdateOne(ctx,
+		spendable(userID, field, amount),
+		bson.D{{Key: "$inc", Value: bson.D{{Key: field, Value: -amount}, bumpVersion}}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return whyNotSpendable(ctx, db, userID)
+	}
+	return nil
+}
+
+// spendable matches an active, undeleted user with at least amount available in field.
+func spendable(userID primitive.ObjectID, field string, amount float64) bson.D {
+	return bson.D{
+		{Key: "_id", Value: userID},
+		{Key: field, Value: bson.D{{Key: "$gte", Value: amount}}},
+		{Key: "frozen", Value: bson.D{{Key: "$ne", Value: true}}},
+		{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{domains.StatusActive, nil}}}},
+		notDeleted,
+	}
+}
+
+// whyNotSpendable explains why a spendable filter matched nothing.
+func whyNotSpendable(ctx context.Context, db *mongo.Database, userID primitive.ObjectID) error {
+	u := domains.User{}
+	err := db.Collection(usersCollection).FindOne(ctx, bson.D{{Key: "_id", Value: userID}}).Decode(&u)
+	if err == mongo.ErrNoDocuments {
+		return domains.NotFound("sender")
+	}
+	if err != nil {
+		return err
+	}
+	if u.Deleted() {
+		return domains.Conflict("account_deleted", "account has been deleted")
+	}
+	switch u.AccountStatus() {
+	case domains.StatusFrozen:
+		return domains.Conflict("account_frozen", "account is frozen")
+	case domains.StatusSuspended:
+		return domains.Conflict("account_suspended", "account is suspended")
+	case domains.StatusClosed:
+		return domains.Conflict("account_closed", "account is closed")
+	}
+	return domains.InsufficientFunds("insufficient balance")
+}
+
+// credit adds amount to a user's balance. Closed and deleted accounts cannot be credited.
+func credit(ctx context.Context, db *mongo.Database, userID primitive.ObjectID, currency string, amount float64) error {
+	res, err := db.Collection(usersCollection).UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: userID}, notDeleted, {Key: "status", Value: bson.D{{Key: "$ne", Value: domains.StatusClosed}}}},
+		bson.D{{Key: "$inc", Value: bson.D{{Key: domains.BalanceField(currency), Value: amount}, bumpVersion}}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		n, err := db.Collection(usersCollection).CountDocuments(ctx, bson.D{{Key: "_id", Value: userID}, {Key: "status", Value: domains.StatusClosed}})
+		if err == nil && n > 0 {
+			return domains.Conflict("account_closed", "recipient account is closed")
+		}
+		return domains.NotFound("recipient")
+	}
+	return nil
+}
+
+// reserve moves amount from a user's available balance into their held balance.
+// Holds are always in the default currency.
+func reserve(ctx context.Context, db *mongo.Database, userID primitive.ObjectID, amount float64) error {
+	res, err := db.Collection(usersCollection).UpdateOne(ctx,
+		spendable(userID, "balance", amount),
+		bson.D{{Key: "$inc", Value: bson.D{{Key: "balance", Value: -amount}, {Key: "held_balance", Value: amount}, bumpVersion}}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return whyNotSpendable(ctx, db, userID)
+	}
+	return nil
+}
+
+// release takes held off a user's held balance and returns refund of it to their available balance.
+func release(ctx context.Context, db *mongo.Database, userID primitive.ObjectID, held, refund float64) error {
+	_, err := db.Collection(usersCollection).UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: userID}},
+		bson.D{{Key: "$inc", Value: bson.D{{Key: "held_balance", Value: -held}, {Key: "balance", Value: refund}, bumpVersion}}},
+	)
+	return err
+}
+
+// transferUsage sums what userID has sent since the start of the current month, counting
+// active holds as sent since they were placed: the money is committed, and capturing one
+// is checked again as a transfer of its own. Reversals are refunds rather than spending,
+// so they do not count.
+func transferUsage(ctx context.Context, db *mongo.Database, userID primitive.ObjectID, now time.Time) (domains.TransferUsage, error) {
+	_, _, month := domains.LimitWindows(now)
+	// Limits are in the default currency; foreign-currency transfers record what they were worth in it.
+	sent, err := sumUsage(ctx, db.Collection(transfersCollection), now,
+		bson.D{
+			{Key: "from_user_id", Value: userID},
+			{Key: "created_at", Value: bson.D{{Key: "$gte", Value: month}}},
+			{Key: "reversal_of", Value: bson.D{{Key: "$exists", Value: false}}},
+		},
+		bson.D{{Key: "$ifNull", Value: bson.A{"$base_amount", "$amount"}}},
+	)
+	if err != nil {
+		return domains.TransferUsage{}, err
+	}
+	held, err := sumUsage(ctx, db.Collection(holdsCollection), now,
+		bson.D{
+			{Key: "from_user_id", Value: userID},
+			{Key: "status", Value: domains.HoldActive},
+			{Key: "created_at", Value: bson.D{{Key: "$gte", Value: month}}},
+		},
+		"$amount",
+	)
+	if err != nil {
+		return domains.TransferUsage{}, err
+	}
+	return domains.TransferUsage{
+		HourlyCount:  sent.HourlyCount + held.HourlyCount,
+		DailyTotal:   sent.DailyTotal + held.DailyTotal,
+		MonthlyTotal: sent.MonthlyTotal + held.MonthlyTotal,
+	}, nil
+}
+
+// sumUsage totals amount over the documents of col matching match, by limit window.
+func sumUsage(ctx context.Context, col *mongo.Collection, now time.Time, match bson.D, amount interface{}) (domains.TransferUsage, error) {
+	hour, day, _ := domains.LimitWindows(now)
+	since := func(t time.Time) bson.D {
+		return bson.D{{Key: "$gte", Value: bson.A{"$created_at", t}}}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: nil},
+			{Key: "monthly", Value: bson.D{{Key: "$sum", Value: amount}}},
+			{Key: "daily", Value: bson.D{{Key: "$sum", Value: bson.D{
+				{Key: "$cond", Value: bson.A{since(day), amount, 0}},
+			}}}},
+			{Key: "hourly", Value: bson.D{{Key: "$sum", Value: bson.D{
+				{Key: "$cond", Value: bson.A{since(hour), 1, 0}},
+			}}}},
+		}}},
+	}
+
+	cursor, err := col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return domains.TransferUsage{}, err
+	}
+	defer cursor.Close(ctx)
+
+	var out []struct {
+		Monthly float64 `bson:"monthly"`
+		Daily   float64 `bson:"daily"`
+		Hourly  int     `bson:"hourly"`
+	}
+	if err := cursor.All(ctx, &out); err != nil {
+		return domains.TransferUsage{}, err
+	}
+	if len(out) == 0 {
+		return domains.TransferUsage{}, nil
+	}
+	return domains.TransferUsage{
+		HourlyCount:  out[0].Hourly,
+		DailyTotal:   out[0].Daily,
+		MonthlyTotal: out[0].Monthly,
+	}, nil
+}
+
+func insertTransfer(ctx context.Context, db *mongo.Database, in domains.Transfer) (*domains.Transfer, error) {
+	result, err := db.Collection(transfersCollection).InsertOne(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	oid, _ := result.InsertedID.(primitive.ObjectID)
+	in.ID = oid
+	return &in, nil
+}
@@ -0,0 +1,279 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/ports"
+	"github.com/wansanjou/backend-exercise-user-api/logging"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// amountEpsilon absorbs float rounding when comparing refunded totals against the original amount.
+const amountEpsilon = 1e-9
+
+type transferRepository struct {
+	mc  *mongo.Client
+	db  string
+	col string
+}
+
+func NewTransferRepository(mc *mongo.Client, db string) ports.TransferRepository {
+	col := transfersCollection
+	_, err := mc.Database(db).Collection(col).Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "reversal_of", Value: 1}}},
+		{Keys: bson.D{{Key: "batch_id", Value: 1}}},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return &transferRepository{mc, db, col}
+}
+
+func (t *transferRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*domains.Transfer, error) {
+	return t.findOne(ctx, bson.D{{Key: "_id", Value: id}})
+}
+
+func (t *transferRepository) Reverse(ctx context.Context, reversal domains.Transfer, allowNegative bool) (*domains.Transfer, error) {
+	db := t.mc.Database(t.db)
+
+	var out *domains.Transfer
+	err := withTransaction(ctx, t.mc, func(sc mongo.SessionContext) error {
+		in := reversal
+		orig, err := t.findOne(sc, bson.D{{Key: "_id", Value: *in.ReversalOf}})
+		if err != nil {
+			return err
+		}
+		if orig == nil {
+			return domains.NotFound("transfer")
+		}
+		if orig.ReversalOf != nil {
+			return domains.Conflict("reversal_not_reversible", "cannot reverse a reversal")
+		}
+
+		remaining := orig.Amount - orig.ReversedAmount
+		if in.Amount == 0 {
+			in.Amount = remaining
+		}
+		if remaining <= amountEpsilon {
+			return domains.Conflict("already_reversed", "transfer is already fully reversed")
+		}
+		if in.Amount > remaining+amountEpsilon {
+			return domains.Conflict("refund_exceeds_remaining", "refund exceeds the amount left on the original transfer")
+		}
+
+		feeRefund := orig.FeeRefund(in.Amount)
+
+		// Bumping the original inside the transaction makes concurrent reversals of the
+		// same transfer conflict, so their combined total can never pass orig.Amount.
+		_, err = db.Collection(t.col).UpdateOne(sc,
+			bson.D{{Key: "_id", Value: orig.ID}},
+			bson.D{{Key: "$inc", Value: bson.D{{Key: "reversed_amount", Value: in.Amount}, {Key: "refunded_fee", Value: feeRefund}}}},
+		)
+		if err != nil {
+			return err
+		}
+
+		// in.Amount is in the sender's currency; take back the matching share of what the
+		// recipient was credited, at the original rate.
+		refund := in.Amount
+		taken := in.Amount
+		if orig.ToAmount != 0 {
+			taken = math.Round(in.Amount*orig.ToAmount/orig.Amount*100) / 100
+		}
+
+		if allowNegative {
+			err = credit(sc, db, orig.ToUserID, orig.DestCurrency(), -taken)
+		} else {
+			err = debit(sc, db, orig.ToUserID, orig.DestCurrency(), taken)
+		}
+		if err != nil {
+			if errors.Is(err, domains.ErrInsufficientFunds) {
+				return domains.InsufficientFunds("recipient has insufficient balance for the reversal")
+			}
+			return err
+		}
+		if err := credit(sc, db, orig.FromUserID, orig.SourceCurrency(), refund); err != nil {
+			return err
+		}
+
+		in.FromUserID = orig.ToUserID
+		in.ToUserID = orig.FromUserID
+		in.Currency = orig.Currency
+		if orig.ToAmount != 0 {
+			in.Currency, in.ToCurrency = orig.DestCurrency(), orig.SourceCurrency()
+			in.Amount, in.ToAmount, in.Rate = taken, refund, 1/orig.Rate
+		}
+		in.CreatedAt = time.Now().UTC()
+		in.RefundedFee = feeRefund
+		out, err = insertTransfer(sc, db, in)
+		if err != nil || feeRefund == 0 {
+			return err
+		}
+
+		// The fee comes back from the house account as a transfer of its own, so the ledger
+		// and statements account for it like any other money movement.
+		if err := debit(sc, db, *orig.FeeAccountID, orig.SourceCurrency(), feeRefund); err != nil {
+			return err
+		}
+		if err := credit(sc, db, orig.FromUserID, orig.SourceCurrency(), feeRefund); err != nil {
+			return err
+		}
+		_, err = insertTransfer(sc, db, domains.Transfer{
+			FromUserID: *orig.FeeAccountID,
+			ToUserID:   orig.FromUserID,
+			Amount:     feeRefund,
+			Currency:   orig.Currency,
+			ReversalOf: &orig.ID,
+			Reason:     "fee refund",
+			ActorID:    in.ActorID,
+			CreatedAt:  in.CreatedAt,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (t *transferRepository) ExecuteBatch(ctx context.Context, batch domains.TransferBatch, limits domains.TransferLimits) (*domains.TransferBatch, error) {
+	db := t.mc.Database(t.db)
+	batches := db.Collection(batchesCollection)
+
+	batch.Status = domains.BatchProcessing
+	batch.CreatedAt = time.Now().UTC()
+	result, err := batches.InsertOne(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+	batch.ID, _ = result.InsertedID.(primitive.ObjectID)
+
+	line := func(sc mongo.SessionContext, i int) error {
+		l := &batch.Lines[i]
+		tr, err := transfer(sc, db, domains.Transfer{
+			FromUserID:   batch.FromUserID,
+			ToUserID:     l.ToUserID,
+			Amount:       l.Amount,
+			Fee:          l.Fee,
+			FeeAccountID: batch.FeeAccountID,
+			BatchID:      &batch.ID,
+			CreatedAt:    time.Now().UTC(),
+		}, limits)
+		if err != nil {
+			return err
+		}
+		l.TransferID = &tr.ID
+		return nil
+	}
+
+	if batch.Mode == domains.BatchModeAtomic {
+		failed := -1
+		err := withTransaction(ctx, t.mc, func(sc mongo.SessionContext) error {
+			failed = -1
+			for i := range batch.Lines {
+				if err := line(sc, i); err != nil {
+					failed = i
+					return err
+				}
+			}
+			return nil
+		})
+		for i := range batch.Lines {
+			l := &batch.Lines[i]
+			switch {
+			case err == nil:
+				l.Status = domains.BatchLineSucceeded
+			case i == failed:
+				l.Status, l.Error = domains.BatchLineFailed, lineError(ctx, batch.ID, i, err)
+			default:
+				l.Status, l.TransferID = domains.BatchLineFailed, nil
+				l.Error = "rolled back with the rest of the batch"
+			}
+		}
+	} else {
+		for i := range batch.Lines {
+			err := withTransaction(ctx, t.mc, func(sc mongo.SessionContext) error {
+				return line(sc, i)
+			})
+			if err != nil {
+				batch.Lines[i].Status, batch.Lines[i].Error = domains.BatchLineFailed, lineError(ctx, batch.ID, i, err)
+				batch.Lines[i].TransferID = nil
+				continue
+			}
+			batch.Lines[i].Status = domains.BatchLineSucceeded
+		}
+	}
+
+	succeeded := 0
+	for _, l := range batch.Lines {
+		if l.Status == domains.BatchLineSucceeded {
+			succeeded++
+		}
+	}
+	switch succeeded {
+	case len(batch.Lines):
+		batch.Status = domains.BatchCompleted
+	case 0:
+		batch.Status = domains.BatchFailed
+	default:
+		batch.Status = domains.BatchPartiallyCompleted
+	}
+	completed := time.Now().UTC()
+	batch.CompletedAt = &completed
+
+	_, err = batches.UpdateOne(ctx, bson.D{{Key: "_id", Value: batch.ID}}, bson.D{{Key: "$set", Value: bson.D{
+		{Key: "status", Value: batch.Status},
+		{Key: "lines", Value: batch.Lines},
+		{Key: "completed_at", Value: batch.CompletedAt},
+	}}})
+	if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// lineError is what the batch report says about line i failing with err. The report goes
+// back to the client, so it only carries the message of errors meant for them; anything
+// else is logged and reported as an internal error.
+func lineError(ctx context.Context, batchID primitive.ObjectID, i int, err error) string {
+	var de *domains.Error
+	if errors.As(err, &de) {
+		return de.Message
+	}
+	var le *domains.LimitExceededError
+	if errors.As(err, &le) {
+		return le.Error()
+	}
+	logging.FromContext(ctx).Error("batch transfer line failed", "batchId", batchID.Hex(), "line", i, "error", err)
+	return "internal error"
+}
+
+func (t *transferRepository) GetBatchByID(ctx context.Context, id primitive.ObjectID) (*domains.TransferBatch, error) {
+	out := domains.TransferBatch{}
+	col := t.mc.Database(t.db).Collection(batchesCollection)
+	if err := col.FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&out); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (t *transferRepository) findOne(ctx context.Context, filter bson.D) (*domains.Transfer, error) {
+	out := domains.Transfer{}
+	col := t.mc.Database(t.db).Collection(t.col)
+	if err := col.FindOne(ctx, filter).Decode(&out); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &out, nil
+}
@@ -2,6 +2,11 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"log/slog"
+	"maps"
+	"regexp"
+	"slices"
 	"time"
 
 	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
@@ -12,22 +17,156 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// emailCollation compares emails ignoring case, for the unique index and for lookups.
+var emailCollation = &options.Collation{Locale: "en", Strength: 2}
+
+const emailIndex = "email_ci"
+
+// notDeleted matches users who have not been soft-deleted.
+var notDeleted = bson.E{Key: "deleted_at", Value: bson.D{{Key: "$exists", Value: false}}}
+
+// notErased matches users whose personal data has not been erased. Erased users stay
+// deleted for good.
+var notErased = bson.E{Key: "erased_at", Value: bson.D{{Key: "$exists", Value: false}}}
+
+// bumpVersion goes in the $inc of every update to a user, so conditional writes can tell
+// the document has changed since it was read.
+var bumpVersion = bson.E{Key: "version", Value: int64(1)}
+
+// withVersion adds bumpVersion to update, which is either an update document or a pipeline.
+func withVersion(update interface{}) interface{} {
+	switch up := update.(type) {
+	case bson.D:
+		out := make(bson.D, 0, len(up)+1)
+		bumped := false
+		for _, op := range up {
+			if inc, ok := op.Value.(bson.D); ok && op.Key == "$inc" {
+				op.Value = append(inc[:len(inc):len(inc)], bumpVersion)
+				bumped = true
+			}
+			out = append(out, op)
+		}
+		if !bumped {
+			out = append(out, bson.E{Key: "$inc", Value: bson.D{bumpVersion}})
+		}
+		return out
+	case mongo.Pipeline:
+		return append(up[:len(up):len(up)], bson.D{{Key: "$set", Value: bson.D{{Key: "version", Value: bson.D{
+			{Key: "$add", Value: bson.A{bson.D{{Key: "$ifNull", Value: bson.A{"$version", 0}}}, 1}},
+		}}}}})
+	}
+	return update
+}
+
 type userRepository struct {
 	mc  *mongo.Client
 	db  string
 	col string
+	// pii encrypts personal fields; nil leaves them in plaintext.
+	pii *PIICipher
 }
 
-func NewUserRepository(mc *mongo.Client, db string) ports.UserRepository {
-	col := "users"
-	_, err := mc.Database(db).Collection(col).Indexes().CreateOne(context.Background(), mongo.IndexModel{
-		Keys:    bson.D{{Key: "email", Value: 1}},
-		Options: options.Index().SetUnique(true),
+// NewUserRepository returns the users repository. With pii set, mc must have been created
+// with pii.Registry().
+func NewUserRepository(mc *mongo.Client, db string, pii *PIICipher) ports.UserRepository {
+	col := usersCollection
+	repo := &userRepository{mc, db, col, pii}
+	err := repo.EnsureEmailIndex(context.Background())
+	if mongo.IsDuplicateKeyError(err) {
+		// Leave the old index in place; cmd/migrate-emails reports the accounts to merge.
+		slog.Warn("users: emails that differ only by case exist, run migrate-emails", "error", err)
+	} else if err != nil {
+		panic(err)
+	}
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "handle", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.D{{Key: "handle", Value: bson.D{{Key: "$type", Value: "string"}}}}),
+		},
+		// Keyset pagination and search for GET /users.
+		{Keys: bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "name_key", Value: 1}}},
+		{Keys: bson.D{{Key: "email_key", Value: 1}}},
+		// Searches on custom attributes, whatever the namespace.
+		{Keys: bson.D{{Key: "attributes.$**", Value: 1}}},
+		{
+			// Anyone may claim a number, but only one account can have it verified.
+			Keys: bson.D{{Key: "phone", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.D{{Key: "phone_verified", Value: true}}),
+		},
+		// The same two rules over blind indexes, for when emails and phones are encrypted,
+		// and the lookup of encrypted names.
+		{Keys: bson.D{{Key: "name_bidx", Value: 1}}},
+		{
+			Keys: bson.D{{Key: "email_bidx", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.D{{Key: "email_bidx", Value: bson.D{{Key: "$type", Value: "string"}}}}),
+		},
+		{
+			Keys: bson.D{{Key: "phone_bidx", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.D{
+				{Key: "phone_verified", Value: true},
+				{Key: "phone_bidx", Value: bson.D{{Key: "$type", Value: "string"}}},
+			}),
+		},
+	}
+	if pii == nil {
+		indexes = append(indexes, mongo.IndexModel{Keys: bson.D{{Key: "name", Value: "text"}}, Options: options.Index().SetName("name_text")})
+	} else if _, err := mc.Database(db).Collection(col).Indexes().DropOne(context.Background(), "name_text"); err != nil && !isIndexNotFound(err) {
+		// The text index would keep every name it has seen in plaintext.
+		panic(err)
+	}
+	_, err = mc.Database(db).Collection(col).Indexes().CreateMany(context.Background(), indexes)
+	if err != nil {
+		panic(err)
+	}
+	// Users created before versioning start at version 0.
+	_, err = mc.Database(db).Collection(col).UpdateMany(context.Background(),
+		bson.D{{Key: "version", Value: bson.D{{Key: "$exists", Value: false}}}},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "version", Value: int64(0)}}}},
+	)
+	if err != nil {
+		panic(err)
+	}
+	// Users created before statuses existed are active, or frozen if reconciliation froze them.
+	_, err = mc.Database(db).Collection(col).UpdateMany(context.Background(),
+		bson.D{{Key: "status", Value: bson.D{{Key: "$exists", Value: false}}}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.D{{Key: "status", Value: bson.D{{Key: "$cond", Value: bson.A{
+			bson.D{{Key: "$eq", Value: bson.A{"$frozen", true}}}, domains.StatusFrozen, domains.StatusActive,
+		}}}}}}}},
+	)
+	if err != nil {
+		panic(err)
+	}
+	_, err = mc.Database(db).Collection(statusChangesCollection).Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		panic(err)
+	}
+	// Users created before search keys existed get them now. Encrypted users go without.
+	if pii == nil {
+		_, err = mc.Database(db).Collection(col).UpdateMany(context.Background(),
+			bson.D{{Key: "name_key", Value: bson.D{{Key: "$exists", Value: false}}}},
+			mongo.Pipeline{{{Key: "$set", Value: bson.D{
+				{Key: "name_key", Value: bson.D{{Key: "$toLower", Value: bson.D{{Key: "$trim", Value: bson.D{{Key: "input", Value: "$name"}}}}}}},
+				{Key: "email_key", Value: bson.D{{Key: "$toLower", Value: bson.D{{Key: "$trim", Value: bson.D{{Key: "input", Value: "$email"}}}}}}},
+			}}}},
+		)
+		if err != nil {
+			panic(err)
+		}
+	}
+	_, err = mc.Database(db).Collection(transfersCollection).Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "from_user_id", Value: 1}, {Key: "created_at", Value: -1}},
 	})
 	if err != nil {
 		panic(err)
 	}
-	return &userRepository{mc, db, col}
+	return repo
 }
 
 func (u *userRepository) Create(ctx context.Context, data domains.User) (*domains.User, error) {
@@ -38,36 +177,203 @@ func (u *userRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*d
 	return u.findOne(ctx, bson.D{{Key: "_id", Value: id}})
 }
 
-func (u *userRepository) GetUsers(ctx context.Context, data domains.FindAllUsers) ([]domains.User, error) {
+// GetUsers reads one page of users in q.SortBy order, starting after q.After. It reads one
+// user more than the page holds to learn whether there is a next page.
+func (u *userRepository) GetUsers(ctx context.Context, q domains.UserQuery) (*domains.UserPage, error) {
+	filter, err := u.userFilter(q)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &domains.UserPage{}
+	if q.Total {
+		total, err := u.mc.Database(u.db).Collection(u.col).CountDocuments(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		out.Total = &total
+	}
+
+	if q.SortBy.Field == domains.SortRelevance {
+		return u.searchUsers(ctx, q, filter, out)
+	}
+	if (q.SortBy.Field == "name" || q.SortBy.Field == "email") && u.pii != nil {
+		return nil, domains.Invalid("sort", "users cannot be sorted by "+q.SortBy.Field+" while personal data is encrypted")
+	}
+
+	dir, cmp := 1, "$gt"
+	if q.SortBy.Descending {
+		dir, cmp = -1, "$lt"
+	}
+	if q.After != nil {
+		filter = append(filter, after(q.SortBy.Field, cmp, q.After))
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: q.SortBy.Field, Value: dir}, {Key: "_id", Value: dir}}).
+		SetLimit(int64(q.Limit + 1))
+	users, err := u.find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(users) > q.Limit {
+		users = users[:q.Limit]
+		out.NextCursor = domains.CursorAfter(users[len(users)-1], q.SortBy).Encode()
+	}
+	out.Users = users
+	return out, nil
+}
+
+// searchUsers pages through a full-text search, best match first. The text score is not
+// a stored field, so the page is read with an aggregation that adds it before filtering
+// on the cursor.
+func (u *userRepository) searchUsers(ctx context.Context, q domains.UserQuery, filter bson.D, out *domains.UserPage) (*domains.UserPage, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$addFields", Value: bson.D{{Key: "search_score", Value: bson.D{{Key: "$meta", Value: "textScore"}}}}}},
+	}
+	if q.After != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.D{after("search_score", "$lt", q.After)}}})
+	}
+	pipeline = append(pipeline,
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "search_score", Value: -1}, {Key: "_id", Value: -1}}}},
+		bson.D{{Key: "$limit", Value: q.Limit + 1}},
+	)
+
+	cursor, err := u.mc.Database(u.db).Collection(u.col).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	// Scores are read on their own, since an inline User would skip the codec that opens
+	// encrypted fields.
+	users := []domains.User{}
+	var scores []float64
+	for cursor.Next(ctx) {
+		var user domains.User
+		var doc struct {
+			Score float64 `bson:"search_score"`
+		}
+		if err := cursor.Decode(&user); err != nil {
+			return nil, err
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		users, scores = append(users, user), append(scores, doc.Score)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	if len(users) > q.Limit {
+		users = users[:q.Limit]
+		out.NextCursor = domains.RelevanceCursor(users[q.Limit-1].ID, scores[q.Limit-1]).Encode()
+	}
+	out.Users = users
+	return out, nil
+}
+
+// userFilter turns the filters of a user listing into a query. Name and email are matched
+// as escaped, anchored prefixes of their search keys so the match can use an index, or
+// whole by their blind indexes while they are encrypted. Full-text search is refused then,
+// since there is no plaintext to search.
+func (u *userRepository) userFilter(q domains.UserQuery) (bson.D, error) {
 	filter := bson.D{}
-	if data.Name != "" {
-		filter = append(filter, bson.E{
-			Key: "name", Value: bson.D{{Key: "$regex", Value: data.Name}, {Key: "$options", Value: "i"}},
-		})
+	switch q.Status {
+	case domains.UserStatusDeleted:
+		filter = append(filter, bson.E{Key: "deleted_at", Value: bson.D{{Key: "$exists", Value: true}}})
+	case "":
+		if !q.IncludeDeleted {
+			filter = append(filter, notDeleted)
+		}
+	default:
+		filter = append(filter, notDeleted, bson.E{Key: "status", Value: q.Status})
+	}
+	if q.Search != "" {
+		if u.pii != nil {
+			return nil, domains.Invalid("search", "users cannot be searched while personal data is encrypted")
+		}
+		filter = append(filter, bson.E{Key: "$text", Value: bson.D{{Key: "$search", Value: q.Search}}})
+	}
+	if q.Name != "" {
+		filter = append(filter, u.nameSearch(q.Name))
+	}
+	if q.Email != "" {
+		filter = append(filter, u.emailSearch(q.Email))
 	}
-	if data.Email != "" {
-		filter = append(filter, bson.E{
-			Key: "email", Value: bson.D{{Key: "$regex", Value: data.Email}, {Key: "$options", Value: "i"}},
-		})
+	if r := between(q.CreatedFrom, q.CreatedTo); len(r) > 0 {
+		filter = append(filter, bson.E{Key: "created_at", Value: r})
 	}
+	if r := between(q.MinBalance, q.MaxBalance); len(r) > 0 {
+		filter = append(filter, bson.E{Key: "balance", Value: r})
+	}
+	for _, path := range slices.Sorted(maps.Keys(q.Attributes)) {
+		filter = append(filter, bson.E{Key: "attributes." + path, Value: q.Attributes[path]})
+	}
+	return filter, nil
+}
 
-	if data.Page < 1 {
-		data.Page = 1
+// emailFilter matches users holding any of emails, ignoring case, and returns the
+// collation to run it with.
+func (u *userRepository) emailFilter(emails ...string) (bson.D, *options.Collation) {
+	if u.pii == nil {
+		return bson.D{{Key: "email", Value: bson.D{{Key: "$in", Value: emails}}}}, emailCollation
 	}
-	if data.Limit < 1 {
-		data.Limit = 10
+	bidx := make([]string, len(emails))
+	for i, e := range emails {
+		bidx[i] = u.pii.blindIndex("email", e)
 	}
-	skip := int64((data.Page - 1) * data.Limit)
-	limit := int64(data.Limit)
+	return bson.D{{Key: "email_bidx", Value: bson.D{{Key: "$in", Value: bidx}}}}, nil
+}
 
-	opts := options.Find().SetSkip(skip).SetLimit(limit)
+// emailSearch matches emails starting with email, or only email itself once emails are
+// encrypted and can only be found by their blind index.
+func (u *userRepository) emailSearch(email string) bson.E {
+	if u.pii != nil {
+		return bson.E{Key: "email_bidx", Value: u.pii.blindIndex("email", email)}
+	}
+	return prefix("email_key", domains.SearchKey(email))
+}
 
-	return u.find(ctx, filter, opts)
+// nameSearch matches names starting with name, or only name itself once names are
+// encrypted.
+func (u *userRepository) nameSearch(name string) bson.E {
+	if u.pii != nil {
+		return bson.E{Key: "name_bidx", Value: u.pii.blindIndex("name", name)}
+	}
+	return prefix("name_key", domains.SearchKey(name))
+}
+
+func prefix(field, value string) bson.E {
+	return bson.E{Key: field, Value: primitive.Regex{Pattern: "^" + regexp.QuoteMeta(value)}}
+}
+
+// between is an inclusive range with either end left open when nil.
+func between[T any](from, to *T) bson.D {
+	r := bson.D{}
+	if from != nil {
+		r = append(r, bson.E{Key: "$gte", Value: *from})
+	}
+	if to != nil {
+		r = append(r, bson.E{Key: "$lte", Value: *to})
+	}
+	return r
+}
+
+// after matches the documents that come after cursor c in field order; cmp is $gt for an
+// ascending sort and $lt for a descending one.
+func after(field, cmp string, c *domains.UserCursor) bson.E {
+	v := c.SortValue()
+	return bson.E{Key: "$or", Value: bson.A{
+		bson.D{{Key: field, Value: bson.D{{Key: cmp, Value: v}}}},
+		bson.D{{Key: field, Value: v}, {Key: "_id", Value: bson.D{{Key: cmp, Value: c.ID}}}},
+	}}
 }
 
 func (u *userRepository) Count(ctx context.Context) (int64, error) {
 	col := u.mc.Database(u.db).Collection(u.col)
-	count, err := col.CountDocuments(ctx, bson.D{})
+	count, err := col.CountDocuments(ctx, bson.D{notDeleted})
 	if err != nil {
 		return 0, err
 	}
@@ -75,44 +381,360 @@ func (u *userRepository) Count(ctx context.Context) (int64, error) {
 }
 
 func (u *userRepository) FindByEmail(ctx context.Context, email string) (*domains.User, error) {
-	return u.findOne(ctx, bson.D{{Key: "email", Value: email}})
+	out := domains.User{}
+	col := u.mc.Database(u.db).Collection(u.col)
+	filter, collation := u.emailFilter(email)
+	err := col.FindOne(ctx, filter, options.FindOne().SetCollation(collation)).Decode(&out)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
 }
 
-func (u *userRepository) TransferWithTransaction(ctx context.Context, fromID, toID primitive.ObjectID, amount float64) error {
-	filterFrom := bson.D{
-		{Key: "_id", Value: fromID},
-		{Key: "balance", Value: bson.D{{Key: "$gte", Value: amount}}},
+func (u *userRepository) ListEmails(ctx context.Context) ([]domains.EmailRecord, error) {
+	col := u.mc.Database(u.db).Collection(u.col)
+	opts := options.Find().SetProjection(bson.D{{Key: "email", Value: 1}}).SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := col.Find(ctx, bson.D{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var out []domains.EmailRecord
+	for cursor.Next(ctx) {
+		var doc domains.User
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		out = append(out, domains.EmailRecord{UserID: doc.ID, Email: doc.Email})
+	}
+	return out, cursor.Err()
+}
+
+func (u *userRepository) SetEmail(ctx context.Context, id primitive.ObjectID, email string) error {
+	col := u.mc.Database(u.db).Collection(u.col)
+	update, err := u.sealUpdate(bson.D{
+		{Key: "email", Value: email},
+		{Key: "email_key", Value: domains.SearchKey(email)},
+	}, nil)
+	if err != nil {
+		return err
 	}
-	updateFrom := bson.D{{Key: "$inc", Value: bson.D{{Key: "balance", Value: -amount}}}}
+	_, err = col.UpdateOne(ctx, bson.D{{Key: "_id", Value: id}}, withVersion(update))
+	if mongo.IsDuplicateKeyError(err) {
+		return domains.Conflict("email_taken", "email is already registered")
+	}
+	return err
+}
 
-	fromUser, err := u.findOneAndUpdate(ctx, filterFrom, updateFrom)
+func (u *userRepository) EnsureEmailIndex(ctx context.Context) error {
+	indexes := u.mc.Database(u.db).Collection(u.col).Indexes()
+	_, err := indexes.CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "email", Value: 1}},
+		Options: options.Index().SetName(emailIndex).SetUnique(true).SetCollation(emailCollation),
+	})
 	if err != nil {
 		return err
 	}
-	if fromUser == nil {
+	// The original case-sensitive index; gone already on a fresh database.
+	if _, err := indexes.DropOne(ctx, "email_1"); err != nil && !isIndexNotFound(err) {
 		return err
 	}
+	return nil
+}
+
+// isIndexNotFound reports whether err is Mongo's IndexNotFound.
+func isIndexNotFound(err error) bool {
+	var cmdErr mongo.CommandError
+	return errors.As(err, &cmdErr) && cmdErr.Code == 27
+}
+
+func (u *userRepository) FindByHandle(ctx context.Context, handle string) (*domains.User, error) {
+	return u.findOne(ctx, bson.D{{Key: "handle", Value: handle}, notDeleted})
+}
+
+// FindByPhone finds the account that has verified phone.
+func (u *userRepository) FindByPhone(ctx context.Context, phone string) (*domains.User, error) {
+	match := bson.E{Key: "phone", Value: phone}
+	if u.pii != nil {
+		match = bson.E{Key: "phone_bidx", Value: u.pii.blindIndex("phone", phone)}
+	}
+	return u.findOne(ctx, bson.D{match, {Key: "phone_verified", Value: true}, notDeleted})
+}
+
+// UpdateAliases sets the fields of in that are not nil; an empty value removes the alias.
+// A changed phone number has to be verified again.
+func (u *userRepository) UpdateAliases(ctx context.Context, id primitive.ObjectID, in domains.UpdateAliasesRequest) (*domains.User, error) {
+	set, unset := aliasChanges(in.Handle, in.Phone)
+	return u.updateProfile(ctx, bson.D{{Key: "_id", Value: id}, notDeleted}, set, unset)
+}
+
+// Update changes the profile of a user who has not been deleted. It returns nil if there is
+// no such user, or if version is set and the user has moved past it.
+func (u *userRepository) Update(ctx context.Context, id primitive.ObjectID, in domains.UpdateUserRequest, version *int64) (*domains.User, error) {
+	set, unset := aliasChanges(in.Handle, in.Phone)
+	if in.Name != nil {
+		set = append(set, bson.E{Key: "name", Value: *in.Name}, bson.E{Key: "name_key", Value: domains.SearchKey(*in.Name)})
+	}
+	pset, punset := profileChanges(in.Profile, in.Attributes)
+	set, unset = append(set, pset...), append(unset, punset...)
+	filter := bson.D{{Key: "_id", Value: id}, notDeleted}
+	if version != nil {
+		filter = append(filter, bson.E{Key: "version", Value: *version})
+	}
+	return u.updateProfile(ctx, filter, set, unset)
+}
+
+// aliasChanges turns the aliases that are not nil into $set and $unset fields.
+func aliasChanges(handle, phone *string) (set, unset bson.D) {
+	set, unset = bson.D{}, bson.D{}
+	if handle != nil {
+		if *handle == "" {
+			unset = append(unset, bson.E{Key: "handle", Value: ""})
+		} else {
+			set = append(set, bson.E{Key: "handle", Value: *handle})
+		}
+	}
+	if phone != nil {
+		unset = append(unset, bson.E{Key: "phone_verified", Value: ""})
+		if *phone == "" {
+			unset = append(unset, bson.E{Key: "phone", Value: ""})
+		} else {
+			set = append(set, bson.E{Key: "phone", Value: *phone})
+		}
+	}
+	return set, unset
+}
 
-	filterTo := bson.D{{Key: "_id", Value: toID}}
-	updateTo := bson.D{{Key: "$inc", Value: bson.D{{Key: "balance", Value: amount}}}}
+// profileChanges turns a profile change and the attribute namespaces given into $set and
+// $unset fields. Empty values and nil namespaces are removed.
+func profileChanges(p *domains.ProfileUpdate, attrs domains.Attributes) (set, unset bson.D) {
+	set, unset = bson.D{}, bson.D{}
+	change := func(key string, remove bool, v interface{}) {
+		if remove {
+			unset = append(unset, bson.E{Key: key, Value: ""})
+		} else {
+			set = append(set, bson.E{Key: key, Value: v})
+		}
+	}
+	if p != nil {
+		if p.Locale != nil {
+			change("profile.locale", *p.Locale == "", *p.Locale)
+		}
+		if p.DateOfBirth != nil {
+			change("profile.date_of_birth", *p.DateOfBirth == "", *p.DateOfBirth)
+		}
+		if p.Address != nil {
+			change("profile.address", *p.Address == domains.Address{}, p.Address)
+		}
+		if m := p.Marketing; m != nil {
+			for _, c := range []struct {
+				key string
+				v   *bool
+			}{{"email", m.Email}, {"sms", m.SMS}, {"push", m.Push}} {
+				if c.v != nil {
+					change("profile.marketing."+c.key, false, *c.v)
+				}
+			}
+		}
+	}
+	for _, ns := range slices.Sorted(maps.Keys(attrs)) {
+		change("attributes."+ns, attrs[ns] == nil, attrs[ns])
+	}
+	return set, unset
+}
 
-	toUser, err := u.findOneAndUpdate(ctx, filterTo, updateTo)
+func (u *userRepository) updateProfile(ctx context.Context, filter, set, unset bson.D) (*domains.User, error) {
+	update, err := u.sealUpdate(set, unset)
 	if err != nil {
-		rollbackFilter := bson.D{{Key: "_id", Value: fromID}}
-		rollbackUpdate := bson.D{{Key: "$inc", Value: bson.D{{Key: "balance", Value: amount}}}}
-		u.findOneAndUpdate(ctx, rollbackFilter, rollbackUpdate)
+		return nil, err
+	}
+	if len(update) == 0 {
+		return u.findOne(ctx, filter)
+	}
+	out, err := u.findOneAndUpdate(ctx, filter, update)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, domains.Conflict("handle_taken", "handle is already taken")
+	}
+	return out, err
+}
 
-		return err
+// sealUpdate builds an update from $set and $unset fields, encrypting the personal ones
+// when encryption is on. Empty parts are left out.
+func (u *userRepository) sealUpdate(set, unset bson.D) (bson.D, error) {
+	if u.pii != nil {
+		var err error
+		if set, unset, err = u.pii.sealChanges(set, unset); err != nil {
+			return nil, err
+		}
+	}
+	update := bson.D{}
+	if len(set) > 0 {
+		update = append(update, bson.E{Key: "$set", Value: set})
+	}
+	if len(unset) > 0 {
+		update = append(update, bson.E{Key: "$unset", Value: unset})
 	}
-	if toUser == nil {
-		rollbackFilter := bson.D{{Key: "_id", Value: fromID}}
-		rollbackUpdate := bson.D{{Key: "$inc", Value: bson.D{{Key: "balance", Value: amount}}}}
-		u.findOneAndUpdate(ctx, rollbackFilter, rollbackUpdate)
+	return update, nil
+}
 
-		return err
+// SoftDelete marks a user deleted. It returns nil if there is no such user or they were
+// already deleted.
+func (u *userRepository) SoftDelete(ctx context.Context, id primitive.ObjectID, at time.Time) (*domains.User, error) {
+	return u.findOneAndUpdate(ctx,
+		bson.D{{Key: "_id", Value: id}, notDeleted},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "deleted_at", Value: at}}}},
+	)
+}
+
+// Restore undeletes a user, taking back their email if it was released. It returns nil if
+// there is no deleted user with id.
+func (u *userRepository) Restore(ctx context.Context, id primitive.ObjectID) (*domains.User, error) {
+	out, err := u.findOneAndUpdate(ctx,
+		bson.D{{Key: "_id", Value: id}, {Key: "deleted_at", Value: bson.D{{Key: "$exists", Value: true}}}, notErased},
+		mongo.Pipeline{
+			{{Key: "$set", Value: bson.D{
+				{Key: "email", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$deleted_email", "$email"}}}},
+				{Key: "email_bidx", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$deleted_email_bidx", "$email_bidx"}}}},
+			}}},
+			// An encrypted email has no plaintext search key.
+			{{Key: "$set", Value: bson.D{{Key: "email_key", Value: bson.D{{Key: "$cond", Value: bson.A{
+				bson.D{{Key: "$eq", Value: bson.A{bson.D{{Key: "$type", Value: "$email"}}, "string"}}},
+				bson.D{{Key: "$toLower", Value: "$email"}},
+				"$$REMOVE",
+			}}}}}}},
+			{{Key: "$unset", Value: bson.A{"deleted_at", "deleted_email", "deleted_email_bidx"}}},
+		},
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, domains.Conflict("email_taken", "the user's email has since been registered by another account")
 	}
+	return out, err
+}
 
-	return nil
+// ReleaseDeletedEmails swaps the email of users deleted before cutoff for a placeholder and
+// drops their handle and verified phone, so the addresses can be registered again.
+func (u *userRepository) ReleaseDeletedEmails(ctx context.Context, cutoff time.Time) (int64, error) {
+	col := u.mc.Database(u.db).Collection(u.col)
+	res, err := col.UpdateMany(ctx,
+		bson.D{
+			{Key: "deleted_at", Value: bson.D{{Key: "$lte", Value: cutoff}}},
+			{Key: "deleted_email", Value: bson.D{{Key: "$exists", Value: false}}},
+			notErased,
+		},
+		withVersion(mongo.Pipeline{
+			{{Key: "$set", Value: bson.D{
+				{Key: "deleted_email", Value: "$email"},
+				{Key: "deleted_email_bidx", Value: "$email_bidx"},
+				{Key: "email", Value: bson.D{{Key: "$concat", Value: bson.A{"deleted+", bson.D{{Key: "$toString", Value: "$_id"}}, "@users.invalid"}}}},
+			}}},
+			{{Key: "$set", Value: bson.D{{Key: "email_key", Value: "$email"}}}},
+			{{Key: "$unset", Value: bson.A{"handle", "phone_verified", "email_verified", "email_bidx"}}},
+		}),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+// ReencryptUsers encrypts up to limit users whose personal fields are in plaintext or
+// sealed under a retired key with the active one, returning how many were updated. The
+// content does not change, so neither does the version; a user written in the meantime is
+// skipped and picked up by the next run. It does nothing while encryption is off.
+func (u *userRepository) ReencryptUsers(ctx context.Context, limit int) (int, error) {
+	if u.pii == nil {
+		return 0, nil
+	}
+	col := u.mc.Database(u.db).Collection(u.col)
+	cursor, err := col.Find(ctx, u.pii.staleUsers(), options.Find().SetLimit(int64(limit)))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	updated := 0
+	for cursor.Next(ctx) {
+		var doc bson.D
+		if err := cursor.Decode(&doc); err != nil {
+			return updated, err
+		}
+		set, unset, err := u.pii.reseal(doc)
+		if err != nil {
+			return updated, err
+		}
+		id, _ := lookup(doc, "_id")
+		version, _ := lookup(doc, "version")
+		update := bson.D{{Key: "$set", Value: set}}
+		if len(unset) > 0 {
+			update = append(update, bson.E{Key: "$unset", Value: unset})
+		}
+		res, err := col.UpdateOne(ctx, bson.D{{Key: "_id", Value: id}, {Key: "version", Value: version}}, update)
+		if err != nil {
+			return updated, err
+		}
+		updated += int(res.ModifiedCount)
+	}
+	return updated, cursor.Err()
+}
+
+func (u *userRepository) SetVerified(ctx context.Context, id primitive.ObjectID, in domains.VerifyAliasesRequest) (*domains.User, error) {
+	set := bson.D{}
+	if in.Email != nil {
+		set = append(set, bson.E{Key: "email_verified", Value: *in.Email})
+	}
+	if in.Phone != nil {
+		set = append(set, bson.E{Key: "phone_verified", Value: *in.Phone})
+	}
+	if len(set) == 0 {
+		return u.GetByID(ctx, id)
+	}
+	out, err := u.findOneAndUpdate(ctx, bson.D{{Key: "_id", Value: id}}, bson.D{{Key: "$set", Value: set}})
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, domains.Conflict("phone_taken", "phone number is already verified on another account")
+	}
+	return out, err
+}
+
+func (u *userRepository) ExistingIDs(ctx context.Context, ids []primitive.ObjectID) ([]primitive.ObjectID, error) {
+	col := u.mc.Database(u.db).Collection(u.col)
+	opts := options.Find().SetProjection(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := col.Find(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}, notDeleted}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var out []primitive.ObjectID
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		out = append(out, doc.ID)
+	}
+	return out, cursor.Err()
+}
+
+func (u *userRepository) TransferWithTransaction(ctx context.Context, in domains.Transfer, limits domains.TransferLimits) (*domains.Transfer, error) {
+	db := u.mc.Database(u.db)
+	in.CreatedAt = time.Now().UTC()
+
+	var out *domains.Transfer
+	err := withTransaction(ctx, u.mc, func(sc mongo.SessionContext) error {
+		var err error
+		out, err = transfer(sc, db, in, limits)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
 func (u *userRepository) find(ctx context.Context, filter bson.D, opts *options.FindOptions) ([]domains.User, error) {
@@ -146,8 +768,13 @@ func (u *userRepository) findOne(ctx context.Context, filter bson.D) (*domains.U
 
 func (u *userRepository) insertOne(ctx context.Context, in domains.User) (*domains.User, error) {
 	in.CreatedAt = time.Now().UTC()
+	in.NameKey, in.EmailKey = domains.SearchKey(in.Name), domains.SearchKey(in.Email)
 	col := u.mc.Database(u.db).Collection(u.col)
 	result, err := col.InsertOne(ctx, in)
+	var writeErr mongo.WriteException
+	if errors.As(err, &writeErr) && len(writeErr.WriteErrors) > 0 && mongo.IsDuplicateKeyError(err) {
+		return nil, userConflict(writeErr.WriteErrors[0])
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -156,12 +783,43 @@ func (u *userRepository) insertOne(ctx context.Context, in domains.User) (*domai
 	return &in, nil
 }
 
-func (u *userRepository) findOneAndUpdate(ctx context.Context, filter bson.D, update bson.D) (*domains.User, error) {
+// userConflict reports a duplicate key error from inserting a user, telling which unique
+// index it hit by the index's key pattern, which the server sends with the write error.
+func userConflict(we mongo.WriteError) error {
+	switch duplicateKeyField(we) {
+	case "email", "email_bidx":
+		return domains.Conflict("email_taken", "email is already registered")
+	case "handle":
+		return domains.Conflict("handle_taken", "handle is already taken")
+	default:
+		return domains.Conflict("user_exists", "email or handle is already registered")
+	}
+}
+
+// duplicateKeyField returns the first field of the unique index a duplicate key error hit,
+// or "" if the server did not say.
+func duplicateKeyField(we mongo.WriteError) string {
+	pattern, err := we.Raw.LookupErr("keyPattern")
+	if err != nil {
+		return ""
+	}
+	doc, ok := pattern.DocumentOK()
+	if !ok {
+		return ""
+	}
+	fields, err := doc.Elements()
+	if err != nil || len(fields) == 0 {
+		return ""
+	}
+	return fields[0].Key()
+}
+
+func (u *userRepository) findOneAndUpdate(ctx context.Context, filter bson.D, update interface{}) (*domains.User, error) {
 	col := u.mc.Database(u.db).Collection(u.col)
 	var out = domains.User{}
 	opts := options.FindOneAndUpdate()
 	opts.SetReturnDocument(options.After)
-	err := col.FindOneAndUpdate(ctx, filter, update, opts).Decode(&out)
+	err := col.FindOneAndUpdate(ctx, filter, withVersion(update), opts).Decode(&out)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, nil
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// InsertMany inserts users without stopping at the first failure. The returned slice holds
+// each user's insert error, nil for those created; the error is set only if the batch as a
+// whole failed.
+func (u *userRepository) InsertMany(ctx context.Context, users []domains.User) ([]error, error) {
+	now := time.Now().UTC()
+	docs := make([]interface{}, len(users))
+	for i := range users {
+		users[i].CreatedAt = now
+		users[i].NameKey, users[i].EmailKey = domains.SearchKey(users[i].Name), domains.SearchKey(users[i].Email)
+		docs[i] = users[i]
+	}
+
+	failed := make([]error, len(users))
+	_, err := u.mc.Database(u.db).Collection(u.col).InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) && bulkErr.WriteConcernError == nil {
+		for _, we := range bulkErr.WriteErrors {
+			if we.Index < 0 || we.Index >= len(users) {
+				continue
+			}
+			if mongo.IsDuplicateKeyError(we) {
+				failed[we.Index] = userConflict(we.WriteError)
+			} else {
+				failed[we.Index] = errors.New(we.Message)
+			}
+		}
+		return failed, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return failed, nil
+}
+
+// FindExisting returns the users, deleted or not, holding any of emails (ignoring case)
+// or handles. Only their emails and handles are loaded.
+func (u *userRepository) FindExisting(ctx context.Context, emails, handles []string) ([]domains.User, error) {
+	projection := bson.D{{Key: "email", Value: 1}, {Key: "handle", Value: 1}}
+	var out []domains.User
+	if len(emails) > 0 {
+		filter, collation := u.emailFilter(emails...)
+		users, err := u.find(ctx, filter, options.Find().SetProjection(projection).SetCollation(collation))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, users...)
+	}
+	if len(handles) > 0 {
+		users, err := u.find(ctx, bson.D{{Key: "handle", Value: bson.D{{Key: "$in", Value: handles}}}},
+			options.Find().SetProjection(projection))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, users...)
+	}
+	return out, nil
+}
+
+// StreamUsers calls fn for each user matching q in _id order, without loading them all.
+func (u *userRepository) StreamUsers(ctx context.Context, q domains.UserQuery, fn func(domains.User) error) error {
+	filter, err := u.userFilter(q)
+	if err != nil {
+		return err
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetBatchSize(500)
+	cursor, err := u.mc.Database(u.db).Collection(u.col).Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var user domains.User
+		if err := cursor.Decode(&user); err != nil {
+			return err
+		}
+		if err := fn(user); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
@@ -0,0 +1,119 @@
+// Package logging is the one way the server writes logs. Everything goes through slog,
+// including the standard log package once New's logger is the default, and is redacted on
+// the way out: attributes under secret keys are replaced, structs and maps are walked for
+// secret fields, and email addresses, bearer tokens and phone numbers are masked in any
+// text.
+//
+// Code serving a request or running a job logs through FromContext, so its lines carry the
+// request ID or job name attached further up.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+type Options struct {
+	// Level is the least severe level written: debug, info, warn or error. Empty means info.
+	Level string
+	// Format is json or text. Empty means json.
+	Format string
+	// RedactKeys are the keys whose values are never written; see Redactor.
+	RedactKeys []string
+}
+
+// New returns a logger writing to w as opts describe. Values under opts.RedactKeys and
+// fields tagged `log:"redact"` are redacted.
+func New(w io.Writer, opts Options) (*slog.Logger, error) {
+	var level slog.Level
+	if opts.Level != "" {
+		if err := level.UnmarshalText([]byte(opts.Level)); err != nil {
+			return nil, fmt.Errorf("log level %q: %w", opts.Level, err)
+		}
+	}
+	ho := &slog.HandlerOptions{Level: level}
+	var inner slog.Handler
+	switch strings.ToLower(opts.Format) {
+	case "", "json":
+		inner = slog.NewJSONHandler(w, ho)
+	case "text":
+		inner = slog.NewTextHandler(w, ho)
+	default:
+		return nil, fmt.Errorf("log format %q: must be json or text", opts.Format)
+	}
+	return slog.New(NewHandler(inner, NewRedactor(opts.RedactKeys))), nil
+}
+
+type ctxKey struct{}
+
+// WithLogger returns a copy of ctx carrying l.
+func WithLogger(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext returns the logger ctx carries, or the default logger.
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+// NewHandler wraps inner so that nothing reaches it unredacted.
+func NewHandler(inner slog.Handler, r *Redactor) slog.Handler {
+	return &handler{inner: inner, r: r}
+}
+
+type handler struct {
+	inner slog.Handler
+	r     *Redactor
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *handler) Handle(ctx context.Context, rec slog.Record) error {
+	out := slog.NewRecord(rec.Time, rec.Level, h.r.Text(rec.Message), rec.PC)
+	rec.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(h.attr(a))
+		return true
+	})
+	return h.inner.Handle(ctx, out)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	safe := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		safe[i] = h.attr(a)
+	}
+	return &handler{inner: h.inner.WithAttrs(safe), r: h.r}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{inner: h.inner.WithGroup(name), r: h.r}
+}
+
+func (h *handler) attr(a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+	if a.Key != "" && h.r.Sensitive(a.Key) {
+		return slog.String(a.Key, Redacted)
+	}
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, h.r.Text(a.Value.String()))
+	case slog.KindGroup:
+		group := a.Value.Group()
+		safe := make([]interface{}, len(group))
+		for i, g := range group {
+			safe[i] = h.attr(g)
+		}
+		return slog.Group(a.Key, safe...)
+	case slog.KindAny:
+		return slog.Any(a.Key, h.r.Value(a.Value.Any()))
+	}
+	return a
+}
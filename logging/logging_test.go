@@ -0,0 +1,44 @@
+package logging_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/logging"
+)
+
+func TestNew(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, logging.Options{Level: "WARN", RedactKeys: redactKeys})
+	assert.NoError(t, err)
+
+	logger.Info("dropped")
+	logger.Warn("kept", "email", "somchai@example.com")
+
+	var line map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line), "json is the default format")
+	assert.Equal(t, "kept", line["msg"])
+	assert.Equal(t, logging.Redacted, line["email"])
+
+	_, err = logging.New(&buf, logging.Options{Level: "loud"})
+	assert.Error(t, err)
+	_, err = logging.New(&buf, logging.Options{Format: "xml"})
+	assert.Error(t, err)
+}
+
+func TestNew_RedactsStandardLog(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(newLogger(t, &buf, redactKeys))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	log.Printf("Transfer request: %+v", domains.TransferRequest{To: "somchai@example.com"})
+
+	assertNoSecrets(t, buf.String())
+	assert.Contains(t, buf.String(), "Transfer request")
+}
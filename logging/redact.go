@@ -0,0 +1,154 @@
+package logging
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces every value that must not be logged.
+const Redacted = "[REDACTED]"
+
+// maxDepth bounds how far Value walks into nested values.
+const maxDepth = 10
+
+// secretText matches secrets that can turn up inside free text: email addresses, bearer
+// tokens and phone numbers in international format, spaced or not.
+var secretText = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}|(?i:bearer)\s+[A-Za-z0-9\-._~+/]+=*|\+\d[\d \-()]{6,18}\d`)
+
+// Redactor decides which logged values are secret. A value is secret when its key, or the
+// JSON name of its struct field, ends with one of the configured keys, compared ignoring
+// case, underscores and dashes; so "email" also covers "deletedEmail" and "token" covers
+// "refresh_token". A struct field tagged `log:"redact"` is always secret.
+type Redactor struct {
+	keys []string
+}
+
+func NewRedactor(keys []string) *Redactor {
+	r := &Redactor{}
+	for _, k := range keys {
+		if k = normalizeKey(k); k != "" {
+			r.keys = append(r.keys, k)
+		}
+	}
+	return r
+}
+
+func normalizeKey(k string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(strings.TrimSpace(k)))
+}
+
+// Sensitive reports whether values logged under key are secret.
+func (r *Redactor) Sensitive(key string) bool {
+	key = normalizeKey(key)
+	for _, k := range r.keys {
+		if strings.HasSuffix(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// Text masks the email addresses, bearer tokens and phone numbers in s.
+func (r *Redactor) Text(s string) string {
+	return secretText.ReplaceAllString(s, Redacted)
+}
+
+// Value returns a copy of v that is safe to log. Structs become maps keyed by their JSON
+// field names and, like maps, have their secret entries replaced; strings and errors are
+// masked with Text.
+func (r *Redactor) Value(v interface{}) interface{} {
+	return r.value(reflect.ValueOf(v), 0)
+}
+
+var (
+	tError    = reflect.TypeOf((*error)(nil)).Elem()
+	tStringer = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+func (r *Redactor) value(v reflect.Value, depth int) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if depth > maxDepth {
+		return Redacted
+	}
+	if v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		if v.Type().Implements(tError) {
+			return r.Text(v.Interface().(error).Error())
+		}
+		return r.value(v.Elem(), depth)
+	}
+	if !v.CanInterface() {
+		return Redacted
+	}
+	if v.Type().Implements(tError) {
+		return r.Text(v.Interface().(error).Error())
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return r.Text(v.String())
+	case reflect.Struct:
+		t := v.Type()
+		out := map[string]interface{}{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			if f.Tag.Get("log") == "redact" || r.Sensitive(name) {
+				out[name] = Redacted
+			} else {
+				out[name] = r.value(v.Field(i), depth+1)
+			}
+		}
+		if len(out) == 0 {
+			// Opaque values such as times.
+			return v.Interface()
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			if r.Sensitive(key) {
+				out[r.Text(key)] = Redacted
+			} else {
+				out[r.Text(key)] = r.value(iter.Value(), depth+1)
+			}
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if v.Type().Implements(tStringer) {
+			// IDs and the like.
+			return r.Text(v.Interface().(fmt.Stringer).String())
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return fmt.Sprintf("[%d bytes]", v.Len())
+		}
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			out[i] = r.value(v.Index(i), depth+1)
+		}
+		return out
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return v.Type().String()
+	}
+	return v.Interface()
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/logging"
+)
+
+const problemContentType = "application/problem+json"
+
+var problemStatus = []struct {
+	kind   error
+	status int
+}{
+	{domains.ErrNotFound, http.StatusNotFound},
+	{domains.ErrValidation, http.StatusBadRequest},
+	{domains.ErrConflict, http.StatusConflict},
+	{domains.ErrInsufficientFunds, http.StatusUnprocessableEntity},
+	{domains.ErrLimitExceeded, http.StatusUnprocessableEntity},
+	{domains.ErrUnauthorized, http.StatusUnauthorized},
+	{domains.ErrForbidden, http.StatusForbidden},
+	{domains.ErrRateLimited, http.StatusTooManyRequests},
+	{domains.ErrStale, http.StatusPreconditionFailed},
+	{domains.ErrUnavailable, http.StatusServiceUnavailable},
+}
+
+// ErrorHandler turns the last error a handler recorded with c.Error into an RFC 7807
+// problem response. Errors that are not domain errors are logged and reported as a bare
+// 500 so internal details never reach the client.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 {
+			return
+		}
+		err := c.Errors.Last().Err
+		if c.Writer.Written() {
+			// Too late to send a problem; a streamed body was cut short.
+			logging.FromContext(c.Request.Context()).Error("[HTTP] failed after the response started", "method", c.Request.Method, "route", route(c), "error", err)
+			return
+		}
+
+		problem := problemFor(c, err)
+		c.Header("Content-Type", problemContentType)
+		c.JSON(problem["status"].(int), problem)
+	}
+}
+
+// problemFor describes err as an RFC 7807 problem document.
+func problemFor(c *gin.Context, err error) gin.H {
+	status, code, detail := http.StatusInternalServerError, "internal_error", "an unexpected error occurred"
+	var fields []domains.FieldError
+	extra := gin.H{}
+
+	var derr *domains.Error
+	var limitErr *domains.LimitExceededError
+	var batchErr *domains.BatchValidationError
+	switch {
+	case errors.As(err, &limitErr):
+		status, code, detail = http.StatusUnprocessableEntity, "limit_exceeded", limitErr.Error()
+		extra["limit"], extra["max"] = limitErr.Limit, limitErr.Max
+		if !limitErr.ResetsAt.IsZero() {
+			extra["resetsAt"] = limitErr.ResetsAt
+		}
+	case errors.As(err, &batchErr):
+		status, code, detail = http.StatusBadRequest, "invalid_batch", "one or more batch lines are invalid"
+		for _, l := range batchErr.Lines {
+			fields = append(fields, domains.FieldError{Field: fmt.Sprintf("lines[%d]", l.Line-1), Code: "invalid_line", Message: l.Error})
+		}
+	case errors.As(err, &derr):
+		code, detail, fields = derr.Code, derr.Message, derr.Fields
+		for _, p := range problemStatus {
+			if errors.Is(derr, p.kind) {
+				status = p.status
+				break
+			}
+		}
+	default:
+		logging.FromContext(c.Request.Context()).Error("[HTTP] unexpected error", "method", c.Request.Method, "route", route(c), "error", err)
+	}
+
+	problem := gin.H{
+		"type":     "/problems/" + code,
+		"title":    http.StatusText(status),
+		"status":   status,
+		"detail":   detail,
+		"code":     code,
+		"instance": c.Request.URL.Path,
+	}
+	if len(fields) > 0 {
+		problem["errors"] = fields
+	}
+	for k, v := range extra {
+		problem[k] = v
+	}
+	return problem
+}
+
+// Fail records err for ErrorHandler and stops the chain.
+func Fail(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
@@ -1,14 +1,14 @@
 package middleware
 
 import (
-	"log"
-	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt"
 	"github.com/spf13/viper"
 	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+	"github.com/wansanjou/backend-exercise-user-api/logging"
 )
 
 func AuthenMiddleware() gin.HandlerFunc {
@@ -16,20 +16,14 @@ func AuthenMiddleware() gin.HandlerFunc {
 		jwtKey := []byte(viper.GetString("jwt.secretKey"))
 		auth := c.Request.Header.Get("Authorization")
 		if len(auth) < 1 {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"message": "token not found",
-			})
-			c.Abort()
+			Fail(c, domains.Unauthorized("token_missing", "token not found"))
 			return
 		}
 
 		authPrefix := "Bearer"
 		i := len(authPrefix)
 		if len(auth) <= i || string(auth[:i]) != authPrefix {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"message": "Invalid found",
-			})
-			c.Abort()
+			Fail(c, domains.Unauthorized("token_invalid", "authorization header must be a bearer token"))
 			return
 		}
 		claims := &domains.JWTClaims{}
@@ -39,8 +33,7 @@ func AuthenMiddleware() gin.HandlerFunc {
 		})
 
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, err.Error())
-			c.Abort()
+			Fail(c, domains.Unauthorized("token_invalid", "token is invalid or has expired"))
 			return
 		}
 
@@ -50,16 +43,71 @@ func AuthenMiddleware() gin.HandlerFunc {
 	}
 }
 
+// RequireRole must run after AuthenMiddleware. It rejects callers whose role is not listed.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := c.Get("user")
+		if ok {
+			for _, role := range roles {
+				if claims.(*domains.JWTClaims).Role == role {
+					c.Next()
+					return
+				}
+			}
+		}
+		Fail(c, domains.Forbidden("forbidden"))
+	}
+}
+
+// LoggingMiddleware writes an access log line for each request. It logs the matched route
+// rather than the path, so IDs and aliases in the URL stay out of the log, and only the
+// values of the query parameters in loggedQuery; see safeQuery.
 func LoggingMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
-		method := c.Request.Method
-		path := c.Request.URL.Path
 
 		c.Next()
 
-		duration := time.Since(start)
+		attrs := []interface{}{
+			"method", c.Request.Method,
+			"route", route(c),
+			"status", c.Writer.Status(),
+			"duration", time.Since(start),
+		}
+		if q := c.Request.URL.Query(); len(q) > 0 {
+			attrs = append(attrs, "query", safeQuery(q))
+		}
+		logging.FromContext(c.Request.Context()).Info("[HTTP]", attrs...)
+	}
+}
+
+// loggedQuery are the query parameters whose values say nothing about anyone. Others,
+// such as ?alias=, ?name=, ?search=, ?attr[...]= and ?cursor=, can carry personal data
+// under keys no redact key would catch.
+var loggedQuery = map[string]bool{
+	"limit": true, "sort": true, "status": true, "total": true, "includeDeleted": true,
+	"createdFrom": true, "createdTo": true, "from": true, "to": true,
+	"format": true, "fields": true, "dryRun": true,
+}
+
+// safeQuery returns q with the values of parameters not in loggedQuery redacted. The
+// parameters themselves are kept, to show which filters a request used.
+func safeQuery(q url.Values) map[string][]string {
+	out := make(map[string][]string, len(q))
+	for k, v := range q {
+		if loggedQuery[k] {
+			out[k] = v
+		} else {
+			out[k] = []string{logging.Redacted}
+		}
+	}
+	return out
+}
 
-		log.Printf("[HTTP] %s %s %v", method, path, duration)
+// route is the route template c matched, such as /api/v1/users/:id.
+func route(c *gin.Context) string {
+	if r := c.FullPath(); r != "" {
+		return r
 	}
+	return "(no route)"
 }
@@ -0,0 +1,94 @@
+package middleware_test
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/wansanjou/backend-exercise-user-api/config"
+	"github.com/wansanjou/backend-exercise-user-api/logging"
+	"github.com/wansanjou/backend-exercise-user-api/middleware"
+)
+
+// secrets are the values the tests log; none of them may show up in any output.
+var secrets = []string{"somchai@example.com", "eyJhbGciOi.payload.sig", "+66812345678", "66812345678", "Somchai", "gold"}
+
+func assertNoSecrets(t *testing.T, out string) {
+	t.Helper()
+	for _, s := range secrets {
+		assert.NotContains(t, out, s)
+	}
+}
+
+// logTo makes a logger redacting keys and writing text to buf the default for the rest of
+// the test.
+func logTo(t *testing.T, buf *bytes.Buffer, keys []string) {
+	t.Helper()
+	logger, err := logging.New(buf, logging.Options{Level: "debug", Format: "text", RedactKeys: keys})
+	assert.NoError(t, err)
+	prev := slog.Default()
+	slog.SetDefault(logger)
+	t.Cleanup(func() { slog.SetDefault(prev) })
+}
+
+// shippedRedactKeys reads the redact keys from the config.yaml the server ships with.
+func shippedRedactKeys(t *testing.T) []string {
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.NoError(t, os.Chdir(".."))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	config.Init()
+	return config.Get().Logging.RedactKeys
+}
+
+func TestLoggingMiddleware_LogsRouteNotPath(t *testing.T) {
+	var buf bytes.Buffer
+	logTo(t, &buf, []string{"email", "token"})
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.LoggingMiddleware(), middleware.ErrorHandler())
+	r.GET("/users/:id", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	for _, target := range []string{
+		"/users/somchai@example.com?email=somchai@example.com&token=eyJhbGciOi.payload.sig&limit=5",
+		"/unknown/+66812345678",
+	} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	out := buf.String()
+	assertNoSecrets(t, out)
+	assert.Contains(t, out, "route=/users/:id")
+	assert.Contains(t, out, "limit:[5]")
+	assert.Contains(t, out, "route=\"(no route)\"")
+}
+
+func TestLoggingMiddleware_ShippedConfig(t *testing.T) {
+	var buf bytes.Buffer
+	logTo(t, &buf, shippedRedactKeys(t))
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.LoggingMiddleware(), middleware.ErrorHandler())
+	r.GET("/recipients", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	r.GET("/users", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	for _, target := range []string{
+		"/recipients?alias=+66812345678",
+		"/recipients?alias=%2B66812345678",
+		"/users?name=Somchai&search=Somchai&attr[loyalty.tier]=gold&email=somchai@example.com&status=active&limit=5",
+		"/users?somchai@example.com=1",
+	} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	out := buf.String()
+	assertNoSecrets(t, out)
+	assert.Contains(t, out, "alias:[[REDACTED]]", "the parameter is kept, its value is not")
+	assert.Contains(t, out, "status:[active]")
+	assert.Contains(t, out, "limit:[5]")
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wansanjou/backend-exercise-user-api/internal/core/domains"
+)
+
+type window struct {
+	start time.Time
+	count int
+}
+
+// Limiter allows each key at most limit requests per window. Counts are kept in memory, so
+// every instance of the API enforces the limit on its own: behind n replicas a key gets up
+// to n times the limit, and a restart forgets every count.
+type Limiter struct {
+	limit int
+	per   time.Duration
+
+	mu        sync.Mutex
+	windows   map[string]*window
+	lastSweep time.Time
+}
+
+func NewLimiter(limit int, per time.Duration) *Limiter {
+	return &Limiter{limit: limit, per: per, windows: map[string]*window{}, lastSweep: time.Now()}
+}
+
+// Allow counts one request for key. Beyond the limit it sets Retry-After on c and returns
+// the rate-limited error to report.
+func (l *Limiter) Allow(c *gin.Context, key string) error {
+	now := time.Now()
+
+	l.mu.Lock()
+	if now.Sub(l.lastSweep) > l.per {
+		for k, w := range l.windows {
+			if now.Sub(w.start) >= l.per {
+				delete(l.windows, k)
+			}
+		}
+		l.lastSweep = now
+	}
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= l.per {
+		w = &window{start: now}
+		l.windows[key] = w
+	}
+	w.count++
+	count, resetsAt := w.count, w.start.Add(l.per)
+	l.mu.Unlock()
+
+	if count > l.limit {
+		c.Header("Retry-After", strconv.Itoa(int(time.Until(resetsAt).Seconds())+1))
+		return domains.NewError(domains.ErrRateLimited, "rate_limited", "too many requests, try again after "+resetsAt.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Handler limits every request by the key it derives, answering 429 beyond the limit.
+func (l *Limiter) Handler(key func(c *gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := l.Allow(c, key(c)); err != nil {
+			Fail(c, err)
+			return
+		}
+		c.Next()
+	}
+}
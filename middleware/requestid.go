@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wansanjou/backend-exercise-user-api/logging"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDPattern is what an incoming request ID must look like to be kept. Anything
+// else, which could forge log lines or smuggle personal data into them, is replaced.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:\-]{1,128}$`)
+
+// RequestID tags each request with an ID: the X-Request-ID it came with, if usable, or a
+// new one. The ID is sent back in X-Request-ID, kept under "requestId", and attached to
+// the logger in the request's context, so everything logged while serving it carries the
+// ID. The engine must have ContextWithFallback set for services to see that logger
+// through the gin.Context handlers pass them.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(id) {
+			id = newRequestID()
+		}
+		c.Set("requestId", id)
+		c.Header(RequestIDHeader, id)
+		ctx := c.Request.Context()
+		c.Request = c.Request.WithContext(logging.WithLogger(ctx, logging.FromContext(ctx).With("requestId", id)))
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,51 @@
+package middleware_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/wansanjou/backend-exercise-user-api/logging"
+	"github.com/wansanjou/backend-exercise-user-api/middleware"
+)
+
+func TestRequestID_SharedByRequestLogs(t *testing.T) {
+	var buf bytes.Buffer
+	logTo(t, &buf, nil)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.ContextWithFallback = true
+	r.Use(middleware.RequestID(), middleware.LoggingMiddleware())
+	// Handlers hand their gin.Context to services, which log through it.
+	r.GET("/ping", func(c *gin.Context) {
+		func(ctx context.Context) { logging.FromContext(ctx).Info("from a service") }(c)
+		c.Status(http.StatusNoContent)
+	})
+	get := func(id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		if id != "" {
+			req.Header.Set(middleware.RequestIDHeader, id)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("req-42")
+	assert.Equal(t, "req-42", w.Header().Get(middleware.RequestIDHeader))
+	assert.Equal(t, 2, strings.Count(buf.String(), "requestId=req-42"), "service and access log lines")
+
+	buf.Reset()
+	w = get("somchai@example.com\nlevel=ERROR")
+	id := w.Header().Get(middleware.RequestIDHeader)
+	assert.Len(t, id, 32, "an unusable ID is replaced")
+	assert.Equal(t, 2, strings.Count(buf.String(), "requestId="+id))
+	assertNoSecrets(t, buf.String())
+
+	assert.NotEqual(t, get("").Header().Get(middleware.RequestIDHeader), get("").Header().Get(middleware.RequestIDHeader))
+}
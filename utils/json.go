@@ -0,0 +1,18 @@
+package utils
+
+import "strings"
+
+// unknownFieldPrefix starts the error a json.Decoder with DisallowUnknownFields returns for
+// a field the target does not have. encoding/json has no error type for this case
+// (golang/go#29035), so its message is the only way to tell it apart; the import tests
+// check the message still has this form.
+const unknownFieldPrefix = "json: unknown field "
+
+// UnknownJSONField returns the field named by err if it reports an unknown field.
+func UnknownJSONField(err error) (string, bool) {
+	msg := err.Error()
+	if !strings.HasPrefix(msg, unknownFieldPrefix) {
+		return "", false
+	}
+	return strings.Trim(strings.TrimPrefix(msg, unknownFieldPrefix), `"`), true
+}